@@ -4,9 +4,7 @@ import (
 	"context"
 	"fmt"
 	"log"
-	"os"
 	"strconv"
-	"time"
 
 	"github.com/spf13/cobra"
 )
@@ -15,13 +13,17 @@ var addCmd = &cobra.Command{
 	Use:   "add weight float [date]",
 	Short: "Add a weight entry to database",
 	Long: `Adds a weight entry to the database.
-The default date that is associated with the entry is current date.
+The default date that is associated with the entry is the current date and time.
 If optional date is provided, that date will be associated with this entry,
-instead of the default.
+instead of the default. Use --time to record the time of day of the weigh-in;
+a date given without --time is recorded at midnight, and a time given without
+--date applies to today.
 
 Examples:
   weight-tracker add 75.5
   weight-tracker add 75.5 --date 15-09-2024
+  weight-tracker add 75.5 --time 07:30
+  weight-tracker add 75.5 --date 15-09-2024 --time 21:15
   weight-tracker add 165.3 --unit lbs --note "After workout"
   weight-tracker add 75.5 --date 15-09-2024 --unit kg --note "Morning weight"`,
 	Run: runAdd,
@@ -30,18 +32,27 @@ Examples:
 var date string
 var unit string
 var note string
+var entryTime string
 
 func init() {
 	// Persistent flags to be inherited for the 'add' command
-	addCmd.Flags().StringVarP(&date, "date", "d", "", "The date of the weight entry (format configurable via DATE_INPUT_FORMAT)")
+	addCmd.Flags().StringVarP(&date, "date", "d", "", "The date of the weight entry (DATE_INPUT_FORMAT or relative: today, -7d, last monday, this month, 2025-W10)")
 	addCmd.Flags().StringVarP(&unit, "unit", "u", "", "The unit of measurement (kg, lbs) - default configurable via DEFAULT_UNIT")
 	addCmd.Flags().StringVarP(&note, "note", "n", "", "A note for the weight entry")
+	addCmd.Flags().StringVarP(&entryTime, "time", "t", "", "The time of day of the weight entry (HH:MM or HH:MM:SS)")
 }
 
 // runAddInternal contains the core logic and returns errors instead of terminating
 func runAddInternal(cmd *cobra.Command, args []string) error {
+	renderer, err := rendererFor(cmd)
+	if err != nil {
+		return err
+	}
+
+	app := appFor(cmd)
+
 	// Create store instance
-	store, err := NewDBStore()
+	store, err := app.NewStore()
 	if err != nil {
 		return fmt.Errorf("could not create store: %w", err)
 	}
@@ -60,23 +71,36 @@ func runAddInternal(cmd *cobra.Command, args []string) error {
 	// Create WeightEntry struct
 	entry := WeightEntry{
 		Weight: weightValue,
-		Date:   time.Now(),       // Default to current time
-		Unit:   GetDefaultUnit(), // Default unit from configuration
+		Date:   app.Now().In(GetLocationFromEnv(app.Getenv)), // Default to current time, in the configured time zone
+		Unit:   GetDefaultUnitFromEnv(app.Getenv),            // Default unit from configuration
+		UserID: resolveUser(cmd),                             // Selected user, if any
 	}
 
 	// Handle date flag
 	if cmd.Flags().Changed("date") {
 		dateStr, _ := cmd.Flags().GetString("date")
 		if dateStr != "" {
-			// Parse date using configured format
-			parsedDate, err := ParseDate(dateStr)
+			// Parse date using configured format or a relative expression
+			parsedDate, err := ParseDateAt(dateStr, app.Now(), app.Getenv)
 			if err != nil {
-				return fmt.Errorf("invalid date format '%s': use %s format", dateStr, GetInputFormatDescription())
+				return fmt.Errorf("invalid date format '%s': %s", dateStr, dateInputHint(app.Getenv))
 			}
 			entry.Date = parsedDate
 		}
 	}
 
+	// Handle time flag
+	if cmd.Flags().Changed("time") {
+		timeStr, _ := cmd.Flags().GetString("time")
+		if timeStr != "" {
+			timeOfDay, err := ParseTimeOfDay(timeStr)
+			if err != nil {
+				return err
+			}
+			entry.Date = WithTimeOfDay(entry.Date, timeOfDay)
+		}
+	}
+
 	// Handle unit flag
 	if cmd.Flags().Changed("unit") {
 		unitStr, _ := cmd.Flags().GetString("unit")
@@ -106,15 +130,12 @@ func runAddInternal(cmd *cobra.Command, args []string) error {
 
 	// Success - log and print result
 	log.Printf("`add` called with args: %v", args)
-	printWeightEntry(addedEntry)
-
-	return nil
+	return renderer.Render(addedEntry)
 }
 
 // runAdd is the cobra command wrapper that handles errors appropriately for CLI usage
 func runAdd(cmd *cobra.Command, args []string) {
 	if err := runAddInternal(cmd, args); err != nil {
-		fmt.Fprintf(cmd.ErrOrStderr(), "Error: %v\n", err)
-		os.Exit(1)
+		exitWithError(cmd, err)
 	}
 }
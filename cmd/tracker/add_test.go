@@ -527,7 +527,7 @@ func TestAddCommand_Configuration(t *testing.T) {
 			}
 
 			// Create store with test database
-			store := NewDBStoreWithDB(db)
+			store := NewDBStoreWithDB(db, time.UTC)
 
 			// Test the add command logic
 			weightValue, err := strconv.ParseFloat(tt.args[0], 64)
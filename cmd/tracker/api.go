@@ -0,0 +1,545 @@
+package tracker
+
+// api.go - REST API exposing the Store over HTTP
+// Related files: serve.go (serve command), dashboard.go (serve --ui), stats.go (buildStatistics), api_test.go (tests)
+// Routes:
+//   POST   /entries        add an entry
+//   GET    /entries        list entries (ListOptions as query parameters)
+//   GET    /entries/{id}   get an entry
+//   PUT    /entries/{id}   replace an entry
+//   PATCH  /entries/{id}   update some fields of an entry
+//   DELETE /entries/{id}   delete an entry
+//   GET    /stats          statistics, trend and goal progress
+// Errors are returned as {"error": "..."} with a matching status code.
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"mime"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// maxRequestBodyBytes bounds the size of request bodies
+const maxRequestBodyBytes = 1 << 20
+
+// apiDateLayouts are the date formats accepted by the API, tried in order
+// before the configured input format
+var apiDateLayouts = []string{time.RFC3339, DBFormat, DBLegacyFormat, "2006-01-02T15:04", DBDateOnlyFormat}
+
+// entryRequest is the JSON body of POST, PUT and PATCH requests. Fields are
+// pointers so PATCH can tell omitted fields from empty ones.
+type entryRequest struct {
+	Weight *float64 `json:"weight"`
+	Date   *string  `json:"date"`
+	Unit   *string  `json:"unit"`
+	Note   *string  `json:"note"`
+	UserID *string  `json:"user_id"`
+}
+
+// apiError is an error with the HTTP status code it is reported with
+type apiError struct {
+	status int
+	err    error
+}
+
+func (e *apiError) Error() string {
+	return e.err.Error()
+}
+
+func (e *apiError) Unwrap() error {
+	return e.err
+}
+
+// badRequest wraps a client error
+func badRequest(format string, args ...any) error {
+	return &apiError{status: http.StatusBadRequest, err: fmt.Errorf(format, args...)}
+}
+
+// apiServer serves the REST API from a Store, taking its configuration and clock
+// from the App
+type apiServer struct {
+	app   *App
+	store Store
+}
+
+// NewAPIHandler returns an http.Handler serving the REST API backed by store,
+// configured by app
+func NewAPIHandler(app *App, store Store) http.Handler {
+	return newAPIMux(&apiServer{app: app, store: store}, false)
+}
+
+// NewDashboardHandler returns an http.Handler serving the REST API together with
+// the embedded web dashboard and its chart endpoint (see dashboard.go)
+func NewDashboardHandler(app *App, store Store) http.Handler {
+	return newAPIMux(&apiServer{app: app, store: store}, true)
+}
+
+// newAPIMux registers the API routes, and the dashboard routes when ui is set
+func newAPIMux(server *apiServer, ui bool) *http.ServeMux {
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /entries", server.handle(server.createEntry))
+	mux.HandleFunc("GET /entries", server.handle(server.listEntries))
+	mux.HandleFunc("GET /entries/{id}", server.handle(server.getEntry))
+	mux.HandleFunc("PUT /entries/{id}", server.handle(server.replaceEntry))
+	mux.HandleFunc("PATCH /entries/{id}", server.handle(server.patchEntry))
+	mux.HandleFunc("DELETE /entries/{id}", server.handle(server.deleteEntry))
+	mux.HandleFunc("GET /stats", server.handle(server.stats))
+
+	if ui {
+		registerDashboardRoutes(mux, server)
+	}
+
+	// Unknown routes and methods get a JSON error like every other failure
+	mux.HandleFunc("/entries", server.handle(methodNotAllowed("GET, POST")))
+	mux.HandleFunc("/entries/{id}", server.handle(methodNotAllowed("GET, PUT, PATCH, DELETE")))
+	mux.HandleFunc("/stats", server.handle(methodNotAllowed("GET")))
+	mux.HandleFunc("/", server.handle(func(w http.ResponseWriter, r *http.Request) error {
+		return &apiError{status: http.StatusNotFound, err: fmt.Errorf("no route for %s %s", r.Method, r.URL.Path)}
+	}))
+
+	return mux
+}
+
+// methodNotAllowed returns a handler rejecting methods a route does not support
+func methodNotAllowed(allowed string) func(http.ResponseWriter, *http.Request) error {
+	return func(w http.ResponseWriter, r *http.Request) error {
+		w.Header().Set("Allow", allowed)
+		return &apiError{status: http.StatusMethodNotAllowed, err: fmt.Errorf("method %s not allowed on %s", r.Method, r.URL.Path)}
+	}
+}
+
+// handle adapts a handler returning an error into an http.HandlerFunc that
+// reports errors as JSON
+func (s *apiServer) handle(h func(http.ResponseWriter, *http.Request) error) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if err := h(w, r); err != nil {
+			writeAPIError(w, err)
+		}
+	}
+}
+
+// writeJSON writes v as a JSON response with the given status code
+func writeJSON(w http.ResponseWriter, status int, v any) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	return json.NewEncoder(w).Encode(v)
+}
+
+// writeAPIError writes err as a JSON error response, choosing the status code from the error
+func writeAPIError(w http.ResponseWriter, err error) {
+	status := http.StatusInternalServerError
+	var apiErr *apiError
+	switch {
+	case errors.As(err, &apiErr):
+		status = apiErr.status
+	case errors.Is(err, ErrEntryNotFound), errors.Is(err, ErrGoalNotFound):
+		status = http.StatusNotFound
+	}
+	writeJSON(w, status, errorResult{Error: err.Error()})
+}
+
+// createEntry handles POST /entries
+func (s *apiServer) createEntry(w http.ResponseWriter, r *http.Request) error {
+	if err := requireJSON(r); err != nil {
+		return err
+	}
+
+	var req entryRequest
+	if err := decodeEntryRequest(r, &req); err != nil {
+		return err
+	}
+	if req.Weight == nil {
+		return badRequest("weight is required")
+	}
+
+	entry := WeightEntry{
+		Date: s.app.Now(),
+		Unit: GetDefaultUnitFromEnv(s.app.Getenv),
+	}
+	if err := s.applyEntryRequest(&entry, req); err != nil {
+		return err
+	}
+	if err := ValidateWeightEntry(entry); err != nil {
+		return badRequest("validation failed: %v", err)
+	}
+
+	added, err := s.store.AddWeight(r.Context(), entry)
+	if err != nil {
+		return fmt.Errorf("failed to add weight entry: %w", err)
+	}
+
+	w.Header().Set("Location", fmt.Sprintf("/entries/%d", added.ID))
+	return writeJSON(w, http.StatusCreated, added)
+}
+
+// listEntries handles GET /entries
+func (s *apiServer) listEntries(w http.ResponseWriter, r *http.Request) error {
+	options, err := s.listOptionsFromQuery(r)
+	if err != nil {
+		return err
+	}
+
+	entries, err := s.store.ListWeights(r.Context(), options)
+	if err != nil {
+		if options.AfterID != 0 && errors.Is(err, ErrEntryNotFound) {
+			// A stale cursor is a bad request, not a missing resource
+			return badRequest("%v", err)
+		}
+		if errors.Is(err, ErrInvalidNoteQuery) {
+			return badRequest("%v", err)
+		}
+		return fmt.Errorf("failed to list weights: %w", err)
+	}
+
+	if unit := r.URL.Query().Get("display_unit"); unit != "" {
+		if !IsValidUnit(unit) {
+			return badRequest("invalid display_unit '%s': must be 'kg' or 'lbs'", unit)
+		}
+		entries, err = NormalizeEntries(entries, unit)
+		if err != nil {
+			return fmt.Errorf("failed to convert weights to %s: %w", unit, err)
+		}
+	}
+
+	// Always return an array, never null
+	if entries == nil {
+		entries = []WeightEntry{}
+	}
+	return writeJSON(w, http.StatusOK, entries)
+}
+
+// getEntry handles GET /entries/{id}
+func (s *apiServer) getEntry(w http.ResponseWriter, r *http.Request) error {
+	entry, err := s.lookupEntry(r)
+	if err != nil {
+		return err
+	}
+	return writeJSON(w, http.StatusOK, entry)
+}
+
+// replaceEntry handles PUT /entries/{id}: weight and date are required, omitted
+// unit falls back to the default unit
+func (s *apiServer) replaceEntry(w http.ResponseWriter, r *http.Request) error {
+	if err := requireJSON(r); err != nil {
+		return err
+	}
+
+	existing, err := s.lookupEntry(r)
+	if err != nil {
+		return err
+	}
+
+	var req entryRequest
+	if err := decodeEntryRequest(r, &req); err != nil {
+		return err
+	}
+	if req.Weight == nil || req.Date == nil {
+		return badRequest("weight and date are required, use PATCH to update some fields")
+	}
+
+	entry := WeightEntry{ID: existing.ID, Unit: GetDefaultUnitFromEnv(s.app.Getenv), UserID: existing.UserID}
+	if err := s.applyEntryRequest(&entry, req); err != nil {
+		return err
+	}
+	return s.saveEntry(w, r, entry)
+}
+
+// patchEntry handles PATCH /entries/{id}: only fields present in the body are changed
+func (s *apiServer) patchEntry(w http.ResponseWriter, r *http.Request) error {
+	if err := requireJSON(r); err != nil {
+		return err
+	}
+
+	entry, err := s.lookupEntry(r)
+	if err != nil {
+		return err
+	}
+
+	var req entryRequest
+	if err := decodeEntryRequest(r, &req); err != nil {
+		return err
+	}
+	if req == (entryRequest{}) {
+		return badRequest("no fields to update: use weight, date, unit, note or user_id")
+	}
+
+	if err := s.applyEntryRequest(&entry, req); err != nil {
+		return err
+	}
+	return s.saveEntry(w, r, entry)
+}
+
+// deleteEntry handles DELETE /entries/{id}
+func (s *apiServer) deleteEntry(w http.ResponseWriter, r *http.Request) error {
+	entry, err := s.lookupEntry(r)
+	if err != nil {
+		return err
+	}
+
+	if err := s.store.DeleteWeight(r.Context(), entry.ID); err != nil {
+		return fmt.Errorf("failed to delete weight entry: %w", err)
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+	return nil
+}
+
+// stats handles GET /stats
+func (s *apiServer) stats(w http.ResponseWriter, r *http.Request) error {
+	query := r.URL.Query()
+
+	unit, err := resolveDisplayUnitFromEnv(query.Get("display_unit"), s.app.Getenv)
+	if err != nil {
+		return badRequest("%v", err)
+	}
+
+	fromDate, toDate, err := s.dateRangeFromQuery(r)
+	if err != nil {
+		return err
+	}
+
+	trendOptions, err := trendOptionsFromQuery(r)
+	if err != nil {
+		return err
+	}
+
+	options := ListOptions{FromDate: fromDate, ToDate: toDate, UserID: query.Get("user")}
+	stats, err := buildStatistics(r.Context(), s.store, options, unit, trendOptions)
+	if err != nil {
+		return err
+	}
+	return writeJSON(w, http.StatusOK, stats)
+}
+
+// lookupEntry returns the entry named by the {id} path value. With a user query
+// parameter, entries of other users are reported as forbidden.
+func (s *apiServer) lookupEntry(r *http.Request) (WeightEntry, error) {
+	idStr := r.PathValue("id")
+	id, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil || id <= 0 {
+		return WeightEntry{}, badRequest("invalid ID '%s': must be a positive number", idStr)
+	}
+
+	entry, err := s.store.GetWeight(r.Context(), id)
+	if err != nil {
+		return WeightEntry{}, err
+	}
+
+	if err := checkEntryOwner(entry, r.URL.Query().Get("user")); err != nil {
+		return WeightEntry{}, &apiError{status: http.StatusForbidden, err: err}
+	}
+	return entry, nil
+}
+
+// saveEntry validates and stores every field of an updated entry, so that omitted
+// (PUT) or emptied (PATCH) fields are cleared, and writes it as the response
+func (s *apiServer) saveEntry(w http.ResponseWriter, r *http.Request, entry WeightEntry) error {
+	if err := ValidateWeightEntry(entry); err != nil {
+		return badRequest("validation failed: %v", err)
+	}
+
+	updated, err := s.store.ReplaceWeight(r.Context(), entry)
+	if err != nil {
+		return fmt.Errorf("failed to update weight entry: %w", err)
+	}
+	return writeJSON(w, http.StatusOK, updated)
+}
+
+// requireJSON rejects request bodies that are not declared as JSON
+func requireJSON(r *http.Request) error {
+	mediaType, _, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if err != nil || mediaType != "application/json" {
+		return &apiError{status: http.StatusUnsupportedMediaType, err: fmt.Errorf("unsupported content type '%s': use application/json", r.Header.Get("Content-Type"))}
+	}
+	return nil
+}
+
+// decodeEntryRequest decodes a JSON entry request body, rejecting unknown fields
+func decodeEntryRequest(r *http.Request, req *entryRequest) error {
+	decoder := json.NewDecoder(http.MaxBytesReader(nil, r.Body, maxRequestBodyBytes))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(req); err != nil {
+		return badRequest("invalid request body: %v", err)
+	}
+	return nil
+}
+
+// applyEntryRequest copies the fields present in req onto entry
+func (s *apiServer) applyEntryRequest(entry *WeightEntry, req entryRequest) error {
+	if req.Weight != nil {
+		entry.Weight = *req.Weight
+	}
+	if req.Date != nil {
+		date, err := s.parseDate(*req.Date)
+		if err != nil {
+			return err
+		}
+		entry.Date = date
+	}
+	if req.Unit != nil {
+		entry.Unit = *req.Unit
+	}
+	if req.Note != nil {
+		entry.Note = *req.Note
+	}
+	if req.UserID != nil {
+		entry.UserID = *req.UserID
+	}
+	return nil
+}
+
+// parseDate parses a date in one of the API formats or the configured input format;
+// values without a zone are in the configured time zone
+func (s *apiServer) parseDate(value string) (time.Time, error) {
+	location := GetLocationFromEnv(s.app.Getenv)
+	for _, layout := range apiDateLayouts {
+		if date, err := time.ParseInLocation(layout, value, location); err == nil {
+			return date, nil
+		}
+	}
+	if date, err := ParseDateFromEnv(value, s.app.Getenv); err == nil {
+		return date, nil
+	}
+	return time.Time{}, badRequest("invalid date '%s': use RFC 3339, yyyy-mm-dd or %s", value, GetInputFormatDescriptionFromEnv(s.app.Getenv))
+}
+
+// dateRangeFromQuery parses the from and to query parameters; a date-only to
+// value includes the whole day
+func (s *apiServer) dateRangeFromQuery(r *http.Request) (*time.Time, *time.Time, error) {
+	query := r.URL.Query()
+	var fromDate, toDate *time.Time
+
+	if value := query.Get("from"); value != "" {
+		date, err := s.parseDate(value)
+		if err != nil {
+			return nil, nil, err
+		}
+		fromDate = &date
+	}
+
+	if value := query.Get("to"); value != "" {
+		date, err := s.parseDate(value)
+		if err != nil {
+			return nil, nil, err
+		}
+		if TimeOfDay(date) == 0 {
+			date = EndOfDay(date)
+		}
+		toDate = &date
+	}
+
+	return fromDate, toDate, nil
+}
+
+// trendOptionsFromQuery parses the trend (method name) and trend_window query
+// parameters; no trend parameter selects no trend
+func trendOptionsFromQuery(r *http.Request) (TrendOptions, error) {
+	method := r.URL.Query().Get("trend")
+	if method == "" {
+		return TrendOptions{}, nil
+	}
+
+	var options TrendOptions
+	var err error
+	options.Method, err = parseTrendMethod(method)
+	if err != nil {
+		return TrendOptions{}, badRequest("%v", err)
+	}
+	options.WindowDays, err = intFromQuery(r, "trend_window", DefaultTrendWindowDays)
+	if err != nil {
+		return TrendOptions{}, err
+	}
+	if options.WindowDays <= 0 {
+		return TrendOptions{}, badRequest("trend_window must be a positive number of days")
+	}
+	return options, nil
+}
+
+// intFromQuery parses an integer query parameter, returning fallback when it is absent
+func intFromQuery(r *http.Request, name string, fallback int) (int, error) {
+	value := r.URL.Query().Get(name)
+	if value == "" {
+		return fallback, nil
+	}
+	parsed, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, badRequest("invalid %s '%s': must be a number", name, value)
+	}
+	return parsed, nil
+}
+
+// listOptionsFromQuery builds ListOptions from the query parameters of a request
+// (from, to, limit, sort, desc, unit, note, q, offset, after, user), with the same
+// defaults as 'list'; q is a full-text query over notes
+func (s *apiServer) listOptionsFromQuery(r *http.Request) (ListOptions, error) {
+	query := r.URL.Query()
+
+	fromDate, toDate, err := s.dateRangeFromQuery(r)
+	if err != nil {
+		return ListOptions{}, err
+	}
+
+	limitValue, err := intFromQuery(r, "limit", 0)
+	if err != nil {
+		return ListOptions{}, err
+	}
+	if limitValue < 0 {
+		return ListOptions{}, badRequest("limit must not be negative")
+	}
+
+	sortSpec := query.Get("sort")
+	if sortSpec == "" {
+		sortSpec = "date"
+	}
+	sortBy, sortKeys, err := resolveSortSpec(sortSpec, query.Get("desc") != "")
+	if err != nil {
+		return ListOptions{}, badRequest("%v", err)
+	}
+
+	sortDesc := true
+	if value := query.Get("desc"); value != "" {
+		sortDesc, err = strconv.ParseBool(value)
+		if err != nil {
+			return ListOptions{}, badRequest("invalid desc '%s': must be true or false", value)
+		}
+	}
+
+	unitFilter := query.Get("unit")
+	if unitFilter != "" && !IsValidUnit(unitFilter) {
+		return ListOptions{}, badRequest("invalid unit '%s': must be 'kg' or 'lbs'", unitFilter)
+	}
+
+	offsetValue, err := intFromQuery(r, "offset", 0)
+	if err != nil {
+		return ListOptions{}, err
+	}
+	if offsetValue < 0 {
+		return ListOptions{}, badRequest("offset must not be negative")
+	}
+
+	afterValue, err := intFromQuery(r, "after", 0)
+	if err != nil {
+		return ListOptions{}, err
+	}
+	if afterValue < 0 {
+		return ListOptions{}, badRequest("after must be the ID of an entry")
+	}
+
+	return ListOptions{
+		FromDate:  fromDate,
+		ToDate:    toDate,
+		Limit:     limitValue,
+		SortBy:    sortBy,
+		SortDesc:  sortDesc,
+		Sort:      sortKeys,
+		Unit:      unitFilter,
+		Note:      query.Get("note"),
+		NoteQuery: query.Get("q"),
+		Offset:    offsetValue,
+		AfterID:   int64(afterValue),
+		UserID:    query.Get("user"),
+	}, nil
+}
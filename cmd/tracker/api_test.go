@@ -0,0 +1,343 @@
+package tracker
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// api_test.go - REST API tests
+// * purpose: tests the HTTP handlers of the serve command against a MockStore.
+// * tests: httptest requests covering every route and error path
+// * focus: status codes, JSON bodies, partial and full updates and structured errors.
+
+// newTestAPI returns an API handler over a MockStore seeded with user entries
+func newTestAPI(t *testing.T) (http.Handler, *MockStore) {
+	t.Helper()
+	store := NewMockStore()
+	seedUserEntries(t, store)
+	return NewAPIHandler(newTestAPIApp(store, nil), store), store
+}
+
+// newTestAPIApp returns the App of an API handler: env as its environment and a fixed clock
+func newTestAPIApp(store Store, env map[string]string) *App {
+	app, _, _, _ := newTestApp(store, env)
+	return app
+}
+
+// doAPIRequest sends a request to handler, with a JSON body when body is set, and
+// returns the recorded response
+func doAPIRequest(handler http.Handler, method, target, body string) *httptest.ResponseRecorder {
+	request := httptest.NewRequest(method, target, strings.NewReader(body))
+	if body != "" {
+		request.Header.Set("Content-Type", "application/json")
+	}
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, request)
+	return recorder
+}
+
+func TestAPI_StatusCodes(t *testing.T) {
+	tests := []struct {
+		name       string
+		method     string
+		target     string
+		body       string
+		wantStatus int
+		wantError  string
+	}{
+		{name: "list entries", method: "GET", target: "/entries", wantStatus: http.StatusOK},
+		{name: "get entry", method: "GET", target: "/entries/1", wantStatus: http.StatusOK},
+		{name: "get missing entry", method: "GET", target: "/entries/99", wantStatus: http.StatusNotFound, wantError: "not found"},
+		{name: "get invalid id", method: "GET", target: "/entries/abc", wantStatus: http.StatusBadRequest, wantError: "invalid ID"},
+		{name: "get entry of other user", method: "GET", target: "/entries/1?user=bob", wantStatus: http.StatusForbidden, wantError: "does not belong to user"},
+		{name: "create entry", method: "POST", target: "/entries", body: `{"weight": 75.5, "unit": "kg", "date": "2025-02-01"}`, wantStatus: http.StatusCreated},
+		{name: "create without weight", method: "POST", target: "/entries", body: `{"unit": "kg"}`, wantStatus: http.StatusBadRequest, wantError: "weight is required"},
+		{name: "create with invalid unit", method: "POST", target: "/entries", body: `{"weight": 75.5, "unit": "stone"}`, wantStatus: http.StatusBadRequest, wantError: "validation failed"},
+		{name: "create with invalid date", method: "POST", target: "/entries", body: `{"weight": 75.5, "date": "yesterday-ish"}`, wantStatus: http.StatusBadRequest, wantError: "invalid date"},
+		{name: "create with unknown field", method: "POST", target: "/entries", body: `{"weight": 75.5, "mass": 1}`, wantStatus: http.StatusBadRequest, wantError: "unknown field"},
+		{name: "create with malformed json", method: "POST", target: "/entries", body: `{"weight":`, wantStatus: http.StatusBadRequest, wantError: "invalid request body"},
+		{name: "put without date", method: "PUT", target: "/entries/1", body: `{"weight": 60}`, wantStatus: http.StatusBadRequest, wantError: "weight and date are required"},
+		{name: "patch without fields", method: "PATCH", target: "/entries/1", body: `{}`, wantStatus: http.StatusBadRequest, wantError: "no fields to update"},
+		{name: "patch missing entry", method: "PATCH", target: "/entries/99", body: `{"weight": 60}`, wantStatus: http.StatusNotFound},
+		{name: "delete entry", method: "DELETE", target: "/entries/1", wantStatus: http.StatusNoContent},
+		{name: "delete missing entry", method: "DELETE", target: "/entries/99", wantStatus: http.StatusNotFound},
+		{name: "list with invalid sort", method: "GET", target: "/entries?sort=bmi", wantStatus: http.StatusBadRequest, wantError: "invalid sort key"},
+		{name: "list with invalid search query", method: "GET", target: "/entries?q=%22unbalanced", wantStatus: http.StatusBadRequest, wantError: "invalid search query"},
+		{name: "list with invalid limit", method: "GET", target: "/entries?limit=ten", wantStatus: http.StatusBadRequest, wantError: "invalid limit"},
+		{name: "stats", method: "GET", target: "/stats", wantStatus: http.StatusOK},
+		{name: "stats with invalid trend", method: "GET", target: "/stats?trend=median", wantStatus: http.StatusBadRequest, wantError: "invalid trend method"},
+		{name: "unknown route", method: "GET", target: "/weights", wantStatus: http.StatusNotFound, wantError: "no route"},
+		{name: "unsupported method", method: "POST", target: "/stats", wantStatus: http.StatusMethodNotAllowed, wantError: "not allowed"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler, _ := newTestAPI(t)
+			response := doAPIRequest(handler, tt.method, tt.target, tt.body)
+
+			if response.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d (body %s)", response.Code, tt.wantStatus, response.Body.String())
+			}
+			if tt.wantError == "" {
+				return
+			}
+
+			var result errorResult
+			if err := json.Unmarshal(response.Body.Bytes(), &result); err != nil {
+				t.Fatalf("error body is not JSON: %v (%s)", err, response.Body.String())
+			}
+			if !strings.Contains(result.Error, tt.wantError) {
+				t.Errorf("error = %q, want it to contain %q", result.Error, tt.wantError)
+			}
+		})
+	}
+}
+
+func TestAPI_CreateEntry(t *testing.T) {
+	handler, store := newTestAPI(t)
+
+	response := doAPIRequest(handler, "POST", "/entries", `{"weight": 75.5, "unit": "kg", "date": "2025-02-01T08:30:00Z", "note": "api", "user_id": "alice"}`)
+	if response.Code != http.StatusCreated {
+		t.Fatalf("status = %d, want 201 (body %s)", response.Code, response.Body.String())
+	}
+
+	var created WeightEntry
+	if err := json.Unmarshal(response.Body.Bytes(), &created); err != nil {
+		t.Fatal(unexpectedErrorString(err))
+	}
+	if location := response.Header().Get("Location"); location != "/entries/5" || created.ID != 5 {
+		t.Errorf("Location = %q, ID = %d, want /entries/5 and 5", location, created.ID)
+	}
+
+	stored, err := store.GetWeight(context.Background(), created.ID)
+	if err != nil {
+		t.Fatal(unexpectedErrorString(err))
+	}
+	wantDate := time.Date(2025, 2, 1, 8, 30, 0, 0, time.UTC)
+	if stored.Weight != 75.5 || stored.Note != "api" || stored.UserID != "alice" || !stored.Date.Equal(wantDate) {
+		t.Errorf("stored entry = %+v", stored)
+	}
+
+}
+
+// Bodies must be declared as JSON on every route that reads one; a charset
+// parameter is fine
+func TestAPI_RequireJSON(t *testing.T) {
+	routes := []struct {
+		method     string
+		target     string
+		successful int
+	}{
+		{method: "POST", target: "/entries", successful: http.StatusCreated},
+		{method: "PUT", target: "/entries/1", successful: http.StatusOK},
+		{method: "PATCH", target: "/entries/1", successful: http.StatusOK},
+	}
+	contentTypes := []string{"", "text/plain", "application/x-www-form-urlencoded", "application/json; charset=utf-8"}
+
+	for _, route := range routes {
+		for _, contentType := range contentTypes {
+			t.Run(route.method+" "+contentType, func(t *testing.T) {
+				handler, _ := newTestAPI(t)
+				request := httptest.NewRequest(route.method, route.target, strings.NewReader(`{"weight": 75.5, "date": "2025-01-05"}`))
+				if contentType != "" {
+					request.Header.Set("Content-Type", contentType)
+				}
+				recorder := httptest.NewRecorder()
+				handler.ServeHTTP(recorder, request)
+
+				wantStatus := http.StatusUnsupportedMediaType
+				if strings.HasPrefix(contentType, "application/json") {
+					wantStatus = route.successful
+				}
+				if recorder.Code != wantStatus {
+					t.Errorf("%s %s with Content-Type %q: status = %d, want %d (body %s)", route.method, route.target, contentType, recorder.Code, wantStatus, recorder.Body.String())
+				}
+			})
+		}
+	}
+}
+
+func TestAPI_CreateEntry_AppDefaults(t *testing.T) {
+	store := NewMockStore()
+	app := newTestAPIApp(store, map[string]string{"DEFAULT_UNIT": "lbs", "TIMEZONE": "America/New_York", "DATE_INPUT_FORMAT": "dd-mm-yyyy"})
+	handler := NewAPIHandler(app, store)
+
+	// Omitted date and unit come from the App's clock and environment
+	response := doAPIRequest(handler, "POST", "/entries", `{"weight": 165}`)
+	if response.Code != http.StatusCreated {
+		t.Fatalf("status = %d, want 201 (body %s)", response.Code, response.Body.String())
+	}
+	stored, err := store.GetWeight(context.Background(), 1)
+	if err != nil {
+		t.Fatal(unexpectedErrorString(err))
+	}
+	if stored.Unit != "lbs" || !stored.Date.Equal(app.Now()) {
+		t.Errorf("stored entry = %+v, want unit lbs and date %v", stored, app.Now())
+	}
+
+	// Dates in the configured input format are read in the configured time zone
+	response = doAPIRequest(handler, "PUT", "/entries/1", `{"weight": 164, "date": "02-03-2025"}`)
+	if response.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200 (body %s)", response.Code, response.Body.String())
+	}
+	if stored, err = store.GetWeight(context.Background(), 1); err != nil {
+		t.Fatal(unexpectedErrorString(err))
+	}
+	wantDate := time.Date(2025, 3, 2, 5, 0, 0, 0, time.UTC)
+	if stored.Unit != "lbs" || !stored.Date.Equal(wantDate) {
+		t.Errorf("replaced entry = %+v, want unit lbs and date %v", stored, wantDate)
+	}
+}
+
+func TestAPI_ListEntries(t *testing.T) {
+	tests := []struct {
+		name       string
+		target     string
+		wantWeight []float64
+	}{
+		{name: "all entries newest first", target: "/entries?sort=weight", wantWeight: []float64{88.0, 70.0, 62.0, 61.5}},
+		{name: "ascending", target: "/entries?sort=weight&desc=false", wantWeight: []float64{61.5, 62.0, 70.0, 88.0}},
+		{name: "user filter", target: "/entries?user=alice", wantWeight: []float64{61.5, 62.0}},
+		{name: "date range", target: "/entries?from=2025-01-02&to=2025-01-02", wantWeight: []float64{61.5}},
+		{name: "limit", target: "/entries?sort=weight&limit=1", wantWeight: []float64{88.0}},
+		{name: "compound sort", target: "/entries?sort=user,-weight", wantWeight: []float64{70.0, 62.0, 61.5, 88.0}},
+		{name: "display unit", target: "/entries?user=bob&display_unit=lbs", wantWeight: []float64{194.0068}},
+		{name: "no matches is an empty array", target: "/entries?user=carol", wantWeight: []float64{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler, _ := newTestAPI(t)
+			response := doAPIRequest(handler, "GET", tt.target, "")
+			if response.Code != http.StatusOK {
+				t.Fatalf("status = %d, want 200 (body %s)", response.Code, response.Body.String())
+			}
+
+			var entries []WeightEntry
+			if err := json.Unmarshal(response.Body.Bytes(), &entries); err != nil {
+				t.Fatal(unexpectedErrorString(err))
+			}
+			if entries == nil {
+				t.Fatalf("body = %s, want a JSON array", response.Body.String())
+			}
+			if len(entries) != len(tt.wantWeight) {
+				t.Fatalf("got %d entries, want %d", len(entries), len(tt.wantWeight))
+			}
+			for i, want := range tt.wantWeight {
+				if diff := entries[i].Weight - want; diff > 0.001 || diff < -0.001 {
+					t.Errorf("entry %d weight = %v, want %v", i, entries[i].Weight, want)
+				}
+			}
+		})
+	}
+}
+
+func TestAPI_UpdateEntry(t *testing.T) {
+	tests := []struct {
+		name     string
+		method   string
+		body     string
+		wantNote string
+		wantUnit string
+		wantDay  int
+	}{
+		{name: "patch keeps omitted fields", method: "PATCH", body: `{"weight": 61}`, wantNote: "", wantUnit: "kg", wantDay: 1},
+		{name: "put replaces the entry", method: "PUT", body: `{"weight": 135, "unit": "lbs", "date": "2025-01-05", "note": "new"}`, wantNote: "new", wantUnit: "lbs", wantDay: 5},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler, store := newTestAPI(t)
+			response := doAPIRequest(handler, tt.method, "/entries/1", tt.body)
+			if response.Code != http.StatusOK {
+				t.Fatalf("status = %d, want 200 (body %s)", response.Code, response.Body.String())
+			}
+
+			stored, err := store.GetWeight(context.Background(), 1)
+			if err != nil {
+				t.Fatal(unexpectedErrorString(err))
+			}
+			if stored.Note != tt.wantNote || stored.Unit != tt.wantUnit || stored.Date.Day() != tt.wantDay || stored.UserID != "alice" {
+				t.Errorf("stored entry = %+v", stored)
+			}
+		})
+	}
+}
+
+func TestAPI_UpdateEntry_ClearsNote(t *testing.T) {
+	testDB := setupTestDB(t)
+	defer testDB.Close()
+
+	stores := map[string]Store{"mock": NewMockStore(), "db": NewDBStoreWithDB(testDB, time.UTC)}
+	for name, store := range stores {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			entry, err := store.AddWeight(ctx, WeightEntry{Weight: 62, Date: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC), Unit: "kg", Note: "old", UserID: "alice"})
+			if err != nil {
+				t.Fatal(failedTestEntryAdditionString(err))
+			}
+			handler := NewAPIHandler(newTestAPIApp(store, nil), store)
+			target := fmt.Sprintf("/entries/%d", entry.ID)
+
+			// PUT replaces the whole entry, so an omitted note is cleared
+			response := doAPIRequest(handler, "PUT", target, `{"weight": 61, "date": "2025-01-02"}`)
+			if response.Code != http.StatusOK {
+				t.Fatalf("PUT status = %d, want 200 (body %s)", response.Code, response.Body.String())
+			}
+			stored, err := store.GetWeight(ctx, entry.ID)
+			if err != nil {
+				t.Fatal(unexpectedErrorString(err))
+			}
+			if stored.Note != "" || stored.Weight != 61 || stored.UserID != "alice" {
+				t.Errorf("entry after PUT without note = %+v, want the note cleared", stored)
+			}
+
+			// PATCH clears a note set to an empty string
+			if response := doAPIRequest(handler, "PATCH", target, `{"note": "again"}`); response.Code != http.StatusOK {
+				t.Fatalf("PATCH status = %d (body %s)", response.Code, response.Body.String())
+			}
+			if response := doAPIRequest(handler, "PATCH", target, `{"note": ""}`); response.Code != http.StatusOK {
+				t.Fatalf("PATCH status = %d (body %s)", response.Code, response.Body.String())
+			}
+			if stored, err = store.GetWeight(ctx, entry.ID); err != nil || stored.Note != "" || stored.Weight != 61 {
+				t.Errorf("entry after PATCH with an empty note = %+v (error %v), want the note cleared", stored, err)
+			}
+		})
+	}
+}
+
+func TestAPI_DeleteEntry(t *testing.T) {
+	handler, store := newTestAPI(t)
+
+	response := doAPIRequest(handler, "DELETE", "/entries/2", "")
+	if response.Code != http.StatusNoContent || response.Body.Len() != 0 {
+		t.Fatalf("status = %d, body %q, want 204 without body", response.Code, response.Body.String())
+	}
+	if _, err := store.GetWeight(context.Background(), 2); !errors.Is(err, ErrEntryNotFound) {
+		t.Errorf("GetWeight() after delete error = %v, want ErrEntryNotFound", err)
+	}
+}
+
+func TestAPI_Stats(t *testing.T) {
+	handler, _ := newTestAPI(t)
+
+	response := doAPIRequest(handler, "GET", "/stats?user=alice&trend=linear", "")
+	if response.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200 (body %s)", response.Code, response.Body.String())
+	}
+
+	var stats WeightStatistics
+	if err := json.Unmarshal(response.Body.Bytes(), &stats); err != nil {
+		t.Fatal(unexpectedErrorString(err))
+	}
+	if stats.TotalEntries != 2 || stats.MinWeight != 61.5 || stats.Trend == nil || stats.Trend.Method != TrendLinear {
+		t.Errorf("stats = %+v, want 2 alice entries with a linear trend", stats)
+	}
+}
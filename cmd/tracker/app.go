@@ -0,0 +1,230 @@
+package tracker
+
+// app.go - Application context holding the dependencies of every command
+// Related files: root.go (wires the App in PersistentPreRunE), app_test.go (tests)
+// Commands take their Store, configuration, I/O streams and clock from the App
+// instead of reaching for the database, environment or os.Std* directly, so they
+// can run end to end against a MockStore or inside other tools.
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/BlochLior/weight-tracker/internal/db"
+	"github.com/spf13/cobra"
+)
+
+// App holds the dependencies shared by all commands
+type App struct {
+	// NewStore opens the Store a command works with; the command closes it when done
+	NewStore func() (Store, error)
+	// OpenDatabase opens the SQLite database without migrating it (db commands)
+	OpenDatabase func() (*sql.DB, error)
+	// Getenv looks up configuration values (DATE_INPUT_FORMAT, DEFAULT_UNIT, ...);
+	// setupApp layers the config file under it
+	Getenv func(string) string
+	// In, Out and Err are the command's input and output streams
+	In  io.Reader
+	Out io.Writer
+	Err io.Writer
+	// Now returns the current time, used for default entry dates
+	Now func() time.Time
+	// Exit ends the process after a command failed (os.Exit by default); tools
+	// embedding the commands can record the status code instead
+	Exit func(code int)
+}
+
+// databasePathFlag holds the --db flag
+var databasePathFlag string
+
+// appContextKey is the context key the App is stored under
+type appContextKey struct{}
+
+// DefaultApp returns the App used by the weight-tracker binary: the SQLite
+// store at the resolved database path, the process environment, standard
+// streams and the system clock
+func DefaultApp() *App {
+	app := &App{
+		Getenv: os.Getenv,
+		In:     os.Stdin,
+		Out:    os.Stdout,
+		Err:    os.Stderr,
+		Now:    time.Now,
+		Exit:   os.Exit,
+	}
+	app.useDatabase()
+	return app
+}
+
+// useDatabase opens the store and database at the path resolved through the
+// App's environment, as it is when the command runs (after the config file was layered)
+func (app *App) useDatabase() {
+	app.NewStore = func() (Store, error) {
+		return openDBStore(app.Getenv)
+	}
+	app.OpenDatabase = func() (*sql.DB, error) {
+		return openDatabaseFile(app.Getenv)
+	}
+}
+
+// NewAppWithStore returns the default App with every command using store
+// (useful for tests and for embedding the commands with a custom Store)
+func NewAppWithStore(store Store) *App {
+	app := DefaultApp()
+	app.NewStore = func() (Store, error) {
+		return store, nil
+	}
+	return app
+}
+
+// openDBStore opens the SQLite store at the database path resolved through getEnv as a Store
+func openDBStore(getEnv func(string) string) (Store, error) {
+	path, err := resolveDatabasePath(getEnv)
+	if err != nil {
+		return nil, err
+	}
+
+	store, err := NewDBStore(path, GetLocationFromEnv(getEnv))
+	if err != nil {
+		return nil, err
+	}
+	return store, nil
+}
+
+// openDatabaseFile opens the SQLite database at the database path resolved through
+// getEnv without migrating it
+func openDatabaseFile(getEnv func(string) string) (*sql.DB, error) {
+	path, err := resolveDatabasePath(getEnv)
+	if err != nil {
+		return nil, err
+	}
+	return db.Connect(path)
+}
+
+// resolveDatabasePath returns the database path selected by --db, DATABASE_PATH
+// (environment, .env or config file) or the per-user default location
+func resolveDatabasePath(getEnv func(string) string) (string, error) {
+	getEnv, err := withConfigFile(getEnv)
+	if err != nil {
+		return "", err
+	}
+	return db.ResolvePath(databasePathFlag, getEnv)
+}
+
+// WithApp returns a copy of ctx carrying app, for use with rootCmd.ExecuteContext
+func WithApp(ctx context.Context, app *App) context.Context {
+	return context.WithValue(ctx, appContextKey{}, app)
+}
+
+// appFromContext returns the App carried by ctx, if any
+func appFromContext(ctx context.Context) (*App, bool) {
+	if ctx == nil {
+		return nil, false
+	}
+	app, ok := ctx.Value(appContextKey{}).(*App)
+	return app, ok && app != nil
+}
+
+// appFor returns the App of a running command, falling back to the default App
+// when the command was not started through the root command. The App is read
+// from the root command, whose context is replaced on every execution (cobra
+// only hands the context down to subcommands that have none yet).
+func appFor(cmd *cobra.Command) *App {
+	if app, ok := appFromContext(cmd.Root().Context()); ok {
+		return app
+	}
+	return DefaultApp()
+}
+
+// withDefaults returns a copy of app with unset dependencies filled in; streams
+// default to the ones already configured on cmd
+func (app App) withDefaults(cmd *cobra.Command) *App {
+	defaults := DefaultApp()
+	if app.NewStore == nil || app.OpenDatabase == nil {
+		newStore, openDatabase := app.NewStore, app.OpenDatabase
+		app.useDatabase()
+		if newStore != nil {
+			app.NewStore = newStore
+		}
+		if openDatabase != nil {
+			app.OpenDatabase = openDatabase
+		}
+	}
+	if app.Getenv == nil {
+		app.Getenv = defaults.Getenv
+	}
+	if app.In == nil {
+		app.In = cmd.InOrStdin()
+	}
+	if app.Out == nil {
+		app.Out = cmd.OutOrStdout()
+	}
+	if app.Err == nil {
+		app.Err = cmd.ErrOrStderr()
+	}
+	if app.Now == nil {
+		app.Now = defaults.Now
+	}
+	if app.Exit == nil {
+		app.Exit = defaults.Exit
+	}
+	return &app
+}
+
+// applyConfigFile layers the config file under the App's environment, reporting an
+// unreadable file and unrecognized values in the file or the environment
+func (app *App) applyConfigFile() error {
+	path := configFilePath(app.Getenv)
+	file, err := LoadConfigFile(path)
+	if err != nil {
+		return err
+	}
+	if err := file.Validate(); err != nil {
+		return fmt.Errorf("config file %s: %w", path, err)
+	}
+
+	app.Getenv = file.layer(app.Getenv)
+	if _, err := LoadAppConfigFromEnv(app.Getenv); err != nil {
+		return err
+	}
+	return nil
+}
+
+// setupApp is rootCmd's PersistentPreRunE: it completes the App carried by the
+// command context and routes the command's streams through it
+func setupApp(cmd *cobra.Command, args []string) error {
+	_ = args
+
+	root := cmd.Root()
+	app, ok := appFromContext(root.Context())
+	if !ok {
+		app = &App{}
+	}
+	app = app.withDefaults(cmd)
+
+	cmd.SetIn(app.In)
+	cmd.SetOut(app.Out)
+	cmd.SetErr(app.Err)
+	// cobra reports errors returned by the command tree on the root command
+	root.SetErr(app.Err)
+
+	// The config commands read the file themselves, so that they can repair it
+	if !isConfigCommand(cmd) {
+		if err := app.applyConfigFile(); err != nil {
+			// Configuration problems are not usage mistakes
+			cmd.SilenceUsage = true
+			return err
+		}
+	}
+
+	ctx := root.Context()
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	root.SetContext(WithApp(ctx, app))
+	return nil
+}
@@ -1,8 +1,15 @@
 package tracker
 
 import (
+	"errors"
+	"fmt"
 	"os"
+	"sort"
+	"strings"
 	"time"
+
+	// Bundle the time zone database so that TIMEZONE works on systems without one
+	_ "time/tzdata"
 )
 
 // DateFormatConfig holds the date format configuration
@@ -15,25 +22,78 @@ type DateFormatConfig struct {
 // AppConfig holds the application configuration
 type AppConfig struct {
 	DateFormat  DateFormatConfig
-	DefaultUnit string // Default weight unit (kg or lbs)
+	DefaultUnit string         // Default weight unit (kg or lbs)
+	Location    *time.Location // Time zone that dates are entered and displayed in (TIMEZONE)
 }
 
 // Default configurations
 const (
-	DefaultInputFormat   = "02-01-2006" // DD-MM-YYYY
-	DefaultDisplayFormat = "02-01-2006" // DD-MM-YYYY
-	DBFormat             = "2006-01-02" // YYYY-MM-DD (ISO standard)
-	DefaultUnit          = "kg"         // Default weight unit
+	DefaultInputFormat   = "02-01-2006"                // DD-MM-YYYY
+	DefaultDisplayFormat = "02-01-2006"                // DD-MM-YYYY
+	DBFormat             = "2006-01-02 15:04:05Z07:00" // YYYY-MM-DD HH:MM:SSZ, always UTC (ISO standard, sortable as text)
+	DBLegacyFormat       = "2006-01-02 15:04:05"       // Rows written before UTC storage, in the configured time zone
+	DBDateOnlyFormat     = "2006-01-02"                // Legacy date-only rows written before time-of-day support
+	DefaultUnit          = "kg"                        // Default weight unit
+	DefaultTimezone      = "Local"                     // The machine's time zone
 )
 
+// Supported time-of-day input formats, tried in order
+var timeInputFormats = []string{
+	"15:04",    // HH:MM
+	"15:04:05", // HH:MM:SS
+}
+
 // Supported format mappings
 var formatMappings = map[string]string{
-	"dd-mm-yyyy": "02-01-2006",
-	"mm-dd-yyyy": "01-02-2006",
-	"yyyy-mm-dd": "2006-01-02",
-	"dd/mm/yyyy": "02/01/2006",
-	"mm/dd/yyyy": "01/02/2006",
-	"yyyy/mm/dd": "2006/01/02",
+	"dd-mm-yyyy":     "02-01-2006",
+	"mm-dd-yyyy":     "01-02-2006",
+	"yyyy-mm-dd":     "2006-01-02",
+	"dd/mm/yyyy":     "02/01/2006",
+	"mm/dd/yyyy":     "01/02/2006",
+	"yyyy/mm/dd":     "2006/01/02",
+	"dd mon yyyy":    "02 Jan 2006",     // 15 Sep 2024
+	"d mon yyyy":     "2 Jan 2006",      // 5 Sep 2024
+	"dd month yyyy":  "02 January 2006", // 15 September 2024
+	"mon d, yyyy":    "Jan 2, 2006",     // Sep 15, 2024
+	"month d, yyyy":  "January 2, 2006", // September 15, 2024
+	"dd.mm.yyyy":     "02.01.2006",
+	"yyyy-mm-dd ddd": "2006-01-02 Mon", // 2024-09-15 Sun
+}
+
+// Unambiguous input layouts accepted after the configured DATE_INPUT_FORMAT, tried in order.
+// Year-first and month-name dates cannot be mistaken for another day, unlike dd-mm/mm-dd.
+var fallbackInputLayouts = []string{
+	"2006-01-02",
+	"2006/01/02",
+	"2 Jan 2006",
+	"2 January 2006",
+	"Jan 2, 2006",
+	"January 2, 2006",
+	"Jan 2 2006",
+	"January 2 2006",
+}
+
+// strftimeDirectives maps strftime conversion characters to Go layout elements
+var strftimeDirectives = map[byte]string{
+	'Y': "2006",    // Year with century
+	'y': "06",      // Year without century
+	'm': "01",      // Month, zero-padded
+	'd': "02",      // Day of the month, zero-padded
+	'e': "_2",      // Day of the month, space-padded
+	'b': "Jan",     // Abbreviated month name
+	'h': "Jan",     // Same as %b
+	'B': "January", // Full month name
+	'a': "Mon",     // Abbreviated weekday name
+	'A': "Monday",  // Full weekday name
+	'j': "002",     // Day of the year
+	'H': "15",      // Hour (24-hour clock)
+	'I': "03",      // Hour (12-hour clock)
+	'M': "04",      // Minute
+	'S': "05",      // Second
+	'p': "PM",      // AM or PM
+	'F': "2006-01-02",
+	'D': "01/02/06",
+	'%': "%",
 }
 
 // GetAppConfig returns the application configuration based on environment variables
@@ -42,40 +102,157 @@ func GetAppConfig() AppConfig {
 }
 
 // GetAppConfigFromEnv returns the application configuration using a custom environment function
-// This allows for dependency injection and better testability
+// This allows for dependency injection and better testability. Unrecognized values fall back
+// to the defaults; commands reject them up front through LoadAppConfigFromEnv.
 func GetAppConfigFromEnv(getEnv func(string) string) AppConfig {
-	dateConfig := DateFormatConfig{
-		InputFormat:   DefaultInputFormat,
-		DisplayFormat: DefaultDisplayFormat,
-		DBFormat:      DBFormat, // Always ISO for database
+	config, _ := LoadAppConfigFromEnv(getEnv)
+	return config
+}
+
+// LoadAppConfigFromEnv returns the application configuration using a custom environment
+// function, with an error naming every variable that holds an unrecognized value.
+// Settings with invalid values are left at their defaults in the returned configuration.
+func LoadAppConfigFromEnv(getEnv func(string) string) (AppConfig, error) {
+	config := AppConfig{
+		DateFormat: DateFormatConfig{
+			InputFormat:   DefaultInputFormat,
+			DisplayFormat: DefaultDisplayFormat,
+			DBFormat:      DBFormat, // Always ISO for database
+		},
+		DefaultUnit: DefaultUnit,
+		Location:    time.Local,
 	}
+	var errs []error
 
 	// Check for input format override
 	if inputFormat := getEnv("DATE_INPUT_FORMAT"); inputFormat != "" {
-		if goFormat, exists := formatMappings[inputFormat]; exists {
-			dateConfig.InputFormat = goFormat
+		goFormat, err := parseDateFormatName(inputFormat)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("invalid DATE_INPUT_FORMAT: %w", err))
+		} else {
+			config.DateFormat.InputFormat = goFormat
 		}
 	}
 
 	// Check for display format override
 	if displayFormat := getEnv("DATE_DISPLAY_FORMAT"); displayFormat != "" {
-		if goFormat, exists := formatMappings[displayFormat]; exists {
-			dateConfig.DisplayFormat = goFormat
+		goFormat, err := parseDateFormatName(displayFormat)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("invalid DATE_DISPLAY_FORMAT: %w", err))
+		} else {
+			config.DateFormat.DisplayFormat = goFormat
 		}
 	}
 
 	// Get default unit configuration
-	defaultUnit := DefaultUnit
 	if unit := getEnv("DEFAULT_UNIT"); unit != "" {
-		if unit == "kg" || unit == "lbs" {
-			defaultUnit = unit
+		if err := validateDefaultUnit(unit); err != nil {
+			errs = append(errs, fmt.Errorf("invalid DEFAULT_UNIT: %w", err))
+		} else {
+			config.DefaultUnit = unit
+		}
+	}
+
+	// Get time zone configuration
+	if timezone := getEnv("TIMEZONE"); timezone != "" {
+		location, err := parseTimezone(timezone)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("invalid TIMEZONE: %w", err))
+		} else {
+			config.Location = location
 		}
 	}
 
-	return AppConfig{
-		DateFormat:  dateConfig,
-		DefaultUnit: defaultUnit,
+	return config, errors.Join(errs...)
+}
+
+// parseTimezone returns the location of an IANA time zone name such as "Europe/Berlin",
+// "UTC" or "Local"
+func parseTimezone(name string) (*time.Location, error) {
+	location, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, fmt.Errorf("unrecognized time zone '%s': use an IANA name such as Europe/London, UTC or Local", name)
+	}
+	return location, nil
+}
+
+// validateTimezone returns an error unless name is a known time zone
+func validateTimezone(name string) error {
+	_, err := parseTimezone(name)
+	return err
+}
+
+// parseDateFormatName returns the Go layout of a date format, given as a name such as
+// "dd-mm-yyyy", a strftime pattern such as "%d.%m.%Y" or a Go layout such as "2 Jan 2006".
+// The layout must identify the year, month and day so that dates round-trip through it.
+func parseDateFormatName(name string) (string, error) {
+	if goFormat, exists := formatMappings[name]; exists {
+		return goFormat, nil
+	}
+
+	layout := name
+	if strings.Contains(name, "%") {
+		converted, err := strftimeToLayout(name)
+		if err != nil {
+			return "", fmt.Errorf("unrecognized date format '%s': %w", name, err)
+		}
+		layout = converted
+	}
+
+	if !isDateLayout(layout) {
+		return "", fmt.Errorf("unrecognized date format '%s': use one of %s, a strftime pattern (e.g. %%d.%%m.%%Y) or a Go layout (e.g. 2 Jan 2006)",
+			name, strings.Join(dateFormatNames(), ", "))
+	}
+	return layout, nil
+}
+
+// strftimeToLayout converts a strftime pattern such as "%d %b %Y" to a Go layout
+func strftimeToLayout(pattern string) (string, error) {
+	var layout strings.Builder
+	for i := 0; i < len(pattern); i++ {
+		if pattern[i] != '%' {
+			layout.WriteByte(pattern[i])
+			continue
+		}
+		if i+1 == len(pattern) {
+			return "", fmt.Errorf("pattern ends with a lone '%%'")
+		}
+		i++
+		element, ok := strftimeDirectives[pattern[i]]
+		if !ok {
+			return "", fmt.Errorf("unsupported strftime directive '%%%c'", pattern[i])
+		}
+		layout.WriteString(element)
+	}
+	return layout.String(), nil
+}
+
+// isDateLayout reports whether a Go layout formats and parses back a full calendar date
+func isDateLayout(layout string) bool {
+	reference := time.Date(2024, time.September, 15, 0, 0, 0, 0, time.UTC)
+	parsed, err := time.Parse(layout, reference.Format(layout))
+	if err != nil {
+		return false
+	}
+	return parsed.Year() == reference.Year() && parsed.Month() == reference.Month() && parsed.Day() == reference.Day()
+}
+
+// dateFormatNames returns the supported date format names in alphabetical order
+func dateFormatNames() []string {
+	names := make([]string, 0, len(formatMappings))
+	for name := range formatMappings {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// validateDefaultUnit returns an error unless unit is a supported weight unit
+func validateDefaultUnit(unit string) error {
+	if !IsValidUnit(unit) {
+		return fmt.Errorf("unrecognized unit '%s': must be 'kg' or 'lbs'", unit)
 	}
+	return nil
 }
 
 // GetDateFormatConfig returns the date format configuration (for backward compatibility)
@@ -88,33 +265,132 @@ func GetDateFormatConfigFromEnv(getEnv func(string) string) DateFormatConfig {
 	return GetAppConfigFromEnv(getEnv).DateFormat
 }
 
-// ParseDate parses a date string using the configured input format
+// ParseDate parses a date string using the configured input format, falling back to
+// the unambiguous layouts in fallbackInputLayouts. The date starts at midnight in the
+// configured time zone.
 func ParseDate(dateStr string) (time.Time, error) {
-	config := GetDateFormatConfig()
-	return time.Parse(config.InputFormat, dateStr)
+	config := GetAppConfig()
+	return parseDateWithLayouts(dateStr, inputLayouts(config.DateFormat), config.Location)
 }
 
 // ParseDateFromEnv parses a date string using a custom environment function
 func ParseDateFromEnv(dateStr string, getEnv func(string) string) (time.Time, error) {
-	config := GetDateFormatConfigFromEnv(getEnv)
-	return time.Parse(config.InputFormat, dateStr)
+	config := GetAppConfigFromEnv(getEnv)
+	return parseDateWithLayouts(dateStr, inputLayouts(config.DateFormat), config.Location)
+}
+
+// inputLayouts returns the layouts ParseDate tries in order: the configured input
+// format followed by the fallback layouts
+func inputLayouts(config DateFormatConfig) []string {
+	layouts := []string{config.InputFormat}
+	for _, layout := range fallbackInputLayouts {
+		if layout != config.InputFormat {
+			layouts = append(layouts, layout)
+		}
+	}
+	return layouts
 }
 
-// FormatDate formats a time.Time using the configured display format
+// parseDateWithLayouts returns the first successful parse of dateStr in location, or the
+// error of the first layout (the configured one) when none matches
+func parseDateWithLayouts(dateStr string, layouts []string, location *time.Location) (time.Time, error) {
+	var firstErr error
+	for _, layout := range layouts {
+		t, err := time.ParseInLocation(layout, strings.TrimSpace(dateStr), location)
+		if err == nil {
+			return t, nil
+		}
+		if firstErr == nil {
+			firstErr = err
+		}
+	}
+	return time.Time{}, firstErr
+}
+
+// GetLocationFromEnv returns the configured time zone using a custom environment function
+func GetLocationFromEnv(getEnv func(string) string) *time.Location {
+	return GetAppConfigFromEnv(getEnv).Location
+}
+
+// FormatDate formats a time.Time in the configured time zone using the configured display format
 func FormatDate(t time.Time) string {
-	config := GetDateFormatConfig()
-	return t.Format(config.DisplayFormat)
+	return GetAppConfig().FormatDate(t)
 }
 
 // FormatDateFromEnv formats a time.Time using a custom environment function
 func FormatDateFromEnv(t time.Time, getEnv func(string) string) string {
-	config := GetDateFormatConfigFromEnv(getEnv)
-	return t.Format(config.DisplayFormat)
+	return GetAppConfigFromEnv(getEnv).FormatDate(t)
+}
+
+// FormatDate formats a time.Time in the time zone of c using its display format
+func (c AppConfig) FormatDate(t time.Time) string {
+	return t.In(c.Location).Format(c.DateFormat.DisplayFormat)
+}
+
+// FormatDateTime formats a time.Time like FormatDate followed by the time of day
+func (c AppConfig) FormatDateTime(t time.Time) string {
+	return c.FormatDate(t) + " " + t.In(c.Location).Format("15:04")
 }
 
-// FormatDateForDB formats a time.Time for database storage (always ISO)
+// FormatDateForDB formats a time.Time for database storage (always ISO, in UTC)
 func FormatDateForDB(t time.Time) string {
-	return t.Format(DBFormat)
+	return t.UTC().Format(DBFormat)
+}
+
+// ParseDateFromDB parses a date stored in the database and returns it in location, the
+// configured time zone. Legacy rows without a zone (full timestamps and date-only values)
+// are read in location, like the migration converting them to UTC does; they remain only
+// when AUTO_MIGRATE kept the database from being migrated.
+func ParseDateFromDB(value string, location *time.Location) (time.Time, error) {
+	if t, err := time.Parse(DBFormat, value); err == nil {
+		return t.In(location), nil
+	}
+	if t, err := time.ParseInLocation(DBLegacyFormat, value, location); err == nil {
+		return t, nil
+	}
+	return time.ParseInLocation(DBDateOnlyFormat, value, location)
+}
+
+// ParseTimestampFromDB parses a timestamp set by SQLite (CURRENT_TIMESTAMP, which is
+// UTC without a zone) and returns it in location
+func ParseTimestampFromDB(value string, location *time.Location) (time.Time, error) {
+	t, err := time.ParseInLocation(DBLegacyFormat, value, time.UTC)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return t.In(location), nil
+}
+
+// ParseTimeOfDay parses a time of day such as "07:30" or "07:30:15" and
+// returns it as an offset from midnight
+func ParseTimeOfDay(timeStr string) (time.Duration, error) {
+	for _, layout := range timeInputFormats {
+		if t, err := time.Parse(layout, timeStr); err == nil {
+			return time.Duration(t.Hour())*time.Hour +
+				time.Duration(t.Minute())*time.Minute +
+				time.Duration(t.Second())*time.Second, nil
+		}
+	}
+	return 0, fmt.Errorf("invalid time '%s': use HH:MM or HH:MM:SS (24-hour clock)", timeStr)
+}
+
+// TimeOfDay returns the offset of t from midnight of its calendar day
+func TimeOfDay(t time.Time) time.Duration {
+	midnight := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+	return t.Sub(midnight)
+}
+
+// WithTimeOfDay returns t on the same calendar day with its clock set to the given offset from midnight
+func WithTimeOfDay(t time.Time, timeOfDay time.Duration) time.Time {
+	midnight := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+	return midnight.Add(timeOfDay)
+}
+
+// EndOfDay returns the last representable instant of t's calendar day,
+// so that date-only upper bounds include entries logged later that day
+func EndOfDay(t time.Time) time.Time {
+	midnight := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+	return midnight.AddDate(0, 0, 1).Add(-time.Nanosecond)
 }
 
 // GetInputFormatDescription returns a human-readable description of the input format
@@ -134,6 +410,11 @@ func GetInputFormatDescription() string {
 
 // GetInputFormatDescriptionFromEnv returns a human-readable description using a custom environment function
 func GetInputFormatDescriptionFromEnv(getEnv func(string) string) string {
+	// Describe custom formats the way they were configured
+	if inputFormat := getEnv("DATE_INPUT_FORMAT"); inputFormat != "" && validateDateFormatName(inputFormat) == nil {
+		return inputFormat
+	}
+
 	config := GetDateFormatConfigFromEnv(getEnv)
 
 	// Find the human-readable format name
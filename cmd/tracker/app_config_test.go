@@ -1,6 +1,8 @@
 package tracker
 
 import (
+	"context"
+	"encoding/json"
 	"testing"
 	"time"
 )
@@ -276,12 +278,143 @@ func TestFormatDate(t *testing.T) {
 }
 
 func TestFormatDateForDB(t *testing.T) {
-	testDate := time.Date(2024, 9, 15, 0, 0, 0, 0, time.UTC)
-	expected := "2024-09-15"
+	tests := []struct {
+		name     string
+		date     time.Time
+		expected string
+	}{
+		{
+			name:     "midnight",
+			date:     time.Date(2024, 9, 15, 0, 0, 0, 0, time.UTC),
+			expected: "2024-09-15 00:00:00Z",
+		},
+		{
+			name:     "with time of day",
+			date:     time.Date(2024, 9, 15, 7, 30, 15, 0, time.UTC),
+			expected: "2024-09-15 07:30:15Z",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := FormatDateForDB(tt.date)
+			if result != tt.expected {
+				t.Errorf("FormatDateForDB() = %v, want %v", result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestParseDateFromDB(t *testing.T) {
+	tests := []struct {
+		name        string
+		value       string
+		expected    time.Time
+		expectError bool
+	}{
+		{
+			name:     "full timestamp",
+			value:    "2024-09-15 07:30:15",
+			expected: time.Date(2024, 9, 15, 7, 30, 15, 0, time.UTC),
+		},
+		{
+			name:     "legacy date-only value",
+			value:    "2024-09-15",
+			expected: time.Date(2024, 9, 15, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:        "invalid value",
+			value:       "15-09-2024",
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := ParseDateFromDB(tt.value, time.UTC)
+
+			if tt.expectError {
+				if err == nil {
+					t.Errorf("ParseDateFromDB() expected error but got none")
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatal(unexpectedErrorString(err))
+			}
+			if !result.Equal(tt.expected) {
+				t.Errorf("ParseDateFromDB() = %v, want %v", result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestParseTimeOfDay(t *testing.T) {
+	tests := []struct {
+		name        string
+		timeStr     string
+		expected    time.Duration
+		expectError bool
+	}{
+		{
+			name:     "hours and minutes",
+			timeStr:  "07:30",
+			expected: 7*time.Hour + 30*time.Minute,
+		},
+		{
+			name:     "hours, minutes and seconds",
+			timeStr:  "21:15:45",
+			expected: 21*time.Hour + 15*time.Minute + 45*time.Second,
+		},
+		{
+			name:        "out of range hour",
+			timeStr:     "25:00",
+			expectError: true,
+		},
+		{
+			name:        "12-hour clock is not accepted",
+			timeStr:     "7:30pm",
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := ParseTimeOfDay(tt.timeStr)
+
+			if tt.expectError {
+				if err == nil {
+					t.Errorf("ParseTimeOfDay() expected error but got none")
+				}
+				return
+			}
 
-	result := FormatDateForDB(testDate)
-	if result != expected {
-		t.Errorf("FormatDateForDB() = %v, want %v", result, expected)
+			if err != nil {
+				t.Fatal(unexpectedErrorString(err))
+			}
+			if result != tt.expected {
+				t.Errorf("ParseTimeOfDay() = %v, want %v", result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestTimeOfDayHelpers(t *testing.T) {
+	date := time.Date(2024, 9, 15, 18, 45, 0, 0, time.UTC)
+
+	if got := TimeOfDay(date); got != 18*time.Hour+45*time.Minute {
+		t.Errorf("TimeOfDay() = %v, want 18h45m", got)
+	}
+
+	morning := WithTimeOfDay(date, 7*time.Hour)
+	if !morning.Equal(time.Date(2024, 9, 15, 7, 0, 0, 0, time.UTC)) {
+		t.Errorf("WithTimeOfDay() = %v, want 2024-09-15 07:00", morning)
+	}
+
+	end := EndOfDay(date)
+	if end.Day() != 15 || FormatDateForDB(end) != "2024-09-15 23:59:59Z" {
+		t.Errorf("EndOfDay() = %v, want last instant of 2024-09-15", end)
 	}
 }
 
@@ -434,3 +567,273 @@ func TestGetAppConfigFromEnv(t *testing.T) {
 		t.Errorf("DefaultUnit = %v, want %v", config.DefaultUnit, expectedConfig.DefaultUnit)
 	}
 }
+
+func TestParseDateFormatName(t *testing.T) {
+	tests := []struct {
+		name       string
+		format     string
+		wantLayout string
+		wantErr    bool
+	}{
+		{name: "named numeric format", format: "mm/dd/yyyy", wantLayout: "01/02/2006"},
+		{name: "named month format", format: "dd mon yyyy", wantLayout: "02 Jan 2006"},
+		{name: "go layout", format: "2 January 2006", wantLayout: "2 January 2006"},
+		{name: "strftime pattern", format: "%d.%m.%Y", wantLayout: "02.01.2006"},
+		{name: "strftime month name", format: "%e %b %Y", wantLayout: "_2 Jan 2006"},
+		{name: "strftime literal percent", format: "%Y-%m-%d%%", wantLayout: "2006-01-02%"},
+		{name: "unsupported strftime directive", format: "%Q-%m-%d", wantErr: true},
+		{name: "trailing percent", format: "%Y-%m-%", wantErr: true},
+		{name: "layout without a day", format: "01-2006", wantErr: true},
+		{name: "free text", format: "iso", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			layout, err := parseDateFormatName(tt.format)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("parseDateFormatName(%q) = %q, want an error", tt.format, layout)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(unexpectedErrorString(err))
+			}
+			if layout != tt.wantLayout {
+				t.Errorf("parseDateFormatName(%q) = %q, want %q", tt.format, layout, tt.wantLayout)
+			}
+		})
+	}
+}
+
+func TestParseDate_AcceptedLayouts(t *testing.T) {
+	want := time.Date(2024, time.September, 15, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name        string
+		inputFormat string
+		dateStr     string
+		expectError bool
+	}{
+		{name: "configured format", dateStr: "15-09-2024"},
+		{name: "ISO date with day-first format", dateStr: "2024-09-15"},
+		{name: "ISO date with slashes", dateStr: "2024/09/15"},
+		{name: "abbreviated month name", dateStr: "15 Sep 2024"},
+		{name: "full month name", dateStr: "15 September 2024"},
+		{name: "month name first", dateStr: "Sep 15, 2024"},
+		{name: "month names ignore case", dateStr: "15 sep 2024"},
+		{name: "custom strftime format", inputFormat: "%d.%m.%Y", dateStr: "15.09.2024"},
+		{name: "ISO date with custom format", inputFormat: "%d.%m.%Y", dateStr: "2024-09-15"},
+		{name: "ambiguous numeric date is not guessed", inputFormat: "mm-dd-yyyy", dateStr: "15-09-2024", expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			getEnv := func(key string) string {
+				if key == "DATE_INPUT_FORMAT" {
+					return tt.inputFormat
+				}
+				return ""
+			}
+
+			got, err := ParseDateFromEnv(tt.dateStr, getEnv)
+			if tt.expectError {
+				if err == nil {
+					t.Errorf("ParseDateFromEnv(%q) = %v, want an error", tt.dateStr, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(unexpectedErrorString(err))
+			}
+			if !got.Equal(want) {
+				t.Errorf("ParseDateFromEnv(%q) = %v, want %v", tt.dateStr, got, want)
+			}
+		})
+	}
+}
+
+func TestFormatDate_CustomFormats(t *testing.T) {
+	date := time.Date(2024, time.September, 5, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		format string
+		want   string
+	}{
+		{format: "dd mon yyyy", want: "05 Sep 2024"},
+		{format: "%e %B %Y", want: " 5 September 2024"},
+		{format: "Mon, 2 Jan 2006", want: "Thu, 5 Sep 2024"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			getEnv := func(key string) string {
+				if key == "DATE_DISPLAY_FORMAT" {
+					return tt.format
+				}
+				return ""
+			}
+			if got := FormatDateFromEnv(date, getEnv); got != tt.want {
+				t.Errorf("FormatDateFromEnv() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadAppConfigFromEnv_Timezone(t *testing.T) {
+	tests := []struct {
+		timezone string
+		want     string
+		wantErr  bool
+	}{
+		{timezone: "", want: time.Local.String()}, // The machine's zone
+		{timezone: "UTC", want: "UTC"},
+		{timezone: "Asia/Tokyo", want: "Asia/Tokyo"},
+		{timezone: "Mars/Olympus_Mons", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.timezone, func(t *testing.T) {
+			config, err := LoadAppConfigFromEnv(func(key string) string {
+				if key == "TIMEZONE" {
+					return tt.timezone
+				}
+				return ""
+			})
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("LoadAppConfigFromEnv() error = nil, want an error for TIMEZONE=%s", tt.timezone)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(unexpectedErrorString(err))
+			}
+			if config.Location.String() != tt.want {
+				t.Errorf("Location = %v, want %s", config.Location, tt.want)
+			}
+		})
+	}
+}
+
+func TestTimezone_ParseAndFormat(t *testing.T) {
+	t.Setenv("TIMEZONE", "Asia/Tokyo")
+
+	// Dates start at midnight in the configured zone
+	date, err := ParseDate("15-09-2024")
+	if err != nil {
+		t.Fatal(unexpectedErrorString(err))
+	}
+	if want := time.Date(2024, 9, 14, 15, 0, 0, 0, time.UTC); !date.Equal(want) {
+		t.Errorf("ParseDate() = %v, want %v", date, want)
+	}
+
+	// Late evening UTC is already the next day in Tokyo
+	evening := time.Date(2024, 9, 15, 22, 30, 0, 0, time.UTC)
+	if got := GetAppConfig().FormatDateTime(evening); got != "16-09-2024 07:30" {
+		t.Errorf("FormatDateTime() = %q, want 16-09-2024 07:30", got)
+	}
+
+	// Storage is always UTC, whatever the zone of the value
+	if got := FormatDateForDB(date); got != "2024-09-14 15:00:00Z" {
+		t.Errorf("FormatDateForDB() = %q, want 2024-09-14 15:00:00Z", got)
+	}
+}
+
+func TestParseDateFromDB_Zones(t *testing.T) {
+	newYork, _ := time.LoadLocation("America/New_York")
+
+	tests := []struct {
+		name  string
+		value string
+		want  time.Time
+	}{
+		{name: "UTC timestamp", value: "2025-03-15 03:30:00Z", want: time.Date(2025, 3, 15, 3, 30, 0, 0, time.UTC)},
+		{name: "timestamp with offset", value: "2025-03-15 05:30:00+02:00", want: time.Date(2025, 3, 15, 3, 30, 0, 0, time.UTC)},
+		// Legacy rows left by a database that was not migrated are in the configured zone
+		{name: "legacy timestamp", value: "2025-03-15 03:30:00", want: time.Date(2025, 3, 15, 3, 30, 0, 0, newYork)},
+		{name: "legacy date", value: "2025-03-15", want: time.Date(2025, 3, 15, 0, 0, 0, 0, newYork)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseDateFromDB(tt.value, newYork)
+			if err != nil {
+				t.Fatal(unexpectedErrorString(err))
+			}
+			if !got.Equal(tt.want) || got.Location().String() != newYork.String() {
+				t.Errorf("ParseDateFromDB(%q) = %v, want %v in America/New_York", tt.value, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTimezone_DBStoreRoundTrip(t *testing.T) {
+	t.Setenv("TIMEZONE", "America/New_York")
+	newYork, _ := time.LoadLocation("America/New_York")
+
+	database := setupTestDB(t)
+	defer database.Close()
+	store := NewDBStoreWithDB(database, newYork)
+	ctx := context.Background()
+
+	// 23:30 on 14 March in New York is 03:30 on 15 March in UTC
+	lateNight := time.Date(2025, 3, 14, 23, 30, 0, 0, newYork)
+	added, err := store.AddWeight(ctx, WeightEntry{Weight: 70, Date: lateNight, Unit: "kg"})
+	if err != nil {
+		t.Fatal(failedTestEntryAdditionString(err))
+	}
+
+	var stored string
+	if err := database.QueryRow("SELECT date FROM weights WHERE id = ?", added.ID).Scan(&stored); err != nil {
+		t.Fatal(unexpectedErrorString(err))
+	}
+	if stored != "2025-03-15 03:30:00Z" {
+		t.Errorf("stored date = %q, want UTC 2025-03-15 03:30:00Z", stored)
+	}
+
+	entry, err := store.GetWeight(ctx, added.ID)
+	if err != nil {
+		t.Fatal(unexpectedErrorString(err))
+	}
+	if formatted := GetAppConfig().FormatDateTime(entry.Date); !entry.Date.Equal(lateNight) || formatted != "14-03-2025 23:30" {
+		t.Errorf("read back date = %v (%s), want 14-03-2025 23:30 in New York", entry.Date, formatted)
+	}
+
+	// Day boundaries follow the configured zone: the entry belongs to 14 March
+	from, _ := ParseDate("14-03-2025")
+	to := EndOfDay(from)
+	entries, err := store.ListWeights(ctx, ListOptions{FromDate: &from, ToDate: &to})
+	if err != nil {
+		t.Fatal(unexpectedErrorString(err))
+	}
+	if len(entries) != 1 {
+		t.Errorf("entries on 14 March = %d, want 1", len(entries))
+	}
+}
+
+func TestTimezone_AddCommand(t *testing.T) {
+	// The App clock reads 07:30 UTC on 14 March, which is 21:30 on 13 March in Honolulu
+	store := NewMockStore()
+	app, stdout, stderr, exitCode := newTestApp(store, map[string]string{"TIMEZONE": "Pacific/Honolulu"})
+
+	result := runCommand(t, app, stdout, stderr, exitCode, "add", "70", "--time", "22:00", "-o", "json")
+	if result.exitCode != 0 {
+		t.Fatalf("add exit code = %d, stderr %s", result.exitCode, result.stderr)
+	}
+	var added WeightEntry
+	if err := json.Unmarshal([]byte(result.stdout), &added); err != nil {
+		t.Fatalf("add output is not JSON: %v (%s)", err, result.stdout)
+	}
+	if want := time.Date(2025, 3, 14, 8, 0, 0, 0, time.UTC); !added.Date.Equal(want) {
+		t.Errorf("added date = %v, want 22:00 on 13 March in Honolulu (%v)", added.Date, want)
+	}
+
+	result = runCommand(t, app, stdout, stderr, exitCode, "add", "70", "--date", "today", "-o", "json")
+	if err := json.Unmarshal([]byte(result.stdout), &added); err != nil {
+		t.Fatalf("add output is not JSON: %v (%s)", err, result.stdout)
+	}
+	if want := time.Date(2025, 3, 13, 10, 0, 0, 0, time.UTC); !added.Date.Equal(want) {
+		t.Errorf("added date for today = %v, want midnight of 13 March in Honolulu (%v)", added.Date, want)
+	}
+}
@@ -0,0 +1,221 @@
+package tracker
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// app_test.go - Application context tests
+// * purpose: runs the cobra commands end to end through ExecuteWithApp against a MockStore.
+// * tests: MockStore (mock) with injected environment, streams, clock and exit
+// * focus: commands take every dependency from the App instead of the database or process.
+
+// commandResult is the outcome of running the root command once
+type commandResult struct {
+	stdout   string
+	stderr   string
+	exitCode int
+}
+
+// resetCommandFlags restores every flag of cmd and its subcommands to its default,
+// since cobra keeps flag values between executions of the same command tree
+func resetCommandFlags(cmd *cobra.Command) {
+	reset := func(flag *pflag.Flag) {
+		_ = flag.Value.Set(flag.DefValue)
+		flag.Changed = false
+	}
+	cmd.Flags().VisitAll(reset)
+	cmd.PersistentFlags().VisitAll(reset)
+	for _, child := range cmd.Commands() {
+		resetCommandFlags(child)
+	}
+}
+
+// newTestApp returns an App over store with captured streams, a fixed clock and
+// the given environment
+func newTestApp(store Store, env map[string]string) (*App, *bytes.Buffer, *bytes.Buffer, *int) {
+	var stdout, stderr bytes.Buffer
+	exitCode := 0
+	app := &App{
+		NewStore: func() (Store, error) { return store, nil },
+		Getenv:   func(key string) string { return env[key] },
+		In:       strings.NewReader(""),
+		Out:      &stdout,
+		Err:      &stderr,
+		Now:      func() time.Time { return time.Date(2025, 3, 14, 7, 30, 0, 0, time.UTC) },
+		Exit:     func(code int) { exitCode = code },
+	}
+	return app, &stdout, &stderr, &exitCode
+}
+
+// runCommand executes the root command with args through app
+func runCommand(t *testing.T, app *App, stdout, stderr *bytes.Buffer, exitCode *int, args ...string) commandResult {
+	t.Helper()
+	stdout.Reset()
+	stderr.Reset()
+	*exitCode = 0
+	defer resetCommandFlags(rootCmd)
+
+	rootCmd.SetArgs(args)
+	if err := ExecuteWithApp(app); err != nil {
+		t.Fatalf("ExecuteWithApp(%v) error = %v", args, err)
+	}
+	return commandResult{stdout: stdout.String(), stderr: stderr.String(), exitCode: *exitCode}
+}
+
+func TestExecuteWithApp_MockStore(t *testing.T) {
+	store := NewMockStore()
+	env := map[string]string{"DATE_INPUT_FORMAT": "yyyy-mm-dd", "DEFAULT_UNIT": "lbs"}
+	app, stdout, stderr, exitCode := newTestApp(store, env)
+
+	// Default date comes from the App clock, default unit from its environment
+	result := runCommand(t, app, stdout, stderr, exitCode, "add", "165.2", "-o", "json")
+	if result.exitCode != 0 {
+		t.Fatalf("add exit code = %d, stderr %s", result.exitCode, result.stderr)
+	}
+	var added WeightEntry
+	if err := json.Unmarshal([]byte(result.stdout), &added); err != nil {
+		t.Fatalf("add output is not JSON: %v (%s)", err, result.stdout)
+	}
+	if !added.Date.Equal(app.Now()) || added.Unit != "lbs" {
+		t.Errorf("added entry = %+v, want date from clock and unit lbs", added)
+	}
+
+	// Dates are parsed with the App's DATE_INPUT_FORMAT
+	result = runCommand(t, app, stdout, stderr, exitCode, "add", "70", "--date", "2025-03-01", "--unit", "kg")
+	if result.exitCode != 0 {
+		t.Fatalf("add --date exit code = %d, stderr %s", result.exitCode, result.stderr)
+	}
+
+	result = runCommand(t, app, stdout, stderr, exitCode, "list", "--output", "json", "--desc=false")
+	var entries []WeightEntry
+	if err := json.Unmarshal([]byte(result.stdout), &entries); err != nil {
+		t.Fatalf("list output is not JSON: %v (%s)", err, result.stdout)
+	}
+	if len(entries) != 2 || entries[0].Date.Format("2006-01-02") != "2025-03-01" {
+		t.Errorf("list = %+v, want the entry of 2025-03-01 first", entries)
+	}
+
+	result = runCommand(t, app, stdout, stderr, exitCode, "stats", "-o", "json", "--display-unit", "kg")
+	var stats WeightStatistics
+	if err := json.Unmarshal([]byte(result.stdout), &stats); err != nil {
+		t.Fatalf("stats output is not JSON: %v (%s)", err, result.stdout)
+	}
+	if stats.TotalEntries != 2 || stats.Unit != "kg" {
+		t.Errorf("stats = %+v, want 2 entries in kg", stats)
+	}
+
+	// Errors are rendered to the App's stderr and reported through its Exit
+	result = runCommand(t, app, stdout, stderr, exitCode, "delete", "99", "--confirm", "-o", "json")
+	if result.exitCode != 1 || !strings.Contains(result.stderr, `"error"`) || !strings.Contains(result.stderr, "not found") {
+		t.Errorf("delete missing entry = %+v, want exit code 1 and a JSON error", result)
+	}
+
+	result = runCommand(t, app, stdout, stderr, exitCode, "delete", "1", "--confirm")
+	if result.exitCode != 0 {
+		t.Fatalf("delete exit code = %d, stderr %s", result.exitCode, result.stderr)
+	}
+	if _, err := store.GetWeight(context.Background(), 1); !errors.Is(err, ErrEntryNotFound) {
+		t.Errorf("GetWeight() after delete error = %v, want ErrEntryNotFound", err)
+	}
+}
+
+func TestExecuteWithApp_Environment(t *testing.T) {
+	tests := []struct {
+		name     string
+		env      map[string]string
+		args     []string
+		wantUser string
+		wantExit int
+	}{
+		{name: "user from environment", env: map[string]string{"WEIGHT_TRACKER_USER": "alice"}, args: []string{"add", "62"}, wantUser: "alice"},
+		{name: "flag overrides environment", env: map[string]string{"WEIGHT_TRACKER_USER": "alice"}, args: []string{"--user", "bob", "add", "62"}, wantUser: "bob"},
+		{name: "date in default format rejected", env: map[string]string{"DATE_INPUT_FORMAT": "yyyy-mm-dd"}, args: []string{"add", "62", "--date", "01-03-2025"}, wantExit: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := NewMockStore()
+			app, stdout, stderr, exitCode := newTestApp(store, tt.env)
+
+			result := runCommand(t, app, stdout, stderr, exitCode, tt.args...)
+			if result.exitCode != tt.wantExit {
+				t.Fatalf("exit code = %d, want %d (stderr %s)", result.exitCode, tt.wantExit, result.stderr)
+			}
+			if tt.wantExit != 0 {
+				return
+			}
+
+			entries, err := store.ListWeights(context.Background(), ListOptions{})
+			if err != nil {
+				t.Fatal(unexpectedErrorString(err))
+			}
+			if len(entries) != 1 || entries[0].UserID != tt.wantUser {
+				t.Errorf("entries = %+v, want one entry for %q", entries, tt.wantUser)
+			}
+		})
+	}
+}
+
+func TestExecuteWithApp_StoreError(t *testing.T) {
+	app, stdout, stderr, exitCode := newTestApp(nil, nil)
+	app.NewStore = func() (Store, error) {
+		return nil, errors.New("database unavailable")
+	}
+
+	result := runCommand(t, app, stdout, stderr, exitCode, "list")
+	if result.exitCode != 1 || !strings.Contains(result.stderr, "database unavailable") {
+		t.Errorf("list with failing store = %+v, want exit code 1 and the store error", result)
+	}
+}
+
+func TestExecuteWithApp_DatabasePath(t *testing.T) {
+	// Without a NewStore, commands open the database named by the App's environment
+	dir := t.TempDir()
+	path := filepath.Join(dir, "app.db")
+	app, stdout, stderr, exitCode := newTestApp(nil, map[string]string{"DATABASE_PATH": path, "HOME": dir})
+	app.NewStore = nil
+
+	if result := runCommand(t, app, stdout, stderr, exitCode, "add", "62"); result.exitCode != 0 {
+		t.Fatalf("add exit code = %d, stderr %s", result.exitCode, result.stderr)
+	}
+	if _, err := os.Stat(path); err != nil {
+		t.Fatalf("database not created at DATABASE_PATH of the App: %v", err)
+	}
+
+	result := runCommand(t, app, stdout, stderr, exitCode, "list", "-o", "json")
+	var entries []WeightEntry
+	if err := json.Unmarshal([]byte(result.stdout), &entries); err != nil || len(entries) != 1 {
+		t.Errorf("list = %q (error %v), want the entry added to %s", result.stdout, err, path)
+	}
+}
+
+func TestAppFor_Defaults(t *testing.T) {
+	// Commands run directly (not through the root command) get the default App
+	cmd := &cobra.Command{}
+	if app := appFor(cmd); app.NewStore == nil || app.Getenv == nil || app.Now == nil || app.Exit == nil {
+		t.Errorf("appFor() without context = %+v, want the default App", app)
+	}
+
+	// Unset dependencies are filled in and streams default to the command's
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+	cmd.SetContext(WithApp(context.Background(), &App{}))
+	if err := setupApp(cmd, nil); err != nil {
+		t.Fatal(unexpectedErrorString(err))
+	}
+	app := appFor(cmd)
+	if app.Out != &out || app.NewStore == nil || app.Getenv == nil {
+		t.Errorf("setupApp() app = %+v, want defaults with the command's stdout", app)
+	}
+}
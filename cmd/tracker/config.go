@@ -0,0 +1,331 @@
+package tracker
+
+// config.go - Config file commands (config get, config set, config list, config validate)
+// Related files: config_file.go (file format and layering), app_config.go (date formats
+// and units), config_test.go (tests)
+// Settings are resolved from flags, then environment variables (and .env), then the
+// config file, then built-in defaults; these commands show and edit the file.
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/spf13/cobra"
+)
+
+// Sources of a configuration value, from highest to lowest precedence
+const (
+	ConfigSourceFlag    = "flag"
+	ConfigSourceEnv     = "env"
+	ConfigSourceFile    = "file"
+	ConfigSourceDefault = "default"
+)
+
+// ConfigValue is the effective value of a setting and where it came from
+type ConfigValue struct {
+	Key         string `json:"key" yaml:"key"`
+	Value       string `json:"value" yaml:"value"`
+	Source      string `json:"source" yaml:"source"`   // flag, env, file or default
+	EnvVar      string `json:"env_var" yaml:"env_var"` // Variable overriding the file
+	Description string `json:"description,omitempty" yaml:"description,omitempty"`
+}
+
+func (value ConfigValue) writeTable(w io.Writer, config AppConfig) error {
+	_ = config
+	// Just the value, so that scripts can use $(weight-tracker config get ...)
+	fmt.Fprintln(w, value.Value)
+	return nil
+}
+
+// configValueList is the result of config list: every setting with its source
+type configValueList []ConfigValue
+
+func (values configValueList) writeTable(w io.Writer, config AppConfig) error {
+	_ = config
+	for _, value := range values {
+		shown := value.Value
+		if shown == "" {
+			shown = "(not set)"
+		}
+		fmt.Fprintf(w, "* %s = %s (%s)\n", value.Key, shown, describeConfigSource(value))
+	}
+	return nil
+}
+
+// describeConfigSource returns a human-readable origin of a setting's value
+func describeConfigSource(value ConfigValue) string {
+	switch value.Source {
+	case ConfigSourceFlag:
+		return "flag"
+	case ConfigSourceEnv:
+		return "environment " + value.EnvVar
+	case ConfigSourceFile:
+		return "config file"
+	default:
+		return "default"
+	}
+}
+
+var configCmd = &cobra.Command{
+	Use:   "config",
+	Short: "Show and edit the config file",
+	Long: `Show and edit the config file at $XDG_CONFIG_HOME/weight-tracker/config.yaml
+(~/.config/weight-tracker/config.yaml by default, or the file named by
+WEIGHT_TRACKER_CONFIG).
+
+Settings are resolved in this order: flags (--user, --db), environment variables
+(including .env), the config file, built-in defaults.
+
+Keys:
+  date_input_format    Date format of command input (DATE_INPUT_FORMAT)
+  date_display_format  Date format of command output (DATE_DISPLAY_FORMAT)
+  default_unit         Weight unit used when none is given (DEFAULT_UNIT)
+  timezone             Time zone dates are entered and displayed in (TIMEZONE)
+  user                 User selected when --user is not given (WEIGHT_TRACKER_USER)
+  database_path        Path to the SQLite database (DATABASE_PATH)`,
+}
+
+var configGetCmd = &cobra.Command{
+	Use:   "get <key>",
+	Short: "Show the effective value of a setting",
+	Long: `Show the value a setting has for this invocation, taking flags, environment
+variables and the config file into account.
+
+Examples:
+  weight-tracker config get default_unit
+  weight-tracker config get date_input_format -o json`,
+	Args: cobra.ExactArgs(1),
+	Run:  runConfigGet,
+}
+
+var configSetCmd = &cobra.Command{
+	Use:   "set <key> <value>",
+	Short: "Store a setting in the config file",
+	Long: `Store a setting in the config file, creating the file if needed. The value is
+checked before it is written; an empty value removes the setting.
+
+Examples:
+  weight-tracker config set default_unit lbs
+  weight-tracker config set date_input_format yyyy-mm-dd
+  weight-tracker config set user ""`,
+	Args: cobra.ExactArgs(2),
+	Run:  runConfigSet,
+}
+
+var configListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "Show all settings with their source",
+	Long: `Show the effective value of every setting and whether it comes from a flag,
+an environment variable, the config file or the defaults.
+
+Examples:
+  weight-tracker config list
+  weight-tracker config list -o yaml`,
+	Args: cobra.NoArgs,
+	Run:  runConfigList,
+}
+
+var configValidateCmd = &cobra.Command{
+	Use:   "validate",
+	Short: "Check the config file and environment for errors",
+	Long: `Check that the config file can be read, contains only known keys and holds
+valid values, and that the environment variables hold valid values. Exits with
+status 1 and lists every problem otherwise.
+
+Examples:
+  weight-tracker config validate`,
+	Args: cobra.NoArgs,
+	Run:  runConfigValidate,
+}
+
+func init() {
+	configCmd.AddCommand(configGetCmd)
+	configCmd.AddCommand(configSetCmd)
+	configCmd.AddCommand(configListCmd)
+	configCmd.AddCommand(configValidateCmd)
+}
+
+// isConfigCommand reports whether cmd is the config command or one of its subcommands
+func isConfigCommand(cmd *cobra.Command) bool {
+	for c := cmd; c != nil; c = c.Parent() {
+		if c == configCmd {
+			return true
+		}
+	}
+	return false
+}
+
+// loadConfigFileFor reads the config file of the command's App and returns it with its path
+func loadConfigFileFor(cmd *cobra.Command) (ConfigFile, string, error) {
+	path := configFilePath(appFor(cmd).Getenv)
+	file, err := LoadConfigFile(path)
+	if err != nil {
+		return nil, path, err
+	}
+	return file, path, nil
+}
+
+// effectiveConfigValue resolves setting the way the other commands do
+func effectiveConfigValue(cmd *cobra.Command, setting configSetting, file ConfigFile) ConfigValue {
+	value := ConfigValue{
+		Key:         setting.Key,
+		Value:       setting.Default,
+		Source:      ConfigSourceDefault,
+		EnvVar:      setting.EnvVar,
+		Description: setting.Description,
+	}
+
+	if setting.Flag != "" {
+		if flag := cmd.Flags().Lookup(setting.Flag); flag != nil && flag.Changed {
+			value.Value = flag.Value.String()
+			value.Source = ConfigSourceFlag
+			return value
+		}
+	}
+	if envValue := appFor(cmd).Getenv(setting.EnvVar); envValue != "" {
+		value.Value = envValue
+		value.Source = ConfigSourceEnv
+		return value
+	}
+	if fileValue := file[setting.Key]; fileValue != "" {
+		value.Value = fileValue
+		value.Source = ConfigSourceFile
+	}
+	return value
+}
+
+// runConfigGetInternal contains the core logic and returns errors instead of terminating
+func runConfigGetInternal(cmd *cobra.Command, args []string) error {
+	renderer, err := rendererFor(cmd)
+	if err != nil {
+		return err
+	}
+
+	setting, err := lookupConfigSetting(args[0])
+	if err != nil {
+		return err
+	}
+
+	file, _, err := loadConfigFileFor(cmd)
+	if err != nil {
+		return err
+	}
+
+	return renderer.Render(effectiveConfigValue(cmd, setting, file))
+}
+
+// runConfigGet is the wrapper that handles errors for the CLI
+func runConfigGet(cmd *cobra.Command, args []string) {
+	if err := runConfigGetInternal(cmd, args); err != nil {
+		exitWithError(cmd, err)
+	}
+}
+
+// runConfigSetInternal contains the core logic and returns errors instead of terminating
+func runConfigSetInternal(cmd *cobra.Command, args []string) error {
+	renderer, err := rendererFor(cmd)
+	if err != nil {
+		return err
+	}
+
+	key, value := args[0], args[1]
+	setting, err := lookupConfigSetting(key)
+	if err != nil {
+		return err
+	}
+	if err := validateConfigValue(key, value); err != nil {
+		return err
+	}
+
+	file, path, err := loadConfigFileFor(cmd)
+	if err != nil {
+		return err
+	}
+	if value == "" {
+		delete(file, key)
+	} else {
+		file[key] = value
+	}
+	if err := file.Save(path); err != nil {
+		return err
+	}
+
+	if renderer.Structured() {
+		return renderer.Render(effectiveConfigValue(cmd, setting, file))
+	}
+
+	if value == "" {
+		fmt.Fprintf(cmd.OutOrStdout(), "Removed %s from %s\n", key, path)
+	} else {
+		fmt.Fprintf(cmd.OutOrStdout(), "Set %s = %s in %s\n", key, value, path)
+	}
+	if appFor(cmd).Getenv(setting.EnvVar) != "" {
+		fmt.Fprintf(cmd.OutOrStdout(), "Note: %s is set in the environment and takes precedence over the config file.\n", setting.EnvVar)
+	}
+	return nil
+}
+
+// runConfigSet is the wrapper that handles errors for the CLI
+func runConfigSet(cmd *cobra.Command, args []string) {
+	if err := runConfigSetInternal(cmd, args); err != nil {
+		exitWithError(cmd, err)
+	}
+}
+
+// runConfigListInternal contains the core logic and returns errors instead of terminating
+func runConfigListInternal(cmd *cobra.Command, args []string) error {
+	_ = args
+
+	renderer, err := rendererFor(cmd)
+	if err != nil {
+		return err
+	}
+
+	file, _, err := loadConfigFileFor(cmd)
+	if err != nil {
+		return err
+	}
+
+	values := make([]ConfigValue, 0, len(configSettings))
+	for _, setting := range configSettings {
+		values = append(values, effectiveConfigValue(cmd, setting, file))
+	}
+	return renderer.Render(configValueList(values))
+}
+
+// runConfigList is the wrapper that handles errors for the CLI
+func runConfigList(cmd *cobra.Command, args []string) {
+	if err := runConfigListInternal(cmd, args); err != nil {
+		exitWithError(cmd, err)
+	}
+}
+
+// runConfigValidateInternal contains the core logic and returns errors instead of terminating
+func runConfigValidateInternal(cmd *cobra.Command, args []string) error {
+	_ = args
+
+	file, path, err := loadConfigFileFor(cmd)
+	if err != nil {
+		return err
+	}
+	if err := file.Validate(); err != nil {
+		return fmt.Errorf("config file %s: %w", path, err)
+	}
+	if _, err := LoadAppConfigFromEnv(appFor(cmd).Getenv); err != nil {
+		return err
+	}
+
+	if path == "" || len(file) == 0 {
+		fmt.Fprintln(cmd.OutOrStdout(), "Configuration is valid (no config file settings, using environment and defaults).")
+		return nil
+	}
+	fmt.Fprintf(cmd.OutOrStdout(), "Configuration is valid (%s).\n", path)
+	return nil
+}
+
+// runConfigValidate is the wrapper that handles errors for the CLI
+func runConfigValidate(cmd *cobra.Command, args []string) {
+	if err := runConfigValidateInternal(cmd, args); err != nil {
+		exitWithError(cmd, err)
+	}
+}
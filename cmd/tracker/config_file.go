@@ -0,0 +1,197 @@
+package tracker
+
+// config_file.go - Config file holding persistent settings
+// Related files: app_config.go (date formats and units), config.go (config command),
+// app.go (layers the file under the environment in setupApp), config_test.go (tests)
+// Settings are read from $XDG_CONFIG_HOME/weight-tracker/config.yaml. Environment
+// variables (including those loaded from .env) override the file, and flags such as
+// --user and --db override both.
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ConfigPathEnvVar is the environment variable that points to a config file elsewhere
+const ConfigPathEnvVar = "WEIGHT_TRACKER_CONFIG"
+
+const (
+	configDirName  = "weight-tracker"
+	configFileName = "config.yaml"
+)
+
+// configSetting describes a setting that can be stored in the config file
+type configSetting struct {
+	Key         string // Key in the config file
+	EnvVar      string // Environment variable overriding the config file
+	Flag        string // Global flag overriding everything, if any
+	Default     string // Value used when the setting is not configured
+	Description string
+	validate    func(value string) error
+}
+
+// configSettings lists the settings supported by the config file
+var configSettings = []configSetting{
+	{Key: "date_input_format", EnvVar: "DATE_INPUT_FORMAT", Default: "dd-mm-yyyy", Description: "Date format of command input", validate: validateDateFormatName},
+	{Key: "date_display_format", EnvVar: "DATE_DISPLAY_FORMAT", Default: "dd-mm-yyyy", Description: "Date format of command output", validate: validateDateFormatName},
+	{Key: "default_unit", EnvVar: "DEFAULT_UNIT", Default: DefaultUnit, Description: "Weight unit used when none is given (kg or lbs)", validate: validateDefaultUnit},
+	{Key: "timezone", EnvVar: "TIMEZONE", Default: DefaultTimezone, Description: "Time zone dates are entered and displayed in (IANA name, UTC or Local)", validate: validateTimezone},
+	{Key: "user", EnvVar: UserEnvVar, Flag: "user", Description: "User selected when --user is not given (empty = all users)"},
+	{Key: "database_path", EnvVar: "DATABASE_PATH", Flag: "db", Description: "Path to the SQLite database (empty = ~/.local/share/weight-tracker/weights.db)"},
+}
+
+// validateDateFormatName returns an error unless name is a supported date format name
+func validateDateFormatName(name string) error {
+	_, err := parseDateFormatName(name)
+	return err
+}
+
+// lookupConfigSetting returns the setting stored under key
+func lookupConfigSetting(key string) (configSetting, error) {
+	for _, setting := range configSettings {
+		if setting.Key == key {
+			return setting, nil
+		}
+	}
+	return configSetting{}, fmt.Errorf("unknown config key '%s': use one of %s", key, strings.Join(configKeys(), ", "))
+}
+
+// configKeys returns the keys of all supported settings
+func configKeys() []string {
+	keys := make([]string, len(configSettings))
+	for i, setting := range configSettings {
+		keys[i] = setting.Key
+	}
+	return keys
+}
+
+// validateConfigValue checks value against the setting stored under key
+func validateConfigValue(key, value string) error {
+	setting, err := lookupConfigSetting(key)
+	if err != nil {
+		return err
+	}
+	if setting.validate == nil || value == "" {
+		return nil
+	}
+	if err := setting.validate(value); err != nil {
+		return fmt.Errorf("invalid %s: %w", key, err)
+	}
+	return nil
+}
+
+// ConfigFile holds the settings stored in a config file, by key
+type ConfigFile map[string]string
+
+// configFilePath returns the location of the config file: WEIGHT_TRACKER_CONFIG when set,
+// otherwise $XDG_CONFIG_HOME/weight-tracker/config.yaml, falling back to
+// ~/.config/weight-tracker/config.yaml. It returns "" when no location can be determined.
+func configFilePath(getEnv func(string) string) string {
+	if path := getEnv(ConfigPathEnvVar); path != "" {
+		return path
+	}
+	configHome := getEnv("XDG_CONFIG_HOME")
+	// The spec requires relative paths to be ignored
+	if configHome == "" || !filepath.IsAbs(configHome) {
+		home := getEnv("HOME")
+		if home == "" {
+			return ""
+		}
+		configHome = filepath.Join(home, ".config")
+	}
+	return filepath.Join(configHome, configDirName, configFileName)
+}
+
+// LoadConfigFile reads the config file at path. A missing file (or an empty path)
+// yields an empty ConfigFile.
+func LoadConfigFile(path string) (ConfigFile, error) {
+	file := ConfigFile{}
+	if path == "" {
+		return file, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return file, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	if err := yaml.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("failed to parse config file %s: %w", path, err)
+	}
+	if file == nil {
+		// The file holds no settings (e.g. only comments)
+		file = ConfigFile{}
+	}
+	return file, nil
+}
+
+// Save writes the config file to path, creating its directory if needed
+func (f ConfigFile) Save(path string) error {
+	if path == "" {
+		return fmt.Errorf("cannot determine the config file location: set HOME, XDG_CONFIG_HOME or %s", ConfigPathEnvVar)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create config directory: %w", err)
+	}
+
+	data, err := yaml.Marshal(map[string]string(f))
+	if err != nil {
+		return fmt.Errorf("failed to encode config file: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write config file: %w", err)
+	}
+	return nil
+}
+
+// Validate returns an error listing every unknown key and invalid value in the file
+func (f ConfigFile) Validate() error {
+	keys := make([]string, 0, len(f))
+	for key := range f {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var errs []error
+	for _, key := range keys {
+		if err := validateConfigValue(key, f[key]); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// layer returns an environment lookup where getEnv takes precedence over the file
+func (f ConfigFile) layer(getEnv func(string) string) func(string) string {
+	fileValues := make(map[string]string, len(f))
+	for _, setting := range configSettings {
+		if value := f[setting.Key]; value != "" {
+			fileValues[setting.EnvVar] = value
+		}
+	}
+	return func(key string) string {
+		if value := getEnv(key); value != "" {
+			return value
+		}
+		return fileValues[key]
+	}
+}
+
+// withConfigFile returns getEnv with the settings of the config file layered under it
+func withConfigFile(getEnv func(string) string) (func(string) string, error) {
+	file, err := LoadConfigFile(configFilePath(getEnv))
+	if err != nil {
+		return nil, err
+	}
+	return file.layer(getEnv), nil
+}
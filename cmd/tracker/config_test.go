@@ -0,0 +1,236 @@
+package tracker
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// config_test.go - Config file tests
+// * purpose: tests loading, validating and layering the config file and the config commands.
+// * tests: MockStore (mock) with a config file in a temporary XDG_CONFIG_HOME
+// * focus: flags > environment > config file > defaults, and explicit errors for invalid values.
+
+// writeConfigFile writes content to the config file under configHome
+func writeConfigFile(t *testing.T, configHome, content string) string {
+	t.Helper()
+	path := filepath.Join(configHome, "weight-tracker", "config.yaml")
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(unexpectedErrorString(err))
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(unexpectedErrorString(err))
+	}
+	return path
+}
+
+func TestLoadAppConfigFromEnv_InvalidValues(t *testing.T) {
+	tests := []struct {
+		name    string
+		env     map[string]string
+		wantErr []string
+	}{
+		{name: "valid", env: map[string]string{"DATE_INPUT_FORMAT": "yyyy-mm-dd", "DEFAULT_UNIT": "lbs"}},
+		{name: "unknown input format", env: map[string]string{"DATE_INPUT_FORMAT": "yyyymmdd"}, wantErr: []string{"DATE_INPUT_FORMAT", "yyyymmdd"}},
+		{name: "unknown display format", env: map[string]string{"DATE_DISPLAY_FORMAT": "day first"}, wantErr: []string{"DATE_DISPLAY_FORMAT", "day first"}},
+		{name: "unknown unit", env: map[string]string{"DEFAULT_UNIT": "stone"}, wantErr: []string{"DEFAULT_UNIT", "stone"}},
+		{name: "every problem reported", env: map[string]string{"DATE_INPUT_FORMAT": "x", "DEFAULT_UNIT": "g"}, wantErr: []string{"DATE_INPUT_FORMAT", "DEFAULT_UNIT"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config, err := LoadAppConfigFromEnv(func(key string) string { return tt.env[key] })
+			if len(tt.wantErr) == 0 {
+				if err != nil {
+					t.Fatal(unexpectedErrorString(err))
+				}
+				return
+			}
+			if err == nil {
+				t.Fatalf("LoadAppConfigFromEnv() error = nil, want an error mentioning %v", tt.wantErr)
+			}
+			for _, want := range tt.wantErr {
+				if !strings.Contains(err.Error(), want) {
+					t.Errorf("LoadAppConfigFromEnv() error = %v, want it to mention %q", err, want)
+				}
+			}
+			// Invalid settings keep their defaults
+			if config.DefaultUnit != DefaultUnit && tt.env["DEFAULT_UNIT"] != "" {
+				t.Errorf("DefaultUnit = %q, want default %q", config.DefaultUnit, DefaultUnit)
+			}
+		})
+	}
+}
+
+func TestConfigFile_Layering(t *testing.T) {
+	configHome := t.TempDir()
+	writeConfigFile(t, configHome, "default_unit: lbs\ndate_input_format: yyyy-mm-dd\nuser: alice\n")
+
+	tests := []struct {
+		name     string
+		env      map[string]string
+		args     []string
+		wantUser string
+		wantUnit string
+	}{
+		{name: "config file", args: []string{"add", "150", "--date", "2025-03-01"}, wantUser: "alice", wantUnit: "lbs"},
+		{name: "environment overrides file", env: map[string]string{"DEFAULT_UNIT": "kg", UserEnvVar: "bob"}, args: []string{"add", "70", "--date", "2025-03-01"}, wantUser: "bob", wantUnit: "kg"},
+		{name: "flag overrides environment", env: map[string]string{UserEnvVar: "bob"}, args: []string{"--user", "carol", "add", "150", "--date", "2025-03-01"}, wantUser: "carol", wantUnit: "lbs"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env := map[string]string{"XDG_CONFIG_HOME": configHome}
+			for key, value := range tt.env {
+				env[key] = value
+			}
+			store := NewMockStore()
+			app, stdout, stderr, exitCode := newTestApp(store, env)
+
+			result := runCommand(t, app, stdout, stderr, exitCode, append(tt.args, "-o", "json")...)
+			if result.exitCode != 0 {
+				t.Fatalf("exit code = %d, stderr %s", result.exitCode, result.stderr)
+			}
+			var added WeightEntry
+			if err := json.Unmarshal([]byte(result.stdout), &added); err != nil {
+				t.Fatalf("add output is not JSON: %v (%s)", err, result.stdout)
+			}
+			if added.UserID != tt.wantUser || added.Unit != tt.wantUnit {
+				t.Errorf("added entry = %+v, want user %q and unit %q", added, tt.wantUser, tt.wantUnit)
+			}
+		})
+	}
+}
+
+func TestConfigFile_DisplaySettings(t *testing.T) {
+	// Output follows the config file of the App running the command, without the file
+	// being copied into the process environment
+	configHome := t.TempDir()
+	writeConfigFile(t, configHome, "date_input_format: yyyy-mm-dd\ndate_display_format: yyyy/mm/dd\ntimezone: America/New_York\n")
+	app, stdout, stderr, exitCode := newTestApp(NewMockStore(), map[string]string{"XDG_CONFIG_HOME": configHome})
+
+	if result := runCommand(t, app, stdout, stderr, exitCode, "add", "70", "--date", "2025-03-01"); result.exitCode != 0 {
+		t.Fatalf("add exit code = %d, stderr %s", result.exitCode, result.stderr)
+	}
+	result := runCommand(t, app, stdout, stderr, exitCode, "list")
+	if result.exitCode != 0 || !strings.Contains(result.stdout, "2025/03/01 00:00") {
+		t.Errorf("list = %+v, want the date in the display format and time zone of the config file", result)
+	}
+
+	for _, key := range []string{"DATE_DISPLAY_FORMAT", "TIMEZONE"} {
+		if value := os.Getenv(key); value != "" {
+			t.Errorf("%s = %q in the process environment, want the config file kept out of it", key, value)
+		}
+	}
+	if format := GetAppConfig().DateFormat.DisplayFormat; format != DefaultDisplayFormat {
+		t.Errorf("display format after the command = %q, want the default %q", format, DefaultDisplayFormat)
+	}
+}
+
+func TestConfigFile_InvalidValuesRejected(t *testing.T) {
+	tests := []struct {
+		name    string
+		file    string
+		env     map[string]string
+		wantErr string
+	}{
+		{name: "invalid unit in file", file: "default_unit: stone\n", wantErr: "unrecognized unit 'stone'"},
+		{name: "unknown key in file", file: "colour: blue\n", wantErr: "unknown config key 'colour'"},
+		{name: "malformed file", file: "default_unit: [kg\n", wantErr: "failed to parse config file"},
+		{name: "invalid format in environment", env: map[string]string{"DATE_DISPLAY_FORMAT": "iso"}, wantErr: "invalid DATE_DISPLAY_FORMAT"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			configHome := t.TempDir()
+			if tt.file != "" {
+				writeConfigFile(t, configHome, tt.file)
+			}
+			env := map[string]string{"XDG_CONFIG_HOME": configHome}
+			for key, value := range tt.env {
+				env[key] = value
+			}
+			app, stdout, stderr, _ := newTestApp(NewMockStore(), env)
+			defer resetCommandFlags(rootCmd)
+
+			rootCmd.SetArgs([]string{"list"})
+			err := ExecuteWithApp(app)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("ExecuteWithApp() error = %v, want %q", err, tt.wantErr)
+			}
+			if stdout.Len() != 0 || !strings.Contains(stderr.String(), tt.wantErr) {
+				t.Errorf("stdout = %q, stderr = %q, want only the error on stderr", stdout.String(), stderr.String())
+			}
+		})
+	}
+}
+
+func TestConfigCommands(t *testing.T) {
+	configHome := t.TempDir()
+	app, stdout, stderr, exitCode := newTestApp(NewMockStore(), map[string]string{"XDG_CONFIG_HOME": configHome, "DATE_DISPLAY_FORMAT": "yyyy/mm/dd"})
+	run := func(args ...string) commandResult {
+		return runCommand(t, app, stdout, stderr, exitCode, args...)
+	}
+
+	if result := run("config", "get", "default_unit"); strings.TrimSpace(result.stdout) != "kg" {
+		t.Errorf("config get default_unit = %q, want the default kg", result.stdout)
+	}
+
+	result := run("config", "set", "default_unit", "lbs")
+	if result.exitCode != 0 || !strings.Contains(result.stdout, "Set default_unit = lbs") {
+		t.Fatalf("config set = %+v, want success", result)
+	}
+	if result := run("config", "set", "default_unit", "stone"); result.exitCode != 1 || !strings.Contains(result.stderr, "unrecognized unit") {
+		t.Errorf("config set invalid unit = %+v, want exit code 1", result)
+	}
+	if result := run("config", "set", "colour", "blue"); result.exitCode != 1 || !strings.Contains(result.stderr, "unknown config key") {
+		t.Errorf("config set unknown key = %+v, want exit code 1", result)
+	}
+
+	// The environment variable wins over the value stored in the file
+	result = run("config", "set", "date_display_format", "dd/mm/yyyy")
+	if !strings.Contains(result.stdout, "DATE_DISPLAY_FORMAT is set in the environment") {
+		t.Errorf("config set overridden key output = %q, want a note about the environment", result.stdout)
+	}
+
+	result = run("config", "list", "-o", "json")
+	var values []ConfigValue
+	if err := json.Unmarshal([]byte(result.stdout), &values); err != nil {
+		t.Fatalf("config list output is not JSON: %v (%s)", err, result.stdout)
+	}
+	sources := map[string]string{}
+	for _, value := range values {
+		sources[value.Key] = value.Value + " " + value.Source
+	}
+	want := map[string]string{
+		"default_unit":        "lbs file",
+		"date_display_format": "yyyy/mm/dd env",
+		"date_input_format":   "dd-mm-yyyy default",
+	}
+	for key, expected := range want {
+		if sources[key] != expected {
+			t.Errorf("config list %s = %q, want %q", key, sources[key], expected)
+		}
+	}
+	if result := run("--user", "dana", "config", "get", "user", "-o", "json"); !strings.Contains(result.stdout, `"source": "flag"`) {
+		t.Errorf("config get user with --user = %q, want source flag", result.stdout)
+	}
+
+	if result := run("config", "validate"); result.exitCode != 0 || !strings.Contains(result.stdout, "valid") {
+		t.Errorf("config validate = %+v, want success", result)
+	}
+
+	// The config commands still run with a broken file so that it can be repaired
+	writeConfigFile(t, configHome, "default_unit: stone\n")
+	if result := run("config", "validate"); result.exitCode != 1 || !strings.Contains(result.stderr, "default_unit") {
+		t.Errorf("config validate with invalid file = %+v, want exit code 1 naming default_unit", result)
+	}
+	if result := run("config", "set", "default_unit", "kg"); result.exitCode != 0 {
+		t.Errorf("config set repairing the file = %+v, want success", result)
+	}
+	if result := run("config", "set", "default_unit", ""); !strings.Contains(result.stdout, "Removed default_unit") {
+		t.Errorf("config set with empty value = %q, want the key removed", result.stdout)
+	}
+}
@@ -0,0 +1,89 @@
+package tracker
+
+// dashboard.go - Web dashboard embedded in the binary (serve --ui)
+// Related files: api.go (REST API used by the dashboard), graph.go (RenderHTMLChart),
+// web/ (dashboard page, script and styles), dashboard_test.go (tests)
+// Routes added to the API:
+//   GET /               dashboard page
+//   GET /static/...     dashboard script and styles
+//   GET /chart          go-echarts chart of the filtered entries, embedded by the page
+
+import (
+	"embed"
+	"fmt"
+	"io/fs"
+	"net/http"
+)
+
+//go:embed web
+var webFiles embed.FS
+
+// dashboardFiles returns the embedded dashboard files rooted at web/
+func dashboardFiles() fs.FS {
+	files, err := fs.Sub(webFiles, "web")
+	if err != nil {
+		// The web directory is embedded at build time, so this cannot fail at runtime
+		panic(fmt.Sprintf("embedded dashboard files missing: %v", err))
+	}
+	return files
+}
+
+// registerDashboardRoutes adds the dashboard page, its static files and the chart endpoint to mux
+func registerDashboardRoutes(mux *http.ServeMux, server *apiServer) {
+	files := dashboardFiles()
+
+	mux.HandleFunc("GET /{$}", func(w http.ResponseWriter, r *http.Request) {
+		http.ServeFileFS(w, r, files, "index.html")
+	})
+	mux.Handle("GET /static/", http.StripPrefix("/static/", http.FileServerFS(files)))
+	mux.HandleFunc("GET /chart", server.handle(server.chart))
+}
+
+// chart handles GET /chart: an HTML page with the chart of the entries selected by
+// from, to and user, in display_unit, with the trend and goal overlays
+func (s *apiServer) chart(w http.ResponseWriter, r *http.Request) error {
+	query := r.URL.Query()
+
+	unit, err := resolveDisplayUnitFromEnv(query.Get("display_unit"), s.app.Getenv)
+	if err != nil {
+		return badRequest("%v", err)
+	}
+
+	fromDate, toDate, err := s.dateRangeFromQuery(r)
+	if err != nil {
+		return err
+	}
+
+	trendOptions, err := trendOptionsFromQuery(r)
+	if err != nil {
+		return err
+	}
+
+	userID := query.Get("user")
+	entries, err := s.store.ListWeights(r.Context(), ListOptions{FromDate: fromDate, ToDate: toDate, UserID: userID})
+	if err != nil {
+		return fmt.Errorf("failed to list weights: %w", err)
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	if len(entries) == 0 {
+		// The page is shown in a frame, so explain instead of returning an error
+		_, err := fmt.Fprintln(w, `<!DOCTYPE html><p style="font-family: sans-serif; color: #666">No weight entries match the current filters.</p>`)
+		return err
+	}
+
+	goal, err := loadGoal(r.Context(), s.store, userID, unit)
+	if err != nil {
+		return fmt.Errorf("failed to load goal: %w", err)
+	}
+
+	return RenderHTMLChart(w, entries, GraphOptions{
+		OutputType:  OutputHTML,
+		Title:       fmt.Sprintf("Weight Tracking Chart (%d entries)", len(entries)),
+		DisplayUnit: unit,
+		Trend:       trendOptions,
+		Goal:        goal,
+		DateLayout:  GetAppConfigFromEnv(s.app.Getenv).DateFormat.DisplayFormat,
+		Responsive:  true,
+	})
+}
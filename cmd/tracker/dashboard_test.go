@@ -0,0 +1,98 @@
+package tracker
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+// dashboard_test.go - Web dashboard tests
+// * purpose: tests the embedded dashboard routes of 'serve --ui' against a MockStore.
+// * tests: httptest requests for the page, static files and chart endpoint
+// * focus: embedded files are served, charts render from the Store, API-only mode hides the UI.
+
+func TestDashboard_Routes(t *testing.T) {
+	store := NewMockStore()
+	seedUserEntries(t, store)
+	if _, err := store.SetGoal(context.Background(), Goal{UserID: "alice", TargetWeight: 60, Unit: "kg", StartWeight: 62}); err != nil {
+		t.Fatal(unexpectedErrorString(err))
+	}
+	app := newTestAPIApp(store, nil)
+	dashboard := NewDashboardHandler(app, store)
+	apiOnly := NewAPIHandler(app, store)
+
+	tests := []struct {
+		name            string
+		handler         http.Handler
+		target          string
+		wantStatus      int
+		wantContentType string
+		wantBody        []string
+	}{
+		{name: "dashboard page", handler: dashboard, target: "/", wantStatus: http.StatusOK, wantContentType: "text/html", wantBody: []string{"<title>Weight Tracker</title>", "/static/dashboard.js"}},
+		{name: "dashboard script", handler: dashboard, target: "/static/dashboard.js", wantStatus: http.StatusOK, wantContentType: "javascript", wantBody: []string{"/entries", "/stats", "/chart"}},
+		{name: "dashboard styles", handler: dashboard, target: "/static/dashboard.css", wantStatus: http.StatusOK, wantContentType: "text/css"},
+		{name: "chart with trend and goal", handler: dashboard, target: "/chart?user=alice&trend=ema", wantStatus: http.StatusOK, wantContentType: "text/html", wantBody: []string{"Weight Tracking Chart (2 entries)", "EMA trend", "Goal: ", `width:100%`}},
+		{name: "chart without entries", handler: dashboard, target: "/chart?user=carol", wantStatus: http.StatusOK, wantContentType: "text/html", wantBody: []string{"No weight entries match"}},
+		{name: "chart with invalid filter", handler: dashboard, target: "/chart?from=someday", wantStatus: http.StatusBadRequest, wantContentType: "application/json", wantBody: []string{"invalid date"}},
+		{name: "api still served", handler: dashboard, target: "/entries?user=bob", wantStatus: http.StatusOK, wantContentType: "application/json", wantBody: []string{`"weight":88`}},
+		{name: "no dashboard without --ui", handler: apiOnly, target: "/", wantStatus: http.StatusNotFound},
+		{name: "no chart without --ui", handler: apiOnly, target: "/chart", wantStatus: http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			response := doAPIRequest(tt.handler, "GET", tt.target, "")
+
+			if response.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d (body %s)", response.Code, tt.wantStatus, response.Body.String())
+			}
+			if contentType := response.Header().Get("Content-Type"); !strings.Contains(contentType, tt.wantContentType) {
+				t.Errorf("Content-Type = %q, want it to contain %q", contentType, tt.wantContentType)
+			}
+			for _, want := range tt.wantBody {
+				if !strings.Contains(response.Body.String(), want) {
+					t.Errorf("body does not contain %q", want)
+				}
+			}
+		})
+	}
+}
+
+func TestRenderHTMLChart(t *testing.T) {
+	baseDate := time.Date(2025, 1, 1, 7, 0, 0, 0, time.UTC)
+	entries := []WeightEntry{
+		{Weight: 176.4, Date: baseDate.AddDate(0, 0, 1), Unit: "lbs"},
+		{Weight: 80.5, Date: baseDate, Unit: "kg"},
+	}
+
+	var buf bytes.Buffer
+	if err := RenderHTMLChart(&buf, entries, GraphOptions{Title: "Rendered", DisplayUnit: "kg"}); err != nil {
+		t.Fatal(unexpectedErrorString(err))
+	}
+
+	output := buf.String()
+	for _, want := range []string{"Rendered", "Weight (kg)", "1600px"} {
+		if !strings.Contains(output, want) {
+			t.Errorf("chart does not contain %q", want)
+		}
+	}
+
+	if err := RenderHTMLChart(&buf, nil, GraphOptions{}); err == nil {
+		t.Errorf("RenderHTMLChart() expected error for no entries but got none")
+	}
+}
+
+func TestDashboard_ChartAssets(t *testing.T) {
+	// The dashboard chart loads ECharts from the go-echarts CDN, like chart files
+	store := NewMockStore()
+	seedUserEntries(t, store)
+	dashboard := NewDashboardHandler(newTestAPIApp(store, nil), store)
+	response := doAPIRequest(dashboard, "GET", "/chart", "")
+	if !strings.Contains(response.Body.String(), `src="https://go-echarts.github.io/go-echarts-assets/assets/echarts.min.js"`) {
+		t.Errorf("dashboard chart does not load ECharts from the go-echarts CDN:\n%s", response.Body.String())
+	}
+}
@@ -3,7 +3,7 @@ package tracker
 import (
 	"context"
 	"fmt"
-	"os"
+	"io"
 	"strconv"
 
 	"github.com/spf13/cobra"
@@ -32,8 +32,15 @@ func init() {
 
 // runDeleteInternal contains the core logic and returns errors instead of terminating
 func runDeleteInternal(cmd *cobra.Command, args []string) error {
+	renderer, err := rendererFor(cmd)
+	if err != nil {
+		return err
+	}
+
+	app := appFor(cmd)
+
 	// Create store instance
-	store, err := NewDBStore()
+	store, err := app.NewStore()
 	if err != nil {
 		return fmt.Errorf("could not create store: %w", err)
 	}
@@ -56,22 +63,28 @@ func runDeleteInternal(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to find weight entry with ID %d: %w", id, err)
 	}
 
-	// Show what will be deleted
-	fmt.Printf("Found weight entry to delete:\n")
-	printWeightEntry(existingEntry)
+	// Entries of other users can't be modified while a user is selected
+	if err := checkEntryOwner(existingEntry, resolveUser(cmd)); err != nil {
+		return err
+	}
 
 	// Handle confirmation unless --confirm or --force is used
 	confirmDelete, _ := cmd.Flags().GetBool("confirm")
 	forceDelete, _ := cmd.Flags().GetBool("force")
 
 	if !confirmDelete && !forceDelete {
-		fmt.Print("Are you sure you want to delete this entry? (y/N): ")
-		var response string
-		fmt.Scanln(&response)
+		// Keep the prompt off stdout when it carries machine-readable output
+		promptOut := cmd.OutOrStdout()
+		if renderer.Structured() {
+			promptOut = cmd.ErrOrStderr()
+		}
 
-		if response != "y" && response != "Y" && response != "yes" && response != "Yes" {
-			fmt.Println("Deletion cancelled.")
-			return nil
+		// Show what will be deleted
+		fmt.Fprintf(promptOut, "Found weight entry to delete:\n")
+		printWeightEntry(promptOut, existingEntry, GetAppConfigFromEnv(app.Getenv))
+
+		if !confirm(cmd.InOrStdin(), promptOut, "Are you sure you want to delete this entry?") {
+			return renderer.Render(deleteResult{Deleted: false, Entry: existingEntry})
 		}
 	}
 
@@ -81,14 +94,28 @@ func runDeleteInternal(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to delete weight entry: %w", err)
 	}
 
-	fmt.Printf("Successfully deleted weight entry with ID %d.\n", id)
+	return renderer.Render(deleteResult{Deleted: true, Entry: existingEntry})
+}
+
+// deleteResult is the result of the delete command; Deleted is false when the user cancelled
+type deleteResult struct {
+	Deleted bool        `json:"deleted" yaml:"deleted"`
+	Entry   WeightEntry `json:"entry" yaml:"entry"`
+}
+
+func (result deleteResult) writeTable(w io.Writer, config AppConfig) error {
+	_ = config
+	if !result.Deleted {
+		fmt.Fprintln(w, "Deletion cancelled.")
+		return nil
+	}
+	fmt.Fprintf(w, "Successfully deleted weight entry with ID %d.\n", result.Entry.ID)
 	return nil
 }
 
 // runDelete is the cobra command wrapper that handles errors appropriately for CLI usage
 func runDelete(cmd *cobra.Command, args []string) {
 	if err := runDeleteInternal(cmd, args); err != nil {
-		fmt.Fprintf(cmd.ErrOrStderr(), "Error: %v\n", err)
-		os.Exit(1)
+		exitWithError(cmd, err)
 	}
 }
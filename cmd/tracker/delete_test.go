@@ -14,7 +14,7 @@ func TestDeleteCommand_Integration(t *testing.T) {
 	db := setupTestDB(t)
 	defer db.Close()
 
-	store := NewDBStoreWithDB(db)
+	store := NewDBStoreWithDB(db, time.UTC)
 	ctx := context.Background()
 
 	tests := []struct {
@@ -249,7 +249,7 @@ func TestDeleteCommand_CLI_Integration(t *testing.T) {
 			defer testDB.Close()
 
 			// Create store with test database
-			store := NewDBStoreWithDB(testDB)
+			store := NewDBStoreWithDB(testDB, time.UTC)
 			ctx := context.Background()
 
 			// Add a test entry to delete (only if we have a valid ID)
@@ -0,0 +1,227 @@
+package tracker
+
+// export.go - Export command
+// Related files: list.go (shared filter flags via buildListOptions), import.go (reads CSV back in)
+// Writes weight entries as CSV, JSON or NDJSON to stdout or a file for backups and analysis.
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// ExportFormat represents the serialization format of an export
+type ExportFormat string
+
+const (
+	ExportCSV    ExportFormat = "csv"
+	ExportJSON   ExportFormat = "json"
+	ExportNDJSON ExportFormat = "ndjson"
+)
+
+// csvExportHeader is the header row of CSV exports
+var csvExportHeader = []string{"id", "date", "weight", "unit", "note", "user_id"}
+
+// csvExportDateLayout formats CSV dates as wall-clock time in the configured time zone,
+// which import reads back with --date-format yyyy-mm-dd
+const csvExportDateLayout = "2006-01-02 15:04:05"
+
+var exportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Export weight entries as CSV, JSON or NDJSON",
+	Long: `Export weight entries to stdout or a file.
+
+Accepts the same filters as 'list'. Weights are exported in the unit they were
+recorded in. CSV dates use the ISO "yyyy-mm-dd HH:MM:SS" format, so an export
+can be imported again with 'import --date-format yyyy-mm-dd'.
+
+Examples:
+  weight-tracker export                                  # CSV to stdout, oldest first
+  weight-tracker export --format json --file backup.json # JSON array to a file
+  weight-tracker export --format ndjson | jq .weight     # One JSON object per line
+  weight-tracker export --from 01-01-2025 --to 31-03-2025 --unit kg
+  weight-tracker export --sort weight --desc --limit 10`,
+	Args: cobra.NoArgs,
+	Run:  runExport,
+}
+
+var exportFormat string
+var exportFile string
+var exportFromDate string
+var exportToDate string
+var exportLimit int
+var exportSortField string
+var exportDesc bool
+var exportUnitFilter string
+var exportNoteFilter string
+
+func init() {
+	exportCmd.Flags().StringVar(&exportFormat, "format", "csv", "Export format (csv, json, ndjson)")
+	exportCmd.Flags().StringVar(&exportFile, "file", "", "Write to this file instead of stdout")
+	exportCmd.Flags().StringVarP(&exportFromDate, "from", "f", "", "Start date for filtering (DATE_INPUT_FORMAT or relative: today, -7d, last monday, this month, 2025-W10)")
+	exportCmd.Flags().StringVarP(&exportToDate, "to", "t", "", "End date for filtering (DATE_INPUT_FORMAT or relative: today, -7d, last monday, this month, 2025-W10)")
+	exportCmd.Flags().IntVarP(&exportLimit, "limit", "l", 0, "Maximum number of entries to export (0 = no limit)")
+	exportCmd.Flags().StringVarP(&exportSortField, "sort", "s", "date", sortFlagUsage)
+	exportCmd.Flags().BoolVarP(&exportDesc, "desc", "d", false, "Sort in descending order (with a single sort field)")
+	exportCmd.Flags().StringVarP(&exportUnitFilter, "unit", "u", "", "Filter by unit (kg, lbs)")
+	exportCmd.Flags().StringVarP(&exportNoteFilter, "note", "n", "", "Filter by note (case-insensitive substring)")
+}
+
+// parseExportFormat validates an export format name
+func parseExportFormat(format string) (ExportFormat, error) {
+	switch ExportFormat(format) {
+	case ExportCSV, ExportJSON, ExportNDJSON:
+		return ExportFormat(format), nil
+	default:
+		return "", fmt.Errorf("unsupported export format '%s': must be 'csv', 'json' or 'ndjson'", format)
+	}
+}
+
+// writeExport writes entries to w in the given format, one entry at a time; CSV
+// dates are written in location
+func writeExport(w io.Writer, entries []WeightEntry, format ExportFormat, location *time.Location) error {
+	switch format {
+	case ExportCSV:
+		return writeCSVExport(w, entries, location)
+	case ExportJSON:
+		return writeJSONExport(w, entries)
+	case ExportNDJSON:
+		return writeNDJSONExport(w, entries)
+	default:
+		return fmt.Errorf("unsupported export format '%s'", format)
+	}
+}
+
+// writeCSVExport writes entries as CSV with a header row, with dates in location
+func writeCSVExport(w io.Writer, entries []WeightEntry, location *time.Location) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(csvExportHeader); err != nil {
+		return fmt.Errorf("failed to write CSV header: %w", err)
+	}
+
+	for _, entry := range entries {
+		record := []string{
+			strconv.FormatInt(entry.ID, 10),
+			entry.Date.In(location).Format(csvExportDateLayout),
+			strconv.FormatFloat(entry.Weight, 'f', -1, 64),
+			entry.Unit,
+			entry.Note,
+			entry.UserID,
+		}
+		if err := writer.Write(record); err != nil {
+			return fmt.Errorf("failed to write entry %d: %w", entry.ID, err)
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+// writeJSONExport writes entries as a single indented JSON array
+func writeJSONExport(w io.Writer, entries []WeightEntry) error {
+	// Encode entries individually so large exports are streamed instead of buffered
+	if _, err := io.WriteString(w, "["); err != nil {
+		return err
+	}
+	for i, entry := range entries {
+		data, err := json.MarshalIndent(entry, "  ", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to encode entry %d: %w", entry.ID, err)
+		}
+		separator := "\n  "
+		if i > 0 {
+			separator = ",\n  "
+		}
+		if _, err := io.WriteString(w, separator); err != nil {
+			return err
+		}
+		if _, err := w.Write(data); err != nil {
+			return err
+		}
+	}
+	if len(entries) > 0 {
+		if _, err := io.WriteString(w, "\n"); err != nil {
+			return err
+		}
+	}
+	_, err := io.WriteString(w, "]\n")
+	return err
+}
+
+// writeNDJSONExport writes entries as newline-delimited JSON, one object per line
+func writeNDJSONExport(w io.Writer, entries []WeightEntry) error {
+	encoder := json.NewEncoder(w)
+	for _, entry := range entries {
+		if err := encoder.Encode(entry); err != nil {
+			return fmt.Errorf("failed to encode entry %d: %w", entry.ID, err)
+		}
+	}
+	return nil
+}
+
+// runExportInternal contains the core logic and returns errors instead of terminating
+func runExportInternal(cmd *cobra.Command, args []string) error {
+	// Note: args are not used for export command as all options are handled via flags
+	_ = args
+
+	formatValue, _ := cmd.Flags().GetString("format")
+	format, err := parseExportFormat(formatValue)
+	if err != nil {
+		return err
+	}
+
+	options, err := buildListOptions(cmd)
+	if err != nil {
+		return err
+	}
+
+	app := appFor(cmd)
+
+	// Create store instance
+	store, err := app.NewStore()
+	if err != nil {
+		return fmt.Errorf("could not create store: %w", err)
+	}
+	defer store.Close()
+
+	entries, err := store.ListWeights(context.Background(), options)
+	if err != nil {
+		return fmt.Errorf("failed to list weights: %w", err)
+	}
+
+	// Write to stdout unless a file was requested
+	out := cmd.OutOrStdout()
+	filePath, _ := cmd.Flags().GetString("file")
+	if filePath != "" {
+		file, err := os.Create(filePath)
+		if err != nil {
+			return fmt.Errorf("failed to create export file: %w", err)
+		}
+		defer file.Close()
+		out = file
+	}
+
+	if err := writeExport(out, entries, format, GetLocationFromEnv(app.Getenv)); err != nil {
+		return fmt.Errorf("failed to export weight entries: %w", err)
+	}
+
+	if filePath != "" {
+		fmt.Fprintf(cmd.ErrOrStderr(), "Exported %d weight entries to %s\n", len(entries), filePath)
+	}
+
+	return nil
+}
+
+// runExport is the cobra command wrapper that handles errors appropriately for CLI usage
+func runExport(cmd *cobra.Command, args []string) {
+	if err := runExportInternal(cmd, args); err != nil {
+		exitWithError(cmd, err)
+	}
+}
@@ -0,0 +1,225 @@
+package tracker
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// export_test.go - Export tests
+// * purpose: tests CSV, JSON and NDJSON serialization and shared list filters.
+// * focus: output shape, round trip through import, and flag parsing.
+
+func exportTestEntries() []WeightEntry {
+	baseDate := time.Date(2025, 1, 1, 7, 30, 0, 0, time.UTC)
+	return []WeightEntry{
+		{ID: 1, Weight: 75.5, Date: baseDate, Unit: "kg", Note: "new year, new me"},
+		{ID: 2, Weight: 165.2, Date: baseDate.AddDate(0, 0, 1), Unit: "lbs", UserID: "alice"},
+	}
+}
+
+func TestWriteExport_CSV(t *testing.T) {
+	var buf bytes.Buffer
+	if err := writeExport(&buf, exportTestEntries(), ExportCSV, time.UTC); err != nil {
+		t.Fatal(unexpectedErrorString(err))
+	}
+
+	records, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatalf("export is not valid CSV: %v", err)
+	}
+	if len(records) != 3 {
+		t.Fatalf("got %d CSV records, want 3 (header + 2 entries)", len(records))
+	}
+	if strings.Join(records[0], ",") != "id,date,weight,unit,note,user_id" {
+		t.Errorf("unexpected header: %v", records[0])
+	}
+	expected := []string{"1", "2025-01-01 07:30:00", "75.5", "kg", "new year, new me", ""}
+	for i, value := range expected {
+		if records[1][i] != value {
+			t.Errorf("column %s = %q, want %q", records[0][i], records[1][i], value)
+		}
+	}
+}
+
+func TestWriteExport_CSVRoundTrip(t *testing.T) {
+	var buf bytes.Buffer
+	if err := writeExport(&buf, exportTestEntries(), ExportCSV, time.UTC); err != nil {
+		t.Fatal(unexpectedErrorString(err))
+	}
+
+	entries, importErrors, err := parseCSVEntries(&buf, CSVImportOptions{
+		Mapping:     CSVColumnMapping{Date: "date", Weight: "weight", Unit: "unit", Note: "note"},
+		HasHeader:   true,
+		DateLayout:  "2006-01-02",
+		DefaultUnit: "kg",
+	})
+	if err != nil {
+		t.Fatal(unexpectedErrorString(err))
+	}
+	if len(importErrors) != 0 {
+		t.Fatalf("unexpected import errors: %v", importErrors)
+	}
+
+	for i, original := range exportTestEntries() {
+		if entries[i].Weight != original.Weight || !entries[i].Date.Equal(original.Date) ||
+			entries[i].Unit != original.Unit || entries[i].Note != original.Note {
+			t.Errorf("round trip entry %d = %+v, want %+v", i, entries[i], original)
+		}
+	}
+}
+
+func TestWriteExport_JSON(t *testing.T) {
+	tests := []struct {
+		name     string
+		entries  []WeightEntry
+		expected int
+	}{
+		{name: "entries", entries: exportTestEntries(), expected: 2},
+		{name: "no entries", entries: nil, expected: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := writeExport(&buf, tt.entries, ExportJSON, time.UTC); err != nil {
+				t.Fatal(unexpectedErrorString(err))
+			}
+
+			var decoded []WeightEntry
+			if err := json.Unmarshal(buf.Bytes(), &decoded); err != nil {
+				t.Fatalf("export is not valid JSON: %v\n%s", err, buf.String())
+			}
+			if len(decoded) != tt.expected {
+				t.Fatalf("decoded %d entries, want %d", len(decoded), tt.expected)
+			}
+			for i, entry := range decoded {
+				original := tt.entries[i]
+				if entry.ID != original.ID || entry.Weight != original.Weight || !entry.Date.Equal(original.Date) || entry.UserID != original.UserID {
+					t.Errorf("decoded entry %d = %+v, want %+v", i, entry, original)
+				}
+			}
+		})
+	}
+}
+
+func TestWriteExport_NDJSON(t *testing.T) {
+	var buf bytes.Buffer
+	if err := writeExport(&buf, exportTestEntries(), ExportNDJSON, time.UTC); err != nil {
+		t.Fatal(unexpectedErrorString(err))
+	}
+
+	scanner := bufio.NewScanner(&buf)
+	lines := 0
+	for scanner.Scan() {
+		var entry WeightEntry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			t.Fatalf("line %d is not valid JSON: %v", lines+1, err)
+		}
+		lines++
+		if entry.ID != int64(lines) {
+			t.Errorf("line %d has ID %d", lines, entry.ID)
+		}
+	}
+	if lines != 2 {
+		t.Errorf("got %d lines, want 2", lines)
+	}
+}
+
+func TestParseExportFormat(t *testing.T) {
+	for _, format := range []string{"csv", "json", "ndjson"} {
+		if _, err := parseExportFormat(format); err != nil {
+			t.Errorf("parseExportFormat(%s) unexpected error: %v", format, err)
+		}
+	}
+	if _, err := parseExportFormat("xml"); err == nil {
+		t.Errorf("parseExportFormat(xml) expected error but got none")
+	}
+}
+
+func TestBuildListOptions(t *testing.T) {
+	tests := []struct {
+		name        string
+		flags       map[string]string
+		shouldError bool
+		check       func(t *testing.T, options ListOptions)
+	}{
+		{
+			name:  "defaults",
+			flags: map[string]string{},
+			check: func(t *testing.T, options ListOptions) {
+				if options.SortBy != "date" || options.FromDate != nil || options.ToDate != nil {
+					t.Errorf("unexpected default options: %+v", options)
+				}
+			},
+		},
+		{
+			name:  "date range includes the whole end day",
+			flags: map[string]string{"from": "01-01-2025", "to": "31-01-2025"},
+			check: func(t *testing.T, options ListOptions) {
+				if options.FromDate == nil || !options.FromDate.Equal(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)) {
+					t.Errorf("FromDate = %v, want 2025-01-01", options.FromDate)
+				}
+				if options.ToDate == nil || FormatDateForDB(*options.ToDate) != "2025-01-31 23:59:59Z" {
+					t.Errorf("ToDate = %v, want end of 2025-01-31", options.ToDate)
+				}
+			},
+		},
+		{
+			name:  "sort, limit and unit",
+			flags: map[string]string{"sort": "weight", "desc": "true", "limit": "5", "unit": "lbs"},
+			check: func(t *testing.T, options ListOptions) {
+				if options.SortBy != "weight" || !options.SortDesc || options.Limit != 5 || options.Unit != "lbs" {
+					t.Errorf("unexpected options: %+v", options)
+				}
+			},
+		},
+		{
+			name:        "invalid sort column",
+			flags:       map[string]string{"sort": "bmi"},
+			shouldError: true,
+		},
+		{
+			name:        "invalid from date",
+			flags:       map[string]string{"from": "yesterday-ish"},
+			shouldError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Create a fresh test command for each test case to avoid flag contamination
+			cmd := &cobra.Command{Use: "export"}
+			cmd.Flags().StringP("from", "f", "", "Start date for filtering")
+			cmd.Flags().StringP("to", "t", "", "End date for filtering")
+			cmd.Flags().IntP("limit", "l", 0, "Maximum number of entries")
+			cmd.Flags().StringP("sort", "s", "date", "Field to sort by")
+			cmd.Flags().BoolP("desc", "d", false, "Sort in descending order")
+			cmd.Flags().StringP("unit", "u", "", "Filter by unit")
+
+			for flag, value := range tt.flags {
+				if err := cmd.Flags().Set(flag, value); err != nil {
+					t.Fatalf("failed to set flag %s: %v", flag, err)
+				}
+			}
+
+			options, err := buildListOptions(cmd)
+			if tt.shouldError {
+				if err == nil {
+					t.Error("expected error but got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(unexpectedErrorString(err))
+			}
+			tt.check(t, options)
+		})
+	}
+}
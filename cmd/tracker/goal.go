@@ -0,0 +1,467 @@
+package tracker
+
+// goal.go - Goal weights with progress tracking and ETA projection
+// Related files: store.go (goals table), stats.go (progress report), graph.go (goal markLine)
+// Each user has at most one goal. Progress is measured from the weight at the time
+// the goal was set, and completion is projected from the recent rate of change.
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+const (
+	// GoalRateWindowDays is the window of recent entries the rate of change is fitted on
+	GoalRateWindowDays = 28
+	// maxProjectionDays bounds projections; slower rates are reported as no projection
+	maxProjectionDays = 10 * 365
+)
+
+// GoalProgress reports how far a user is towards their goal
+type GoalProgress struct {
+	Goal            Goal       `json:"goal" yaml:"goal"`
+	CurrentWeight   float64    `json:"current_weight" yaml:"current_weight"`     // Latest recorded weight
+	Remaining       float64    `json:"remaining" yaml:"remaining"`               // Target minus current weight
+	ProgressPercent float64    `json:"progress_percent" yaml:"progress_percent"` // Share of the way from start to target
+	Reached         bool       `json:"reached" yaml:"reached"`
+	HasRate         bool       `json:"has_rate" yaml:"has_rate"`           // False with fewer than two recent dates
+	RatePerWeek     float64    `json:"rate_per_week" yaml:"rate_per_week"` // Recent rate of change
+	ProjectedDate   *time.Time `json:"projected_date,omitempty" yaml:"projected_date,omitempty"`
+	OnTrack         *bool      `json:"on_track,omitempty" yaml:"on_track,omitempty"` // Set when the goal has a target date and a projection
+}
+
+var goalCmd = &cobra.Command{
+	Use:   "goal",
+	Short: "Manage goal weights",
+	Long: `Set, list and clear goal weights.
+
+Each user (see --user) has at most one goal. Once a goal is set, 'stats' reports
+the progress towards it, the remaining difference and a projected completion date
+based on the rate of change over the last 28 days, and HTML charts draw the goal
+as a horizontal line.
+
+Examples:
+  weight-tracker goal set 70                     # Goal of 70 in the default unit
+  weight-tracker goal set 155 --unit lbs --by 01-06-2025
+  weight-tracker goal set 70 --start 82.5        # Progress measured from 82.5
+  weight-tracker goal list                       # Show goals
+  weight-tracker goal clear                      # Remove the goal`,
+}
+
+var goalSetCmd = &cobra.Command{
+	Use:   "set <target-weight>",
+	Short: "Set or replace the goal weight",
+	Long: `Set the goal weight, replacing any existing goal.
+
+Progress is measured from the latest recorded weight unless --start is given.`,
+	Args: cobra.ExactArgs(1),
+	Run:  runGoalSet,
+}
+
+var goalListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List goals",
+	Args:  cobra.NoArgs,
+	Run:   runGoalList,
+}
+
+var goalClearCmd = &cobra.Command{
+	Use:   "clear",
+	Short: "Remove the goal",
+	Args:  cobra.NoArgs,
+	Run:   runGoalClear,
+}
+
+var goalUnit string
+var goalTargetDate string
+var goalStartWeight float64
+
+func init() {
+	goalSetCmd.Flags().StringVarP(&goalUnit, "unit", "u", "", "The unit of the goal (kg, lbs) - default configurable via DEFAULT_UNIT")
+	goalSetCmd.Flags().StringVarP(&goalTargetDate, "by", "b", "", "Target date of the goal (DATE_INPUT_FORMAT or relative: today, -7d, last monday, this month, 2025-W10)")
+	goalSetCmd.Flags().Float64VarP(&goalStartWeight, "start", "s", 0, "Starting weight progress is measured from (default: latest entry)")
+
+	goalCmd.AddCommand(goalSetCmd)
+	goalCmd.AddCommand(goalListCmd)
+	goalCmd.AddCommand(goalClearCmd)
+}
+
+// convertGoal returns the goal with its weights expressed in unit
+func convertGoal(goal Goal, unit string) (Goal, error) {
+	target, err := ConvertWeight(goal.TargetWeight, goal.Unit, unit)
+	if err != nil {
+		return Goal{}, err
+	}
+	start, err := ConvertWeight(goal.StartWeight, goal.Unit, unit)
+	if err != nil {
+		return Goal{}, err
+	}
+
+	goal.TargetWeight = target
+	goal.StartWeight = start
+	goal.Unit = unit
+	return goal, nil
+}
+
+// calculateGoalProgress measures entries against goal. Entries must be expressed
+// in the unit of the goal (see NormalizeEntries and convertGoal).
+func calculateGoalProgress(goal Goal, entries []WeightEntry) (*GoalProgress, error) {
+	if len(entries) == 0 {
+		return nil, fmt.Errorf("no weight entries to measure goal progress against")
+	}
+
+	sorted := sortedByDate(entries)
+	latest := sorted[len(sorted)-1]
+
+	progress := &GoalProgress{
+		Goal:          goal,
+		CurrentWeight: latest.Weight,
+		Remaining:     goal.TargetWeight - latest.Weight,
+	}
+
+	// A goal below the starting weight is a weight-loss goal
+	losing := goal.TargetWeight < goal.StartWeight
+	if losing {
+		progress.Reached = latest.Weight <= goal.TargetWeight
+	} else {
+		progress.Reached = latest.Weight >= goal.TargetWeight
+	}
+
+	if span := goal.StartWeight - goal.TargetWeight; span != 0 {
+		progress.ProgressPercent = (goal.StartWeight - latest.Weight) / span * 100
+	} else if progress.Reached {
+		progress.ProgressPercent = 100
+	}
+
+	// Fit the rate of change on recent entries only, so old plateaus don't hide current progress
+	windowStart := latest.Date.AddDate(0, 0, -GoalRateWindowDays)
+	var recent []WeightEntry
+	for _, entry := range sorted {
+		if !entry.Date.Before(windowStart) {
+			recent = append(recent, entry)
+		}
+	}
+
+	regression, err := FitLinearRegression(recent)
+	if err != nil {
+		// Not enough recent data for a rate; progress is still reported
+		return progress, nil
+	}
+	progress.HasRate = true
+	progress.RatePerWeek = regression.SlopePerWeek()
+
+	// Project only when the weight is moving towards the target
+	if progress.Reached || regression.SlopePerDay == 0 {
+		return progress, nil
+	}
+	days := progress.Remaining / regression.SlopePerDay
+	if days <= 0 || days > maxProjectionDays {
+		return progress, nil
+	}
+
+	projected := latest.Date.Add(time.Duration(days * 24 * float64(time.Hour)))
+	progress.ProjectedDate = &projected
+	if goal.TargetDate != nil {
+		onTrack := !projected.After(EndOfDay(*goal.TargetDate))
+		progress.OnTrack = &onTrack
+	}
+
+	return progress, nil
+}
+
+// loadGoalProgress returns the progress of the selected user's goal in unit, or
+// nil when no goal is set or there are no entries
+func loadGoalProgress(ctx context.Context, store Store, userID, unit string, entries []WeightEntry) (*GoalProgress, error) {
+	if len(entries) == 0 {
+		return nil, nil
+	}
+
+	goal, err := loadGoal(ctx, store, userID, unit)
+	if err != nil || goal == nil {
+		return nil, err
+	}
+
+	return calculateGoalProgress(*goal, entries)
+}
+
+// loadGoal returns the goal of userID converted to unit, or nil when none is set
+func loadGoal(ctx context.Context, store Store, userID, unit string) (*Goal, error) {
+	goal, err := store.GetGoal(ctx, userID)
+	if errors.Is(err, ErrGoalNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	converted, err := convertGoal(goal, unit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert goal to %s: %w", unit, err)
+	}
+	return &converted, nil
+}
+
+// goalOwner returns a display label for the owner of a goal
+func goalOwner(goal Goal) string {
+	if goal.UserID == "" {
+		return "(no user)"
+	}
+	return goal.UserID
+}
+
+// formatGoal returns a one-line human-readable description of a goal, with the target
+// date (in the zone the Store returned it in) formatted with dateLayout
+func formatGoal(goal Goal, dateLayout string) string {
+	if dateLayout == "" {
+		dateLayout = DefaultDisplayFormat
+	}
+	description := fmt.Sprintf("%.2f %s", goal.TargetWeight, goal.Unit)
+	if goal.TargetDate != nil {
+		description += " by " + goal.TargetDate.Format(dateLayout)
+	}
+	return description
+}
+
+// goalSetResult is the result of goal set: the goal that was set
+type goalSetResult Goal
+
+func (result goalSetResult) writeTable(w io.Writer, config AppConfig) error {
+	goal := Goal(result)
+	fmt.Fprintf(w, "Goal set for %s: %s (starting from %.2f %s)\n",
+		goalOwner(goal), formatGoal(goal, config.DateFormat.DisplayFormat), goal.StartWeight, goal.Unit)
+	return nil
+}
+
+// goalList is the result of goal list
+type goalList []Goal
+
+func (goals goalList) writeTable(w io.Writer, config AppConfig) error {
+	if len(goals) == 0 {
+		fmt.Fprintln(w, "No goals set.")
+		return nil
+	}
+
+	fmt.Fprintf(w, "Found %d goals:\n\n", len(goals))
+	for _, goal := range goals {
+		fmt.Fprintf(w, "* %s: %s (from %.2f %s, set %s)\n",
+			goalOwner(goal), formatGoal(goal, config.DateFormat.DisplayFormat), goal.StartWeight, goal.Unit, config.FormatDate(goal.CreatedAt))
+	}
+	return nil
+}
+
+// goalClearResult is the result of goal clear: the goal that was removed
+type goalClearResult struct {
+	Cleared bool `json:"cleared" yaml:"cleared"`
+	Goal    Goal `json:"goal" yaml:"goal"`
+}
+
+func (result goalClearResult) writeTable(w io.Writer, config AppConfig) error {
+	fmt.Fprintf(w, "Cleared goal for %s: %s\n", goalOwner(result.Goal), formatGoal(result.Goal, config.DateFormat.DisplayFormat))
+	return nil
+}
+
+// runGoalSetInternal contains the core logic and returns errors instead of terminating
+func runGoalSetInternal(cmd *cobra.Command, args []string) error {
+	renderer, err := rendererFor(cmd)
+	if err != nil {
+		return err
+	}
+	app := appFor(cmd)
+
+	targetWeight, err := strconv.ParseFloat(args[0], 64)
+	if err != nil {
+		return fmt.Errorf("goal set needs a float target weight to process: %w", err)
+	}
+
+	goal := Goal{
+		UserID:       resolveUser(cmd),
+		TargetWeight: targetWeight,
+		Unit:         GetDefaultUnitFromEnv(app.Getenv),
+	}
+
+	if cmd.Flags().Changed("unit") {
+		unitStr, _ := cmd.Flags().GetString("unit")
+		if unitStr != "" {
+			goal.Unit = unitStr
+		}
+	}
+
+	if cmd.Flags().Changed("by") {
+		dateStr, _ := cmd.Flags().GetString("by")
+		if dateStr != "" {
+			targetDate, err := ParseDateAt(dateStr, app.Now(), app.Getenv)
+			if err != nil {
+				return fmt.Errorf("invalid target date format '%s': %s", dateStr, dateInputHint(app.Getenv))
+			}
+			goal.TargetDate = &targetDate
+		}
+	}
+
+	// Create store instance
+	store, err := app.NewStore()
+	if err != nil {
+		return fmt.Errorf("could not create store: %w", err)
+	}
+	defer store.Close()
+
+	ctx := context.Background()
+	if cmd.Flags().Changed("start") {
+		goal.StartWeight, _ = cmd.Flags().GetFloat64("start")
+	} else {
+		// Measure progress from the latest recorded weight
+		latest, err := store.ListWeights(ctx, ListOptions{SortBy: "date", SortDesc: true, Limit: 1, UserID: goal.UserID})
+		if err != nil {
+			return fmt.Errorf("failed to find latest weight entry: %w", err)
+		}
+		if len(latest) == 0 {
+			return fmt.Errorf("no weight entries to measure progress from: add an entry first or use --start")
+		}
+		goal.StartWeight, err = ConvertWeight(latest[0].Weight, latest[0].Unit, goal.Unit)
+		if err != nil {
+			return fmt.Errorf("failed to convert latest weight to %s: %w", goal.Unit, err)
+		}
+	}
+
+	if err := ValidateGoal(goal); err != nil {
+		return fmt.Errorf("validation failed: %w", err)
+	}
+
+	savedGoal, err := store.SetGoal(ctx, goal)
+	if err != nil {
+		return fmt.Errorf("failed to set goal: %w", err)
+	}
+
+	return renderer.Render(goalSetResult(savedGoal))
+}
+
+// runGoalListInternal contains the core logic and returns errors instead of terminating
+func runGoalListInternal(cmd *cobra.Command, args []string) error {
+	// Note: args are not used for goal list command
+	_ = args
+
+	renderer, err := rendererFor(cmd)
+	if err != nil {
+		return err
+	}
+
+	app := appFor(cmd)
+
+	// Create store instance
+	store, err := app.NewStore()
+	if err != nil {
+		return fmt.Errorf("could not create store: %w", err)
+	}
+	defer store.Close()
+
+	ctx := context.Background()
+
+	// With a selected user, only that user's goal is listed
+	if user := resolveUser(cmd); user != "" {
+		goal, err := store.GetGoal(ctx, user)
+		if errors.Is(err, ErrGoalNotFound) {
+			return renderer.Render(goalList(nil))
+		}
+		if err != nil {
+			return fmt.Errorf("failed to get goal: %w", err)
+		}
+		return renderer.Render(goalList{goal})
+	}
+
+	goals, err := store.ListGoals(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list goals: %w", err)
+	}
+	return renderer.Render(goalList(goals))
+}
+
+// runGoalClearInternal contains the core logic and returns errors instead of terminating
+func runGoalClearInternal(cmd *cobra.Command, args []string) error {
+	// Note: args are not used for goal clear command
+	_ = args
+
+	renderer, err := rendererFor(cmd)
+	if err != nil {
+		return err
+	}
+
+	app := appFor(cmd)
+
+	// Create store instance
+	store, err := app.NewStore()
+	if err != nil {
+		return fmt.Errorf("could not create store: %w", err)
+	}
+	defer store.Close()
+
+	ctx := context.Background()
+	user := resolveUser(cmd)
+
+	goal, err := store.GetGoal(ctx, user)
+	if err != nil {
+		return fmt.Errorf("failed to clear goal: %w", err)
+	}
+	if err := store.DeleteGoal(ctx, user); err != nil {
+		return fmt.Errorf("failed to clear goal: %w", err)
+	}
+
+	return renderer.Render(goalClearResult{Cleared: true, Goal: goal})
+}
+
+// runGoalSet is the cobra command wrapper that handles errors appropriately for CLI usage
+func runGoalSet(cmd *cobra.Command, args []string) {
+	if err := runGoalSetInternal(cmd, args); err != nil {
+		exitWithError(cmd, err)
+	}
+}
+
+// runGoalList is the cobra command wrapper that handles errors appropriately for CLI usage
+func runGoalList(cmd *cobra.Command, args []string) {
+	if err := runGoalListInternal(cmd, args); err != nil {
+		exitWithError(cmd, err)
+	}
+}
+
+// runGoalClear is the cobra command wrapper that handles errors appropriately for CLI usage
+func runGoalClear(cmd *cobra.Command, args []string) {
+	if err := runGoalClearInternal(cmd, args); err != nil {
+		exitWithError(cmd, err)
+	}
+}
+
+// writeGoalProgress writes the goal section of the statistics report, with dates
+// formatted as configured in config
+func writeGoalProgress(w io.Writer, progress GoalProgress, config AppConfig) {
+	unit := progress.Goal.Unit
+
+	fmt.Fprintf(w, "\nGoal: %s (from %.2f %s)\n", formatGoal(progress.Goal, config.DateFormat.DisplayFormat), progress.Goal.StartWeight, unit)
+	if progress.Reached {
+		fmt.Fprintf(w, "Progress: %.1f%% - goal reached!\n", progress.ProgressPercent)
+	} else {
+		fmt.Fprintf(w, "Progress: %.1f%% (%.2f %s to go)\n", progress.ProgressPercent, math.Abs(progress.Remaining), unit)
+	}
+
+	if !progress.HasRate {
+		fmt.Fprintf(w, "Recent Rate: Unable to calculate (fewer than 2 entries in the last %d days)\n", GoalRateWindowDays)
+		return
+	}
+	fmt.Fprintf(w, "Recent Rate: %+.2f %s/week (last %d days)\n", progress.RatePerWeek, unit, GoalRateWindowDays)
+
+	switch {
+	case progress.Reached:
+	case progress.ProjectedDate == nil:
+		fmt.Fprintln(w, "Projected: Not moving towards the goal at the recent rate")
+	case progress.OnTrack == nil:
+		fmt.Fprintf(w, "Projected: %s\n", config.FormatDate(*progress.ProjectedDate))
+	case *progress.OnTrack:
+		fmt.Fprintf(w, "Projected: %s (on track)\n", config.FormatDate(*progress.ProjectedDate))
+	default:
+		fmt.Fprintf(w, "Projected: %s (behind target date)\n", config.FormatDate(*progress.ProjectedDate))
+	}
+}
@@ -0,0 +1,185 @@
+package tracker
+
+import (
+	"context"
+	"errors"
+	"math"
+	"testing"
+	"time"
+)
+
+// goal_test.go - Goal tests
+// * purpose: tests goal persistence with both real db and mock, and progress calculation.
+// * tests: integration (real DB), MockStore (mock) and pure progress/projection logic
+// * focus: one goal per user, progress percentage, rate and projected completion.
+
+func testGoalStore(t *testing.T, store Store) {
+	t.Helper()
+	ctx := context.Background()
+	targetDate := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+
+	if _, err := store.GetGoal(ctx, ""); !errors.Is(err, ErrGoalNotFound) {
+		t.Fatalf("GetGoal() error = %v, want ErrGoalNotFound", err)
+	}
+
+	goal, err := store.SetGoal(ctx, Goal{TargetWeight: 70, Unit: "kg", StartWeight: 80, TargetDate: &targetDate})
+	if err != nil {
+		t.Fatal(unexpectedErrorString(err))
+	}
+	if goal.ID == 0 || goal.TargetDate == nil || !goal.TargetDate.Equal(targetDate) {
+		t.Errorf("SetGoal() = %+v", goal)
+	}
+
+	// Setting a goal again replaces it
+	if _, err := store.SetGoal(ctx, Goal{TargetWeight: 68, Unit: "kg", StartWeight: 80}); err != nil {
+		t.Fatal(unexpectedErrorString(err))
+	}
+	if _, err := store.SetGoal(ctx, Goal{UserID: "alice", TargetWeight: 150, Unit: "lbs", StartWeight: 160}); err != nil {
+		t.Fatal(unexpectedErrorString(err))
+	}
+
+	goal, err = store.GetGoal(ctx, "")
+	if err != nil {
+		t.Fatal(unexpectedErrorString(err))
+	}
+	if goal.TargetWeight != 68 || goal.TargetDate != nil {
+		t.Errorf("GetGoal() after replace = %+v, want target 68 without date", goal)
+	}
+
+	goals, err := store.ListGoals(ctx)
+	if err != nil {
+		t.Fatal(unexpectedErrorString(err))
+	}
+	if len(goals) != 2 || goals[0].UserID != "" || goals[1].UserID != "alice" {
+		t.Errorf("ListGoals() = %+v, want default goal and alice", goals)
+	}
+
+	// Setting a goal registers the user
+	users, err := store.ListUsers(ctx)
+	if err != nil {
+		t.Fatal(unexpectedErrorString(err))
+	}
+	if len(users) != 1 || users[0].ID != "alice" {
+		t.Errorf("ListUsers() = %+v, want alice", users)
+	}
+
+	if err := store.DeleteGoal(ctx, "alice"); err != nil {
+		t.Fatal(unexpectedErrorString(err))
+	}
+	if err := store.DeleteGoal(ctx, "alice"); !errors.Is(err, ErrGoalNotFound) {
+		t.Errorf("DeleteGoal() twice error = %v, want ErrGoalNotFound", err)
+	}
+
+	// Invalid goals are rejected
+	if _, err := store.SetGoal(ctx, Goal{TargetWeight: 70, Unit: "stone", StartWeight: 80}); err == nil {
+		t.Errorf("SetGoal() expected error for invalid unit but got none")
+	}
+}
+
+func TestGoals_Integration(t *testing.T) {
+	testDB := setupTestDB(t)
+	defer testDB.Close()
+
+	testGoalStore(t, NewDBStoreWithDB(testDB, time.UTC))
+}
+
+func TestGoals_MockStore(t *testing.T) {
+	testGoalStore(t, NewMockStore())
+}
+
+func TestCalculateGoalProgress(t *testing.T) {
+	baseDate := time.Date(2025, 1, 1, 7, 0, 0, 0, time.UTC)
+	targetDate := time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC)
+
+	// Losing 0.1 kg per day, from 80 to 78 over 20 days
+	losing := make([]WeightEntry, 0, 21)
+	for day := 0; day <= 20; day++ {
+		losing = append(losing, WeightEntry{Weight: 80 - 0.1*float64(day), Date: baseDate.AddDate(0, 0, day), Unit: "kg"})
+	}
+
+	tests := []struct {
+		name          string
+		goal          Goal
+		entries       []WeightEntry
+		wantPercent   float64
+		wantRemaining float64
+		wantReached   bool
+		wantProjected *time.Time
+		wantOnTrack   *bool
+	}{
+		{
+			name:          "losing towards goal, behind target date",
+			goal:          Goal{TargetWeight: 76, StartWeight: 80, Unit: "kg", TargetDate: &targetDate},
+			entries:       losing,
+			wantPercent:   50,
+			wantRemaining: -2,
+			// 2 kg at 0.1 kg/day is 20 days after the latest entry
+			wantProjected: &[]time.Time{baseDate.AddDate(0, 0, 40)}[0],
+			wantOnTrack:   &[]bool{false}[0],
+		},
+		{
+			name:          "goal reached",
+			goal:          Goal{TargetWeight: 78.5, StartWeight: 80, Unit: "kg"},
+			entries:       losing,
+			wantPercent:   133.33,
+			wantRemaining: 0.5,
+			wantReached:   true,
+		},
+		{
+			name:          "gaining goal while losing has no projection",
+			goal:          Goal{TargetWeight: 82, StartWeight: 80, Unit: "kg"},
+			entries:       losing,
+			wantPercent:   -100,
+			wantRemaining: 4,
+		},
+		{
+			name:          "single entry has no rate",
+			goal:          Goal{TargetWeight: 70, StartWeight: 80, Unit: "kg"},
+			entries:       losing[:1],
+			wantPercent:   0,
+			wantRemaining: -10,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			progress, err := calculateGoalProgress(tt.goal, tt.entries)
+			if err != nil {
+				t.Fatal(unexpectedErrorString(err))
+			}
+
+			if math.Abs(progress.ProgressPercent-tt.wantPercent) > 0.01 {
+				t.Errorf("ProgressPercent = %v, want %v", progress.ProgressPercent, tt.wantPercent)
+			}
+			if math.Abs(progress.Remaining-tt.wantRemaining) > 1e-9 {
+				t.Errorf("Remaining = %v, want %v", progress.Remaining, tt.wantRemaining)
+			}
+			if progress.Reached != tt.wantReached {
+				t.Errorf("Reached = %v, want %v", progress.Reached, tt.wantReached)
+			}
+
+			if tt.wantProjected == nil {
+				if progress.ProjectedDate != nil {
+					t.Errorf("ProjectedDate = %v, want none", progress.ProjectedDate)
+				}
+			} else if progress.ProjectedDate == nil || progress.ProjectedDate.Sub(*tt.wantProjected).Abs() > time.Minute {
+				t.Errorf("ProjectedDate = %v, want %v", progress.ProjectedDate, tt.wantProjected)
+			}
+
+			if (tt.wantOnTrack == nil) != (progress.OnTrack == nil) ||
+				(tt.wantOnTrack != nil && *tt.wantOnTrack != *progress.OnTrack) {
+				t.Errorf("OnTrack = %v, want %v", progress.OnTrack, tt.wantOnTrack)
+			}
+		})
+	}
+}
+
+func TestConvertGoal(t *testing.T) {
+	goal, err := convertGoal(Goal{TargetWeight: 70, StartWeight: 80, Unit: "kg"}, "lbs")
+	if err != nil {
+		t.Fatal(unexpectedErrorString(err))
+	}
+	if goal.Unit != "lbs" || math.Abs(goal.TargetWeight-154.3236) > 0.0001 || math.Abs(goal.StartWeight-176.3698) > 0.0001 {
+		t.Errorf("convertGoal() = %+v", goal)
+	}
+}
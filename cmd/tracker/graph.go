@@ -2,18 +2,22 @@ package tracker
 
 // graph.go - Chart generation functionality for weight tracking data
 // Related files: list.go (uses graph functionality via --graph flag), graph_test.go (tests)
-// Provides ASCII, HTML, and PNG chart generation using go-echarts library
-// Note: PNG is not yet implemented.
+// Provides HTML line charts using the go-echarts library; the other HTML chart types are
+// built by graph_types.go, terminal charts by graph_terminal.go and PNG and SVG charts
+// by graph_image.go
 
 import (
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 	"sort"
+	"strings"
 	"time"
 
 	"github.com/go-echarts/go-echarts/v2/charts"
 	"github.com/go-echarts/go-echarts/v2/opts"
+	"github.com/go-echarts/go-echarts/v2/types"
 )
 
 // GraphOutputType represents the type of graph output
@@ -23,21 +27,49 @@ const (
 	OutputTerminal GraphOutputType = "terminal"
 	OutputHTML     GraphOutputType = "html"
 	OutputPNG      GraphOutputType = "png"
+	OutputSVG      GraphOutputType = "svg"
 )
 
 // GraphOptions represents options for graph generation
 type GraphOptions struct {
 	OutputType GraphOutputType
 	OutputFile string
-	Width      int
-	Height     int
-	Title      string
+	// ChartType selects the chart drawn (empty draws a line chart); types other than
+	// line are only drawn as HTML
+	ChartType ChartType
+	// Period is the span of each bar or box of bar and boxplot charts (empty is weekly)
+	Period ChartPeriod
+	// Width and Height size PNG and SVG charts in pixels (0 uses 800x600)
+	Width  int
+	Height int
+	// Columns and Rows size terminal charts in characters (0 uses 80x24)
+	Columns int
+	Rows    int
+	// Out receives terminal charts (nil writes to standard output)
+	Out   io.Writer
+	Title string
+	// DisplayUnit is the unit all weights are converted to before plotting (empty keeps stored units)
+	DisplayUnit string
+	// Series draws one line per user or period instead of the entries (HTML line charts
+	// only); entries of series with a Start are aligned by day of period
+	Series []ChartSeries
+	// Trend selects a smoothed trend drawn over the weights (empty Method draws none)
+	Trend TrendOptions
+	// Goal is drawn as a horizontal line on HTML charts (nil draws none); it must be
+	// expressed in the plotted unit
+	Goal *Goal
+	// DateLayout is the Go layout of the date labels on HTML charts (DATE_DISPLAY_FORMAT);
+	// empty lets the chart choose labels to suit the zoom level
+	DateLayout string
+	// Responsive sizes HTML charts to fill the page instead of a fixed canvas
+	Responsive bool
 	// TestOutputDir allows tests to specify a custom output directory
 	TestOutputDir string
 }
 
-// ensureOutputDir creates the output directory if it doesn't exist
-func ensureOutputDir(filename string, testOutputDir string) (string, error) {
+// ensureOutputDir creates the output directory if it doesn't exist. Filenames without
+// an extension, including the generated default, are given extension (such as ".html").
+func ensureOutputDir(filename string, testOutputDir string, extension string) (string, error) {
 	outputDir := "charts"
 	if testOutputDir != "" {
 		outputDir = testOutputDir
@@ -46,12 +78,12 @@ func ensureOutputDir(filename string, testOutputDir string) (string, error) {
 	// If filename is provided, use it; otherwise generate a default
 	if filename == "" {
 		timestamp := time.Now().Format("2006-01-02_15-04-05")
-		filename = fmt.Sprintf("weight-chart_%s.html", timestamp)
+		filename = fmt.Sprintf("weight-chart_%s", timestamp)
 	}
 
 	// Ensure filename has proper extension
 	if filepath.Ext(filename) == "" {
-		filename += ".html"
+		filename += extension
 	}
 
 	// Create the full path including any nested directories
@@ -68,14 +100,13 @@ func ensureOutputDir(filename string, testOutputDir string) (string, error) {
 
 // GenerateWeightChart generates a chart from weight entries
 func GenerateWeightChart(entries []WeightEntry, options GraphOptions) (string, error) {
-	if len(entries) == 0 {
-		return "", fmt.Errorf("no weight entries to display")
+	if err := validateChartType(options); err != nil {
+		return "", err
+	}
+	entries, options, err := prepareChart(entries, options)
+	if err != nil {
+		return "", err
 	}
-
-	// Sort entries by date for proper chronological display
-	sort.Slice(entries, func(i, j int) bool {
-		return entries[i].Date.Before(entries[j].Date)
-	})
 
 	switch options.OutputType {
 	case OutputTerminal:
@@ -85,117 +116,110 @@ func GenerateWeightChart(entries []WeightEntry, options GraphOptions) (string, e
 		return generateHTMLChart(entries, options)
 	case OutputPNG:
 		return generatePNGChart(entries, options)
+	case OutputSVG:
+		return generateSVGChart(entries, options)
 	default:
 		return "", fmt.Errorf("unsupported output type: %s", options.OutputType)
 	}
 }
 
-// generateASCIIChart creates a simple ASCII art chart for terminal display
-func generateASCIIChart(entries []WeightEntry, options GraphOptions) error {
-	if len(entries) == 0 {
-		return fmt.Errorf("no entries to display")
+// RenderHTMLChart writes an interactive HTML chart of entries to w instead of a file
+// (used by the dashboard of 'serve --ui')
+func RenderHTMLChart(w io.Writer, entries []WeightEntry, options GraphOptions) error {
+	entries, options, err := prepareChart(entries, options)
+	if err != nil {
+		return err
 	}
 
-	// Find min and max weights for scaling
-	minWeight, maxWeight := entries[0].Weight, entries[0].Weight
-	for _, entry := range entries {
-		if entry.Weight < minWeight {
-			minWeight = entry.Weight
-		}
-		if entry.Weight > maxWeight {
-			maxWeight = entry.Weight
-		}
+	chart, err := buildTypedHTMLChart(entries, options)
+	if err != nil {
+		return err
 	}
 
-	// Add some padding
-	weightRange := maxWeight - minWeight
-	if weightRange == 0 {
-		weightRange = 1 // Avoid division by zero
+	if err := chart.Render(w); err != nil {
+		return fmt.Errorf("failed to render chart: %w", err)
 	}
-	padding := weightRange * 0.1
-	minWeight -= padding
-	maxWeight += padding
-	weightRange = maxWeight - minWeight
+	return nil
+}
 
-	fmt.Printf("\n%s\n", options.Title)
-	fmt.Printf("Weight Chart (%d entries)\n", len(entries))
-	fmt.Printf("Range: %.1f - %.1f kg\n\n", minWeight, maxWeight)
+// prepareChart prepares the entries of a chart, or the series of a comparison chart
+// (whose entries are not drawn)
+func prepareChart(entries []WeightEntry, options GraphOptions) ([]WeightEntry, GraphOptions, error) {
+	if len(options.Series) == 0 {
+		entries, err := prepareChartEntries(entries, options)
+		return entries, options, err
+	}
 
-	// Simple line chart with dots
-	chartHeight := 10
-	chartWidth := len(entries)
-	if chartWidth > 20 {
-		chartWidth = 20 // Limit width for readability
+	series, err := prepareChartSeries(options.Series, options)
+	if err != nil {
+		return nil, options, err
 	}
+	options.Series = series
+	return nil, options, nil
+}
 
-	// Create simple chart
-	chart := make([][]string, chartHeight)
-	for i := range chart {
-		chart[i] = make([]string, chartWidth)
-		for j := range chart[i] {
-			chart[i][j] = " "
-		}
+// prepareChartEntries converts entries to the display unit and sorts them by date
+func prepareChartEntries(entries []WeightEntry, options GraphOptions) ([]WeightEntry, error) {
+	if len(entries) == 0 {
+		return nil, fmt.Errorf("no weight entries to display")
 	}
 
-	// Plot data points
-	for i, entry := range entries {
-		if i >= chartWidth {
-			break
+	// Convert to a single unit so kg and lbs entries share one scale
+	if options.DisplayUnit != "" {
+		normalized, err := NormalizeEntries(entries, options.DisplayUnit)
+		if err != nil {
+			return nil, fmt.Errorf("failed to convert weights to %s: %w", options.DisplayUnit, err)
 		}
+		entries = normalized
+	}
 
-		// Calculate position
-		x := i
-		y := int((maxWeight - entry.Weight) / weightRange * float64(chartHeight-1))
+	// Sort entries by date for proper chronological display
+	// (stable, so same-timestamp entries keep their stored order)
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].Date.Before(entries[j].Date)
+	})
+	return entries, nil
+}
 
-		if y >= 0 && y < chartHeight {
-			chart[y][x] = "●"
-		}
+// generateHTMLChart creates an HTML chart file using go-echarts
+func generateHTMLChart(entries []WeightEntry, options GraphOptions) (string, error) {
+	chart, err := buildTypedHTMLChart(entries, options)
+	if err != nil {
+		return "", err
 	}
 
-	// Print chart with Y-axis labels
-	for i := 0; i < chartHeight; i++ {
-		weight := maxWeight - (float64(i)/float64(chartHeight-1))*weightRange
-		fmt.Printf("%6.1f |", weight)
+	// Generate HTML with proper output directory
+	outputFile, err := ensureOutputDir(options.OutputFile, options.TestOutputDir, ".html")
+	if err != nil {
+		return "", err
+	}
 
-		for j := 0; j < chartWidth; j++ {
-			fmt.Printf("%s", chart[i][j])
-		}
-		fmt.Println()
-	}
-
-	// Print X-axis
-	fmt.Printf("       +")
-	for i := 0; i < chartWidth; i++ {
-		fmt.Printf("-")
-	}
-	fmt.Println()
-
-	// Print entry details in a cleaner format
-	fmt.Printf("\nWeight Entries:\n")
-	for i, entry := range entries {
-		fmt.Printf("  %2d. %s: %.1f %s",
-			i+1,
-			entry.Date.Format("2006-01-02"),
-			entry.Weight,
-			entry.Unit)
-		if entry.Note != "" {
-			fmt.Printf(" (%s)", entry.Note)
-		}
-		fmt.Println()
+	f, err := os.Create(outputFile)
+	if err != nil {
+		return "", fmt.Errorf("failed to create output file: %w", err)
 	}
+	defer f.Close()
 
-	return nil
+	err = chart.Render(f)
+	if err != nil {
+		return "", fmt.Errorf("failed to render chart: %w", err)
+	}
+
+	return outputFile, nil
 }
 
-// generateHTMLChart creates an HTML chart using go-echarts
-func generateHTMLChart(entries []WeightEntry, options GraphOptions) (string, error) {
+// buildHTMLChart builds the go-echarts line chart of entries, with the trend and
+// goal overlays selected in options
+func buildHTMLChart(entries []WeightEntry, options GraphOptions) (*charts.Line, error) {
 	if len(entries) == 0 {
-		return "", fmt.Errorf("no entries to display")
+		return nil, fmt.Errorf("no entries to display")
 	}
 
-	// Prepare data for the chart with smart time-based spacing
+	// Entries with dates are placed on a time axis as [date, weight] pairs, so gaps
+	// between entries show as time passing; entries without dates are numbered
 	var xAxisData []string
 	var yAxisData []opts.LineData
+	var trendData []opts.LineData
 	var validEntries []WeightEntry
 
 	// Check if we have proper dates (not zero dates)
@@ -213,8 +237,8 @@ func generateHTMLChart(entries []WeightEntry, options GraphOptions) (string, err
 			xAxisData = append(xAxisData, fmt.Sprintf("Entry %d", i+1))
 			yAxisData = append(yAxisData, opts.LineData{Value: entry.Weight})
 		}
+		validEntries = entries
 	} else {
-		// We have proper dates - create time-normalized spacing using numeric X-axis
 		// Filter entries with proper dates and sort them
 		for _, entry := range entries {
 			if !entry.Date.IsZero() && entry.Date.Year() > 1 {
@@ -223,46 +247,26 @@ func generateHTMLChart(entries []WeightEntry, options GraphOptions) (string, err
 		}
 
 		// Sort by date
-		sort.Slice(validEntries, func(i, j int) bool {
+		sort.SliceStable(validEntries, func(i, j int) bool {
 			return validEntries[i].Date.Before(validEntries[j].Date)
 		})
 
-		if len(validEntries) == 0 {
-			return "", fmt.Errorf("no valid entries with proper dates")
+		for _, entry := range validEntries {
+			yAxisData = append(yAxisData, opts.LineData{Value: []interface{}{chartTimestamp(entry.Date), entry.Weight}})
 		}
+	}
 
-		// Calculate time-based X-axis positions
-		startTime := validEntries[0].Date
-
-		// Create X-axis data with time-based positioning
-		for i, entry := range validEntries {
-			// Calculate position as days from start
-			daysFromStart := entry.Date.Sub(startTime).Hours() / 24
-
-			// Format X-axis label more meaningfully
-			var xLabel string
-			if i == 0 {
-				// First entry should show as "Start"
-				xLabel = "Start"
-			} else if daysFromStart < 1 {
-				// Less than a day - show hours
-				hoursFromStart := entry.Date.Sub(startTime).Hours()
-				xLabel = fmt.Sprintf("%.0fh", hoursFromStart)
-			} else if daysFromStart < 7 {
-				// Less than a week - show days with decimal
-				xLabel = fmt.Sprintf("%.1fd", daysFromStart)
-			} else if daysFromStart < 30 {
-				// Less than a month - show days as integers
-				xLabel = fmt.Sprintf("%.0fd", daysFromStart)
-			} else {
-				// More than a month - show weeks
-				weeksFromStart := daysFromStart / 7
-				xLabel = fmt.Sprintf("%.1fw", weeksFromStart)
-			}
-
-			xAxisData = append(xAxisData, xLabel)
-			yAxisData = append(yAxisData, opts.LineData{Value: entry.Weight})
+	// Trend values line up with the plotted entries, which are in date order
+	trend, err := chartTrend(validEntries, options)
+	if err != nil {
+		return nil, err
+	}
+	for _, point := range trend {
+		if hasProperDates {
+			trendData = append(trendData, opts.LineData{Value: []interface{}{chartTimestamp(point.Date), roundTrend(point.Weight)}})
+			continue
 		}
+		trendData = append(trendData, opts.LineData{Value: roundTrend(point.Weight)})
 	}
 
 	// Create line chart with area filling
@@ -278,16 +282,31 @@ func generateHTMLChart(entries []WeightEntry, options GraphOptions) (string, err
 		subtitle = fmt.Sprintf("Total entries: %d", len(entries))
 	}
 
+	xAxis := opts.XAxis{
+		Name: "Entry",
+		Type: "category",
+		AxisLabel: &opts.AxisLabel{
+			Show: &[]bool{true}[0],
+		},
+		SplitLine: &opts.SplitLine{
+			Show: &[]bool{false}[0], // Remove grid lines for cleaner look
+		},
+	}
+	if hasProperDates {
+		xAxis.Name = "Date"
+		xAxis.Type = "time"
+		if options.DateLayout != "" {
+			xAxis.AxisLabel.Formatter = types.FuncStr(echartsTimeFormat(options.DateLayout))
+		}
+	}
+
 	line.SetGlobalOptions(
-		charts.WithInitializationOpts(opts.Initialization{
-			Width:  "1600px", // Further increased width to prevent text cutoff
-			Height: "800px",  // Increased height for better visibility
-		}),
+		charts.WithInitializationOpts(chartInitialization(options)),
 		charts.WithGridOpts(opts.Grid{
 			Left:   "15%", // More left padding to prevent title overlap
 			Right:  "10%", // More right padding for X-axis label
 			Top:    "20%", // More top padding for title space
-			Bottom: "15%", // More bottom padding for X-axis label
+			Bottom: "15%", // More bottom padding for X-axis label and zoom slider
 		}),
 		charts.WithTitleOpts(opts.Title{
 			Title:    options.Title, // Don't add entry count here, it's already in the title
@@ -296,28 +315,23 @@ func generateHTMLChart(entries []WeightEntry, options GraphOptions) (string, err
 			Top:      "5%",     // Position title higher to avoid overlap
 		}),
 		charts.WithTooltipOpts(opts.Tooltip{
-			Show: &[]bool{true}[0],
+			Show:    &[]bool{true}[0],
+			Trigger: "axis",
 		}),
 		charts.WithLegendOpts(opts.Legend{
 			Show: &[]bool{true}[0],
 		}),
-		charts.WithXAxisOpts(opts.XAxis{
-			Name: "Days from Start",
-			Type: "category",
-			AxisLabel: &opts.AxisLabel{
-				Show: &[]bool{true}[0],
-			},
-			SplitLine: &opts.SplitLine{
-				Show: &[]bool{false}[0], // Remove grid lines for cleaner look
-			},
-		}),
+		charts.WithXAxisOpts(xAxis),
 		charts.WithYAxisOpts(opts.YAxis{
 			// Remove Y-axis label to prevent overlap with title
 		}),
+		charts.WithDataZoomOpts(chartDataZoom(validEntries, hasProperDates)...),
 	)
 
-	line.SetXAxis(xAxisData).
-		AddSeries("Weight", yAxisData).
+	if !hasProperDates {
+		line.SetXAxis(xAxisData)
+	}
+	line.AddSeries(fmt.Sprintf("Weight (%s)", chartUnit(entries, options)), yAxisData).
 		SetSeriesOptions(
 			charts.WithLineChartOpts(opts.LineChart{
 				Smooth:       &[]bool{true}[0],
@@ -328,31 +342,135 @@ func generateHTMLChart(entries []WeightEntry, options GraphOptions) (string, err
 			}),
 		)
 
-	// Generate HTML with proper output directory
-	outputFile, err := ensureOutputDir(options.OutputFile, options.TestOutputDir)
-	if err != nil {
-		return "", err
+	line.SetSeriesOptions(goalMarkLine(options.Goal, options.DateLayout)...)
+
+	if trendData != nil {
+		line.AddSeries(trendLabel(options.Trend), trendData,
+			charts.WithLineChartOpts(opts.LineChart{
+				Smooth:     &[]bool{options.Trend.Method != TrendLinear}[0],
+				ShowSymbol: &[]bool{false}[0],
+			}),
+			charts.WithItemStyleOpts(opts.ItemStyle{
+				Color: "#ee6666",
+			}),
+			charts.WithLineStyleOpts(opts.LineStyle{
+				Type:  "dashed",
+				Width: 2,
+			}),
+		)
 	}
 
-	f, err := os.Create(outputFile)
-	if err != nil {
-		return "", fmt.Errorf("failed to create output file: %w", err)
+	return line, nil
+}
+
+// chartInitialization returns the page setup of an HTML chart: a fixed large canvas
+// for chart files, or one filling the page for embedding in the dashboard
+func chartInitialization(options GraphOptions) opts.Initialization {
+	if options.Responsive {
+		return opts.Initialization{Width: "100%", Height: "95vh"}
 	}
-	defer f.Close()
+	return opts.Initialization{
+		Width:  "1600px", // Further increased width to prevent text cutoff
+		Height: "800px",  // Increased height for better visibility
+	}
+}
 
-	err = line.Render(f)
-	if err != nil {
-		return "", fmt.Errorf("failed to render chart: %w", err)
+// chartTimestamp formats a date for the time axis of an HTML chart. Without a zone,
+// ECharts reads it as the viewer's local time, so the chart shows the wall-clock time of
+// the entry in the configured time zone wherever it is opened.
+func chartTimestamp(t time.Time) string {
+	return t.Format("2006-01-02 15:04:05")
+}
+
+// zoomWindowDays is the history shown when an HTML chart opens; longer histories get a
+// slider to move and resize the window
+const zoomWindowDays = 90
+
+// chartDataZoom returns the zoom controls of an HTML chart of entries (in date order):
+// zooming with the mouse wheel or touch, plus a slider opened on the last zoomWindowDays
+// days when the entries span more than that
+func chartDataZoom(entries []WeightEntry, timed bool) []opts.DataZoom {
+	zoom := []opts.DataZoom{{Type: "inside", XAxisIndex: 0, FilterMode: "none"}}
+	if !timed || len(entries) < 2 {
+		return zoom
 	}
 
-	return outputFile, nil
+	spanDays := entries[len(entries)-1].Date.Sub(entries[0].Date).Hours() / 24
+	if spanDays <= zoomWindowDays {
+		return zoom
+	}
+	start := float32(100 * (1 - zoomWindowDays/spanDays))
+	zoom[0].Start, zoom[0].End = start, 100
+	return append(zoom, opts.DataZoom{Type: "slider", XAxisIndex: 0, FilterMode: "none", Start: start, End: 100})
+}
+
+// echartsLayoutElements maps the elements of Go layouts to ECharts time format
+// placeholders, longest first so that "2006" is not read as "2" followed by "006"
+var echartsLayoutElements = []struct{ layout, placeholder string }{
+	{"January", "{MMMM}"},
+	{"Monday", "{eeee}"},
+	{"2006", "{yyyy}"},
+	{"Jan", "{MMM}"},
+	{"Mon", "{ee}"},
+	{"_2", "{d}"},
+	{"01", "{MM}"},
+	{"02", "{dd}"},
+	{"03", "{hh}"},
+	{"04", "{mm}"},
+	{"05", "{ss}"},
+	{"06", "{yy}"},
+	{"15", "{HH}"},
+	{"PM", "{A}"},
+	{"pm", "{a}"},
+	{"1", "{M}"},
+	{"2", "{d}"},
+	{"3", "{h}"},
+}
+
+// echartsTimeFormat converts a Go date layout (DATE_DISPLAY_FORMAT) to an ECharts time
+// axis label template, such as "02-01-2006" to "{dd}-{MM}-{yyyy}"
+func echartsTimeFormat(layout string) string {
+	var format strings.Builder
+	for len(layout) > 0 {
+		matched := false
+		for _, element := range echartsLayoutElements {
+			if strings.HasPrefix(layout, element.layout) {
+				format.WriteString(element.placeholder)
+				layout = layout[len(element.layout):]
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			format.WriteByte(layout[0])
+			layout = layout[1:]
+		}
+	}
+	return format.String()
 }
 
-// generatePNGChart creates a PNG chart (placeholder - go-echarts doesn't directly support PNG)
-func generatePNGChart(entries []WeightEntry, options GraphOptions) (string, error) {
-	// Note: go-echarts generates HTML/JS, not direct PNG
-	// For PNG output, we'd need to use a headless browser or different library
-	// For now, we'll generate HTML and suggest opening in browser
-	options.OutputType = OutputHTML
-	return generateHTMLChart(entries, options)
+// chartUnit returns the unit label for a chart: the requested display unit,
+// otherwise the unit of the first entry (kg when unset)
+func chartUnit(entries []WeightEntry, options GraphOptions) string {
+	if options.DisplayUnit != "" {
+		return options.DisplayUnit
+	}
+	if len(entries) > 0 && entries[0].Unit != "" {
+		return entries[0].Unit
+	}
+	return UnitKg
+}
+
+// chartTrend computes the trend overlay of a chart; nil when no trend is requested.
+// Points are returned in date order, matching the (sorted) plotted entries.
+func chartTrend(entries []WeightEntry, options GraphOptions) ([]TrendPoint, error) {
+	if options.Trend.Method == "" || len(entries) == 0 {
+		return nil, nil
+	}
+
+	trend, err := calculateTrend(entries, options.Trend)
+	if err != nil {
+		return nil, fmt.Errorf("failed to calculate trend: %w", err)
+	}
+	return trend.Points, nil
 }
@@ -0,0 +1,212 @@
+package tracker
+
+// graph_compare.go - Comparison charts with one line per user or period
+// Related files: graph.go (chart options and the single line chart), list.go
+// (--compare-users, --compare-years), graph_compare_test.go (tests)
+// Users are compared on a shared time axis. Periods, such as this January and last
+// January, are aligned by day of period so that they overlay.
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/go-echarts/go-echarts/v2/charts"
+	"github.com/go-echarts/go-echarts/v2/opts"
+	"github.com/go-echarts/go-echarts/v2/types"
+)
+
+// ChartSeries is one line of a comparison chart
+type ChartSeries struct {
+	Name    string
+	Entries []WeightEntry
+	// Start aligns the series by day of period: entries are plotted at their day
+	// counted from Start, so that periods starting on different dates overlay (zero
+	// plots entries at their dates)
+	Start time.Time
+}
+
+// seriesColors are the colors of the series of comparison charts, in order (the
+// ECharts default palette, so the first matches the single line chart)
+var seriesColors = []string{"#5470c6", "#91cc75", "#fac858", "#ee6666", "#73c0de", "#3ba272", "#fc8452", "#9a60b4", "#ea7ccc"}
+
+// compareUserSeries lists the entries matching options of each user, one series per user
+func compareUserSeries(ctx context.Context, store Store, options ListOptions, users []string) ([]ChartSeries, error) {
+	series := make([]ChartSeries, 0, len(users))
+	for _, user := range users {
+		options.UserID = user
+		entries, err := store.ListWeights(ctx, options)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list weights of user '%s': %w", user, err)
+		}
+		series = append(series, ChartSeries{Name: user, Entries: entries})
+	}
+	return series, nil
+}
+
+// compareYearSeries lists the entries matching options in the period from options.FromDate
+// to options.ToDate and in the same period of each of the years before it, most recent
+// first, aligned by day of period
+func compareYearSeries(ctx context.Context, store Store, options ListOptions, years int) ([]ChartSeries, error) {
+	if options.FromDate == nil || options.ToDate == nil {
+		return nil, fmt.Errorf("comparing years needs the start and end of the period")
+	}
+	from, to := *options.FromDate, *options.ToDate
+
+	series := make([]ChartSeries, 0, years+1)
+	for back := 0; back <= years; back++ {
+		start, end := from.AddDate(-back, 0, 0), to.AddDate(-back, 0, 0)
+		options.FromDate, options.ToDate = &start, &end
+		entries, err := store.ListWeights(ctx, options)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list weights from %s to %s: %w", start.Format("2006-01-02"), end.Format("2006-01-02"), err)
+		}
+		series = append(series, ChartSeries{Name: periodName(start, end), Entries: entries, Start: start})
+	}
+	return series, nil
+}
+
+// periodName names the period from start to end by its year when it lies within one
+func periodName(start, end time.Time) string {
+	if start.Year() == end.Year() {
+		return fmt.Sprint(start.Year())
+	}
+	return fmt.Sprintf("%s to %s", start.Format("2006-01-02"), end.Format("2006-01-02"))
+}
+
+// seriesNames joins the names of series for a chart title
+func seriesNames(series []ChartSeries) string {
+	names := make([]string, len(series))
+	for i, s := range series {
+		names[i] = s.Name
+	}
+	return strings.Join(names, ", ")
+}
+
+// prepareChartSeries converts the entries of each series to the display unit and sorts
+// them by date. Series without entries are kept, so that they appear in the legend,
+// but at least one series must have entries.
+func prepareChartSeries(series []ChartSeries, options GraphOptions) ([]ChartSeries, error) {
+	prepared := make([]ChartSeries, len(series))
+	hasEntries := false
+	for i, s := range series {
+		prepared[i] = s
+		if len(s.Entries) == 0 {
+			continue
+		}
+		entries, err := prepareChartEntries(s.Entries, options)
+		if err != nil {
+			return nil, err
+		}
+		prepared[i].Entries = entries
+		hasEntries = true
+	}
+	if !hasEntries {
+		return nil, fmt.Errorf("no weight entries to display")
+	}
+	return prepared, nil
+}
+
+// dayOfPeriod returns the day of date counted from the day of start (day 1), with the
+// time of day as a fraction, so that entries logged on the same day of two periods line up
+func dayOfPeriod(date, start time.Time) float64 {
+	midnight := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, date.Location())
+	first := time.Date(start.Year(), start.Month(), start.Day(), 0, 0, 0, 0, date.Location())
+	// Rounded, as days across a daylight saving change are not 24 hours long
+	days := math.Round(midnight.Sub(first).Hours() / 24)
+	return math.Round((days+1+date.Sub(midnight).Hours()/24)*100) / 100
+}
+
+// buildComparisonChart builds a line chart with one line (and trend) per series of
+// options.Series (prepared by prepareChartSeries)
+func buildComparisonChart(options GraphOptions) (*charts.Line, error) {
+	aligned := false
+	var allEntries []WeightEntry
+	for _, s := range options.Series {
+		aligned = aligned || !s.Start.IsZero()
+		allEntries = append(allEntries, s.Entries...)
+	}
+	if len(allEntries) == 0 {
+		return nil, fmt.Errorf("no entries to display")
+	}
+	sort.SliceStable(allEntries, func(i, j int) bool {
+		return allEntries[i].Date.Before(allEntries[j].Date)
+	})
+
+	// x places a date of a series on the x axis
+	x := func(s ChartSeries, date time.Time) interface{} { return chartTimestamp(date) }
+	xAxis := opts.XAxis{Name: "Date", Type: "time", AxisLabel: &opts.AxisLabel{Show: &[]bool{true}[0]}}
+	if options.DateLayout != "" {
+		xAxis.AxisLabel.Formatter = types.FuncStr(echartsTimeFormat(options.DateLayout))
+	}
+	subtitle := chartPeriodSubtitle(allEntries)
+	zoom := chartDataZoom(allEntries, true)
+	if aligned {
+		x = func(s ChartSeries, date time.Time) interface{} { return dayOfPeriod(date, s.Start) }
+		xAxis = opts.XAxis{Name: "Day", Type: "value", Min: 1}
+		subtitle = "Periods aligned by day: day 1 is the first day of each period"
+		zoom = chartDataZoom(nil, false)
+	}
+
+	line := charts.NewLine()
+	line.SetGlobalOptions(
+		charts.WithInitializationOpts(chartInitialization(options)),
+		charts.WithGridOpts(opts.Grid{Left: "10%", Right: "10%", Top: "20%", Bottom: "15%"}),
+		charts.WithTitleOpts(opts.Title{
+			Title:    options.Title,
+			Subtitle: subtitle,
+			Left:     "center",
+			Top:      "5%",
+		}),
+		charts.WithTooltipOpts(opts.Tooltip{Show: &[]bool{true}[0], Trigger: "axis"}),
+		charts.WithLegendOpts(opts.Legend{Show: &[]bool{true}[0], Top: "bottom"}),
+		charts.WithXAxisOpts(xAxis),
+		charts.WithYAxisOpts(opts.YAxis{
+			Name:  fmt.Sprintf("Weight (%s)", chartUnit(allEntries, options)),
+			Scale: &[]bool{true}[0],
+		}),
+		charts.WithDataZoomOpts(zoom...),
+	)
+
+	for i, s := range options.Series {
+		color := seriesColors[i%len(seriesColors)]
+
+		data := make([]opts.LineData, 0, len(s.Entries))
+		for _, entry := range s.Entries {
+			data = append(data, opts.LineData{Value: []interface{}{x(s, entry.Date), entry.Weight}})
+		}
+		seriesOptions := []charts.SeriesOpts{
+			charts.WithLineChartOpts(opts.LineChart{Smooth: &[]bool{true}[0]}),
+			charts.WithItemStyleOpts(opts.ItemStyle{Color: color}),
+		}
+		if i == 0 {
+			seriesOptions = append(seriesOptions, goalMarkLine(options.Goal, options.DateLayout)...)
+		}
+		line.AddSeries(s.Name, data, seriesOptions...)
+
+		// Each trend is dashed in the color of its series
+		trend, err := chartTrend(s.Entries, options)
+		if err != nil {
+			return nil, err
+		}
+		if trend == nil {
+			continue
+		}
+		trendData := make([]opts.LineData, 0, len(trend))
+		for _, point := range trend {
+			trendData = append(trendData, opts.LineData{Value: []interface{}{x(s, point.Date), roundTrend(point.Weight)}})
+		}
+		line.AddSeries(fmt.Sprintf("%s %s", s.Name, trendLabel(options.Trend)), trendData,
+			charts.WithLineChartOpts(opts.LineChart{
+				Smooth:     &[]bool{options.Trend.Method != TrendLinear}[0],
+				ShowSymbol: &[]bool{false}[0],
+			}),
+			charts.WithItemStyleOpts(opts.ItemStyle{Color: color}),
+			charts.WithLineStyleOpts(opts.LineStyle{Type: "dashed", Width: 2}),
+		)
+	}
+	return line, nil
+}
@@ -0,0 +1,179 @@
+package tracker
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+// graph_compare_test.go - Comparison chart tests
+// * purpose: tests charts with one line per user or per period and the --compare flags.
+// * tests: day of period alignment, period names, rendered series, list --graph --compare-users/--compare-years
+// * focus: periods overlay by day, every series is drawn, and comparisons are HTML line charts.
+
+func TestDayOfPeriod(t *testing.T) {
+	amsterdam, err := time.LoadLocation("Europe/Amsterdam")
+	if err != nil {
+		t.Skipf("time zone data unavailable: %v", err)
+	}
+
+	tests := []struct {
+		name  string
+		date  time.Time
+		start time.Time
+		want  float64
+	}{
+		{name: "first day", date: time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC), start: time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC), want: 1},
+		{name: "time of day", date: time.Date(2026, 1, 10, 18, 0, 0, 0, time.UTC), start: time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC), want: 10.75},
+		{name: "across daylight saving", date: time.Date(2025, 4, 1, 6, 0, 0, 0, amsterdam), start: time.Date(2025, 3, 1, 0, 0, 0, 0, amsterdam), want: 32.25},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := dayOfPeriod(tt.date, tt.start); got != tt.want {
+				t.Errorf("dayOfPeriod() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPeriodName(t *testing.T) {
+	january := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	if got := periodName(january, january.AddDate(0, 1, -1)); got != "2026" {
+		t.Errorf("periodName() = %q, want 2026", got)
+	}
+	if got := periodName(january.AddDate(0, -1, 0), january.AddDate(0, 1, -1)); got != "2025-12-01 to 2026-01-31" {
+		t.Errorf("periodName() = %q, want the dates of a period spanning two years", got)
+	}
+}
+
+func TestRenderHTMLChart_Comparison(t *testing.T) {
+	thisYear := time.Date(2026, 1, 1, 7, 0, 0, 0, time.UTC)
+	lastYear := thisYear.AddDate(-1, 0, 0)
+
+	tests := []struct {
+		name    string
+		options GraphOptions
+		want    []string
+		wantErr string
+	}{
+		{
+			name: "users on a time axis",
+			options: GraphOptions{Series: []ChartSeries{
+				{Name: "alice", Entries: []WeightEntry{{Weight: 62, Date: thisYear, Unit: "kg"}, {Weight: 61.5, Date: thisYear.AddDate(0, 0, 1), Unit: "kg"}}},
+				{Name: "bob", Entries: []WeightEntry{{Weight: 194, Date: thisYear, Unit: "lbs"}}},
+				{Name: "carol"},
+			}, DisplayUnit: "kg"},
+			want: []string{`"name":"alice"`, `"name":"bob"`, `"name":"carol"`, `"type":"time"`, `["2026-01-01 07:00:00",62]`, `"color":"#91cc75"`, "Period: 2026-01-01 to 2026-01-02"},
+		},
+		{
+			name: "years aligned by day with trends",
+			options: GraphOptions{Series: []ChartSeries{
+				{Name: "2026", Entries: []WeightEntry{{Weight: 80, Date: thisYear.AddDate(0, 0, 4)}}, Start: thisYear},
+				{Name: "2025", Entries: []WeightEntry{{Weight: 82, Date: lastYear.AddDate(0, 0, 4)}}, Start: lastYear},
+			}, Trend: TrendOptions{Method: TrendEMA}, Goal: &Goal{TargetWeight: 78, Unit: "kg"}},
+			want: []string{`"type":"value"`, `"value":[5.29,80]`, `"value":[5.29,82]`, "2025 EMA trend", "Goal: 78.00 kg", "day 1 is the first day"},
+		},
+		{
+			name:    "no entries in any series",
+			options: GraphOptions{Series: []ChartSeries{{Name: "alice"}, {Name: "bob"}}},
+			wantErr: "no weight entries",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			tt.options.Title = "Comparison"
+			err := RenderHTMLChart(&buf, nil, tt.options)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("RenderHTMLChart() error = %v, want one containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(unexpectedErrorString(err))
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(buf.String(), want) {
+					t.Errorf("chart does not contain %s", want)
+				}
+			}
+		})
+	}
+}
+
+func TestListCommand_Compare(t *testing.T) {
+	store := NewMockStore()
+	january := time.Date(2026, 1, 10, 7, 0, 0, 0, time.UTC)
+	for _, entry := range []WeightEntry{
+		{Weight: 62, Date: january, Unit: "kg", UserID: "alice"},
+		{Weight: 88, Date: january, Unit: "kg", UserID: "bob"},
+		{Weight: 63, Date: january.AddDate(-1, 0, 0), Unit: "kg", UserID: "alice"},
+		{Weight: 64, Date: january.AddDate(-1, 2, 0), Unit: "kg", UserID: "alice"},
+	} {
+		if _, err := store.AddWeight(context.Background(), entry); err != nil {
+			t.Fatal(failedTestEntryAdditionString(err))
+		}
+	}
+	app, stdout, stderr, exitCode := newTestApp(store, map[string]string{"DATE_INPUT_FORMAT": "yyyy-mm-dd"})
+	t.Chdir(t.TempDir())
+
+	tests := []struct {
+		name      string
+		args      []string
+		want      []string
+		unwanted  []string
+		wantError string
+	}{
+		{
+			name: "users",
+			args: []string{"--compare-users", "alice, bob", "--file", "users.html"},
+			want: []string{"Weight Comparison (alice, bob)", `"name":"bob"`, `["2026-01-10 07:00:00",62]`},
+		},
+		{
+			name:     "years",
+			args:     []string{"--user", "alice", "--from", "2026-01-01", "--to", "2026-01-31", "--compare-years", "1", "--file", "years.html"},
+			want:     []string{"Weight Comparison (2026, 2025)", `"value":[10.29,62]`, `"value":[10.29,63]`},
+			unwanted: []string{"64"},
+		},
+		{name: "one user", args: []string{"--compare-users", "alice"}, wantError: "at least two users"},
+		{name: "years without a period", args: []string{"--compare-years", "2"}, wantError: "needs --from"},
+		{name: "both comparisons", args: []string{"--compare-users", "alice,bob", "--compare-years", "1"}, wantError: "cannot be combined"},
+		{name: "terminal output", args: []string{"--compare-users", "alice,bob", "--graph-output", "terminal"}, wantError: "only available as HTML"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := runCommand(t, app, stdout, stderr, exitCode, append([]string{"list", "--graph"}, tt.args...)...)
+			if tt.wantError != "" {
+				if result.exitCode != 1 || !strings.Contains(result.stderr, tt.wantError) {
+					t.Errorf("exit code %d, stderr %q, want an error containing %q", result.exitCode, result.stderr, tt.wantError)
+				}
+				return
+			}
+			if result.exitCode != 0 {
+				t.Fatalf("exit code %d, stderr %q", result.exitCode, result.stderr)
+			}
+
+			chart, err := os.ReadFile("charts/" + tt.args[len(tt.args)-1])
+			if err != nil {
+				t.Fatalf("chart file not written: %v", err)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(string(chart), want) {
+					t.Errorf("chart does not contain %s", want)
+				}
+			}
+			for _, unwanted := range tt.unwanted {
+				if strings.Contains(string(chart), `,`+unwanted+`]`) {
+					t.Errorf("chart contains the weight %s from outside the compared periods", unwanted)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,680 @@
+package tracker
+
+// graph_image.go - PNG and SVG chart rendering
+// Related files: graph.go (GenerateWeightChart selects the output), graph_font.go (PNG text),
+// graph_image_test.go (tests)
+// Static charts are drawn once by drawChart onto a chartCanvas, which is either an SVG
+// document or an in-memory image encoded as PNG. Both are pure Go, so charts can be
+// produced anywhere the tracker runs, without a browser.
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// Chart sizes in pixels; GraphOptions.Width and Height default to the default size
+const (
+	defaultChartWidth  = 800
+	defaultChartHeight = 600
+	minChartWidth      = 320
+	minChartHeight     = 240
+)
+
+// Margins around the plot area, leaving room for the title, legend and axis labels
+const (
+	chartMarginTop    = 100
+	chartMarginBottom = 56
+	chartMarginLeft   = 80
+	chartMarginRight  = 32
+)
+
+// Text sizes in pixels; text that does not fit a small chart falls back to smaller sizes
+const (
+	chartTitleSize = 20
+	chartLabelSize = 14
+	chartSmallSize = 7
+)
+
+// Colors of the static charts, matching the HTML charts
+var (
+	chartBackground = color.RGBA{0xff, 0xff, 0xff, 0xff}
+	chartTextColor  = color.RGBA{0x33, 0x33, 0x33, 0xff}
+	chartMutedColor = color.RGBA{0x6e, 0x70, 0x79, 0xff}
+	chartGridColor  = color.RGBA{0xe0, 0xe6, 0xf1, 0xff}
+	chartWeightLine = color.RGBA{0x54, 0x70, 0xc6, 0xff}
+	chartTrendLine  = color.RGBA{0xee, 0x66, 0x66, 0xff}
+	chartGoalLine   = color.RGBA{0x91, 0xcc, 0x75, 0xff}
+)
+
+// chartDashes is the dash pattern of the trend and goal lines: alternating drawn and
+// skipped lengths
+var chartDashes = []float64{8, 5}
+
+// chartPoint is a position on a chart, in pixels from the top left corner
+type chartPoint struct {
+	X, Y float64
+}
+
+// strokeStyle describes how a line is drawn
+type strokeStyle struct {
+	color  color.RGBA
+	width  float64
+	dashes []float64 // nil draws a solid line
+}
+
+// textAnchor selects which point of a text its position refers to horizontally
+type textAnchor string
+
+const (
+	anchorStart  textAnchor = "start"
+	anchorMiddle textAnchor = "middle"
+	anchorEnd    textAnchor = "end"
+)
+
+// textStyle describes how text is drawn; texts are vertically centered on their position
+type textStyle struct {
+	color  color.RGBA
+	size   float64
+	anchor textAnchor
+}
+
+// chartCanvas is a surface static charts are drawn on
+type chartCanvas interface {
+	fillRect(x, y, width, height float64, fill color.RGBA)
+	polyline(points []chartPoint, style strokeStyle)
+	circle(center chartPoint, radius float64, fill color.RGBA)
+	text(at chartPoint, s string, style textStyle)
+}
+
+// generatePNGChart creates a PNG chart file
+func generatePNGChart(entries []WeightEntry, options GraphOptions) (string, error) {
+	return writeChartFile(options, ".png", func(w io.Writer) error {
+		return renderPNGChart(w, entries, options)
+	})
+}
+
+// generateSVGChart creates an SVG chart file
+func generateSVGChart(entries []WeightEntry, options GraphOptions) (string, error) {
+	return writeChartFile(options, ".svg", func(w io.Writer) error {
+		return renderSVGChart(w, entries, options)
+	})
+}
+
+// writeChartFile creates the chart file of options, named with extension unless
+// options.OutputFile names it, and writes the chart to it with render
+func writeChartFile(options GraphOptions, extension string, render func(io.Writer) error) (string, error) {
+	outputFile, err := ensureOutputDir(options.OutputFile, options.TestOutputDir, extension)
+	if err != nil {
+		return "", err
+	}
+
+	f, err := os.Create(outputFile)
+	if err != nil {
+		return "", fmt.Errorf("failed to create output file: %w", err)
+	}
+	if err := render(f); err != nil {
+		f.Close()
+		return "", err
+	}
+	if err := f.Close(); err != nil {
+		return "", fmt.Errorf("failed to write chart: %w", err)
+	}
+	return outputFile, nil
+}
+
+// renderPNGChart draws a chart of entries, which must be sorted by date, and writes it
+// to w as a PNG image of options.Width by options.Height pixels
+func renderPNGChart(w io.Writer, entries []WeightEntry, options GraphOptions) error {
+	width, height, err := chartSize(options)
+	if err != nil {
+		return err
+	}
+
+	canvas := &rasterCanvas{img: image.NewRGBA(image.Rect(0, 0, width, height))}
+	if err := drawChart(canvas, entries, options, width, height); err != nil {
+		return err
+	}
+	if err := png.Encode(w, canvas.img); err != nil {
+		return fmt.Errorf("failed to encode chart: %w", err)
+	}
+	return nil
+}
+
+// renderSVGChart draws a chart of entries, which must be sorted by date, and writes it
+// to w as an SVG document of options.Width by options.Height pixels
+func renderSVGChart(w io.Writer, entries []WeightEntry, options GraphOptions) error {
+	width, height, err := chartSize(options)
+	if err != nil {
+		return err
+	}
+
+	canvas := &svgCanvas{}
+	fmt.Fprintf(&canvas.buf, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d">`+"\n", width, height, width, height)
+	if err := drawChart(canvas, entries, options, width, height); err != nil {
+		return err
+	}
+	canvas.buf.WriteString("</svg>\n")
+
+	if _, err := canvas.buf.WriteTo(w); err != nil {
+		return fmt.Errorf("failed to write chart: %w", err)
+	}
+	return nil
+}
+
+// chartSize returns the size of a static chart: options.Width and options.Height, or
+// the default size when they are unset
+func chartSize(options GraphOptions) (int, int, error) {
+	width, height := options.Width, options.Height
+	if width == 0 {
+		width = defaultChartWidth
+	}
+	if height == 0 {
+		height = defaultChartHeight
+	}
+	if width < minChartWidth || height < minChartHeight {
+		return 0, 0, fmt.Errorf("chart size %dx%d is too small: minimum is %dx%d", width, height, minChartWidth, minChartHeight)
+	}
+	return width, height, nil
+}
+
+// drawChart draws the chart of entries (sorted by date), with the trend and goal
+// overlays selected in options, on a canvas of width by height pixels
+func drawChart(canvas chartCanvas, entries []WeightEntry, options GraphOptions, width, height int) error {
+	if len(entries) == 0 {
+		return fmt.Errorf("no entries to display")
+	}
+
+	trend, err := chartTrend(entries, options)
+	if err != nil {
+		return err
+	}
+
+	plot := image.Rect(chartMarginLeft, chartMarginTop, width-chartMarginRight, height-chartMarginBottom)
+	left, right := float64(plot.Min.X), float64(plot.Max.X)
+	top, bottom := float64(plot.Min.Y), float64(plot.Max.Y)
+	center := float64(width) / 2
+
+	canvas.fillRect(0, 0, float64(width), float64(height), chartBackground)
+
+	// Title and subtitle, as on the HTML charts
+	maxTextWidth := float64(width - 20)
+	titleSize := fitTextSize(options.Title, maxTextWidth, chartTitleSize, chartLabelSize, chartSmallSize)
+	canvas.text(chartPoint{center, 24}, options.Title, textStyle{color: chartTextColor, size: titleSize, anchor: anchorMiddle})
+	subtitle := chartSubtitle(entries)
+	subtitleSize := fitTextSize(subtitle, maxTextWidth, chartLabelSize, chartSmallSize)
+	canvas.text(chartPoint{center, 50}, subtitle, textStyle{color: chartMutedColor, size: subtitleSize, anchor: anchorMiddle})
+
+	// Weight scale, covering the weights, the trend and the goal
+	low, high := entries[0].Weight, entries[0].Weight
+	for _, entry := range entries {
+		low, high = math.Min(low, entry.Weight), math.Max(high, entry.Weight)
+	}
+	for _, point := range trend {
+		low, high = math.Min(low, point.Weight), math.Max(high, point.Weight)
+	}
+	if options.Goal != nil {
+		low, high = math.Min(low, options.Goal.TargetWeight), math.Max(high, options.Goal.TargetWeight)
+	}
+	ticks, step := weightTicks(low, high)
+	minWeight, maxWeight := ticks[0], ticks[len(ticks)-1]
+	weightY := func(weight float64) float64 {
+		return bottom - (weight-minWeight)/(maxWeight-minWeight)*(bottom-top)
+	}
+
+	// Horizontal grid lines with the weight labels and the unit above them
+	for _, tick := range ticks {
+		y := weightY(tick)
+		canvas.polyline([]chartPoint{{left, y}, {right, y}}, strokeStyle{color: chartGridColor, width: 1})
+		canvas.text(chartPoint{left - 8, y}, strconv.FormatFloat(tick, 'f', stepDecimals(step), 64), textStyle{color: chartMutedColor, size: chartLabelSize, anchor: anchorEnd})
+	}
+	canvas.text(chartPoint{left - 8, top - 18}, chartUnit(entries, options), textStyle{color: chartMutedColor, size: chartLabelSize, anchor: anchorEnd})
+
+	// Time axis: entries are spaced by date, or evenly when they have no dates
+	entryX := chartXScale(entries, left, right)
+	canvas.polyline([]chartPoint{{left, bottom}, {right, bottom}}, strokeStyle{color: chartMutedColor, width: 1})
+	for _, tick := range chartXTicks(entries, left, right) {
+		x := entryX(tick.at)
+		canvas.polyline([]chartPoint{{x, bottom}, {x, bottom + 5}}, strokeStyle{color: chartMutedColor, width: 1})
+		// Labels at the ends of the axis are kept within the chart
+		half := textWidth(tick.label, chartLabelSize)/2 + 2
+		labelX := math.Max(half, math.Min(float64(width)-half, x))
+		canvas.text(chartPoint{labelX, bottom + 20}, tick.label, textStyle{color: chartMutedColor, size: chartLabelSize, anchor: anchorMiddle})
+	}
+
+	// Series, with the data points drawn last so that they stay visible
+	legend := []legendItem{{label: fmt.Sprintf("Weight (%s)", chartUnit(entries, options)), style: strokeStyle{color: chartWeightLine, width: 2.5}}}
+	if options.Goal != nil {
+		style := strokeStyle{color: chartGoalLine, width: 2, dashes: chartDashes}
+		y := weightY(options.Goal.TargetWeight)
+		canvas.polyline([]chartPoint{{left, y}, {right, y}}, style)
+		legend = append(legend, legendItem{label: fmt.Sprintf("Goal: %s", formatGoal(*options.Goal, options.DateLayout)), style: style})
+	}
+	if trend != nil {
+		style := strokeStyle{color: chartTrendLine, width: 2, dashes: chartDashes}
+		points := make([]chartPoint, len(trend))
+		for i, point := range trend {
+			points[i] = chartPoint{entryX(i), weightY(point.Weight)}
+		}
+		canvas.polyline(points, style)
+		legend = append(legend, legendItem{label: trendLabel(options.Trend), style: style})
+	}
+
+	points := make([]chartPoint, len(entries))
+	for i, entry := range entries {
+		points[i] = chartPoint{entryX(i), weightY(entry.Weight)}
+	}
+	canvas.polyline(points, legend[0].style)
+	for _, point := range points {
+		canvas.circle(point, 3.5, chartWeightLine)
+	}
+
+	drawLegend(canvas, legend, center, 76, maxTextWidth)
+	return nil
+}
+
+// legendItem is one series of a chart legend
+type legendItem struct {
+	label string
+	style strokeStyle
+}
+
+// drawLegend draws the legend items side by side, centered on x, in smaller text when
+// they would not fit in maxWidth
+func drawLegend(canvas chartCanvas, items []legendItem, x, y, maxWidth float64) {
+	const swatch, gap, spacing = 24.0, 6.0, 24.0
+
+	legendWidth := func(size float64) float64 {
+		total := 0.0
+		for i, item := range items {
+			if i > 0 {
+				total += spacing
+			}
+			total += swatch + gap + textWidth(item.label, size)
+		}
+		return total
+	}
+	size := float64(chartLabelSize)
+	if legendWidth(size) > maxWidth {
+		size = chartSmallSize
+	}
+
+	position := x - legendWidth(size)/2
+	for _, item := range items {
+		canvas.polyline([]chartPoint{{position, y}, {position + swatch, y}}, item.style)
+		position += swatch + gap
+		canvas.text(chartPoint{position, y}, item.label, textStyle{color: chartTextColor, size: size, anchor: anchorStart})
+		position += textWidth(item.label, size) + spacing
+	}
+}
+
+// chartSubtitle returns the subtitle of a chart: the period covered, or the number of
+// entries when they have no dates
+func chartSubtitle(entries []WeightEntry) string {
+	if !entriesHaveDates(entries) {
+		return fmt.Sprintf("Total entries: %d", len(entries))
+	}
+	return fmt.Sprintf("Period: %s to %s", entries[0].Date.Format("2006-01-02"), entries[len(entries)-1].Date.Format("2006-01-02"))
+}
+
+// entriesHaveDates reports whether every entry has a date to be placed at
+func entriesHaveDates(entries []WeightEntry) bool {
+	for _, entry := range entries {
+		if entry.Date.IsZero() {
+			return false
+		}
+	}
+	return true
+}
+
+// weightTicks returns evenly spaced round weights covering low to high, from the first
+// at or below low to the first at or above high, and the spacing between them
+func weightTicks(low, high float64) ([]float64, float64) {
+	if high-low < 1e-9 {
+		low, high = low-1, high+1
+	}
+
+	// About five intervals
+	step := niceStep((high - low) / 5)
+
+	first := math.Floor(low / step)
+	last := math.Ceil(high / step)
+	ticks := make([]float64, 0, int(last-first)+1)
+	for i := first; i <= last; i++ {
+		ticks = append(ticks, i*step)
+	}
+	return ticks, step
+}
+
+// niceStep rounds rough up to a step of 1, 2, 2.5 or 5 times a power of ten
+func niceStep(rough float64) float64 {
+	magnitude := math.Pow(10, math.Floor(math.Log10(rough)))
+	for _, factor := range []float64{1, 2, 2.5, 5} {
+		if factor*magnitude >= rough {
+			return factor * magnitude
+		}
+	}
+	return 10 * magnitude
+}
+
+// stepDecimals returns the number of decimals needed to print multiples of step
+func stepDecimals(step float64) int {
+	decimals := 0
+	for scaled := step; decimals < 6 && math.Abs(scaled-math.Round(scaled)) > 1e-6*scaled; scaled *= 10 {
+		decimals++
+	}
+	return decimals
+}
+
+// chartXScale returns the horizontal position of the entry at each index: proportional
+// to its date when the entries span some time, otherwise evenly spaced
+func chartXScale(entries []WeightEntry, left, right float64) func(int) float64 {
+	if len(entries) == 1 {
+		return func(int) float64 { return (left + right) / 2 }
+	}
+
+	start, end := entries[0].Date, entries[len(entries)-1].Date
+	if entriesHaveDates(entries) && end.After(start) {
+		span := end.Sub(start).Seconds()
+		return func(i int) float64 {
+			return left + entries[i].Date.Sub(start).Seconds()/span*(right-left)
+		}
+	}
+	return func(i int) float64 {
+		return left + float64(i)/float64(len(entries)-1)*(right-left)
+	}
+}
+
+// chartTick is a labelled position on the time axis, at the entry with index at
+type chartTick struct {
+	at    int
+	label string
+}
+
+// chartXTicks returns the labelled entries of the time axis: as many entries, spread
+// over the axis, as there is room to label
+func chartXTicks(entries []WeightEntry, left, right float64) []chartTick {
+	layout := chartDateLayout(entries)
+	label := func(i int) string {
+		if layout == "" {
+			return fmt.Sprintf("Entry %d", i+1)
+		}
+		return entries[i].Date.Format(layout)
+	}
+
+	labelWidth := textWidth(label(len(entries)-1), chartLabelSize) + 24
+	count := min(len(entries), max(2, int((right-left)/labelWidth)+1))
+	if len(entries) == 1 {
+		return []chartTick{{at: 0, label: label(0)}}
+	}
+
+	// Pick the entries closest to evenly spaced positions, each at most once
+	entryX := chartXScale(entries, left, right)
+	var ticks []chartTick
+	next := 0
+	for i := 0; i < count; i++ {
+		target := left + float64(i)/float64(count-1)*(right-left)
+		best := next
+		for j := next; j < len(entries); j++ {
+			if math.Abs(entryX(j)-target) < math.Abs(entryX(best)-target) {
+				best = j
+			}
+		}
+		if len(ticks) > 0 && entryX(best)-entryX(ticks[len(ticks)-1].at) < labelWidth {
+			continue
+		}
+		ticks = append(ticks, chartTick{at: best, label: label(best)})
+		next = best + 1
+		if next == len(entries) {
+			break
+		}
+	}
+	return ticks
+}
+
+// chartDateLayout returns the layout of the time axis labels: times for entries within
+// two days, days within a year and full dates otherwise ("" when entries have no dates)
+func chartDateLayout(entries []WeightEntry) string {
+	if !entriesHaveDates(entries) {
+		return ""
+	}
+	start, end := entries[0].Date, entries[len(entries)-1].Date
+	switch {
+	case end.Sub(start) < 48*time.Hour:
+		return "Jan 2 15:04"
+	case start.Year() == end.Year():
+		return "Jan 2"
+	default:
+		return "2006-01-02"
+	}
+}
+
+// glyphScale returns how many pixels each font pixel is drawn as at text size
+func glyphScale(size float64) int {
+	return max(1, int(math.Round(size/glyphHeight)))
+}
+
+// fitTextSize returns the first of sizes at which s fits in maxWidth, or the last size
+func fitTextSize(s string, maxWidth float64, sizes ...float64) float64 {
+	for _, size := range sizes {
+		if textWidth(s, size) <= maxWidth {
+			return size
+		}
+	}
+	return sizes[len(sizes)-1]
+}
+
+// textWidth returns the width in pixels of s drawn at text size in a PNG chart, which is
+// also used to lay out SVG charts
+func textWidth(s string, size float64) float64 {
+	count := utf8.RuneCountInString(s)
+	if count == 0 {
+		return 0
+	}
+	scale := glyphScale(size)
+	return float64(count*glyphAdvance*scale - scale)
+}
+
+// dashedSegments splits the line through points into the dashes of the pattern
+// dashes, which alternates drawn and skipped lengths starting with a drawn one
+func dashedSegments(points []chartPoint, dashes []float64) [][]chartPoint {
+	var segments [][]chartPoint
+	var current []chartPoint
+	index, remaining, drawn := 0, dashes[0], true
+
+	for i := 1; i < len(points); i++ {
+		from, to := points[i-1], points[i]
+		length := math.Hypot(to.X-from.X, to.Y-from.Y)
+		at := func(distance float64) chartPoint {
+			return chartPoint{from.X + (to.X-from.X)*distance/length, from.Y + (to.Y-from.Y)*distance/length}
+		}
+
+		for position := 0.0; position < length; {
+			step := math.Min(remaining, length-position)
+			if drawn {
+				if len(current) == 0 {
+					current = append(current, at(position))
+				}
+				current = append(current, at(position+step))
+			}
+			position += step
+			remaining -= step
+
+			if remaining <= 0 {
+				if drawn {
+					segments = append(segments, current)
+					current = nil
+				}
+				index = (index + 1) % len(dashes)
+				remaining, drawn = dashes[index], !drawn
+			}
+		}
+	}
+	if len(current) > 1 {
+		segments = append(segments, current)
+	}
+	return segments
+}
+
+// rasterCanvas draws charts into an image, with anti-aliased lines and circles
+type rasterCanvas struct {
+	img *image.RGBA
+}
+
+// blend paints the pixel at x, y with fill covering the given fraction of it
+func (c *rasterCanvas) blend(x, y int, fill color.RGBA, coverage float64) {
+	if coverage <= 0 || !(image.Point{X: x, Y: y}).In(c.img.Rect) {
+		return
+	}
+	if coverage >= 1 {
+		c.img.SetRGBA(x, y, fill)
+		return
+	}
+
+	current := c.img.RGBAAt(x, y)
+	mix := func(src, dst uint8) uint8 {
+		return uint8(math.Round(float64(src)*coverage + float64(dst)*(1-coverage)))
+	}
+	c.img.SetRGBA(x, y, color.RGBA{mix(fill.R, current.R), mix(fill.G, current.G), mix(fill.B, current.B), 0xff})
+}
+
+func (c *rasterCanvas) fillRect(x, y, width, height float64, fill color.RGBA) {
+	bounds := image.Rect(int(math.Round(x)), int(math.Round(y)), int(math.Round(x+width)), int(math.Round(y+height)))
+	bounds = bounds.Intersect(c.img.Rect)
+	for py := bounds.Min.Y; py < bounds.Max.Y; py++ {
+		for px := bounds.Min.X; px < bounds.Max.X; px++ {
+			c.img.SetRGBA(px, py, fill)
+		}
+	}
+}
+
+func (c *rasterCanvas) polyline(points []chartPoint, style strokeStyle) {
+	segments := [][]chartPoint{points}
+	if style.dashes != nil {
+		segments = dashedSegments(points, style.dashes)
+	}
+	for _, segment := range segments {
+		for i := 1; i < len(segment); i++ {
+			c.line(segment[i-1], segment[i], style.color, style.width)
+		}
+	}
+}
+
+// line draws a line of the given width from a to b, with round ends
+func (c *rasterCanvas) line(a, b chartPoint, fill color.RGBA, width float64) {
+	half := width / 2
+	minX, maxX := int(math.Floor(math.Min(a.X, b.X)-half-1)), int(math.Ceil(math.Max(a.X, b.X)+half+1))
+	minY, maxY := int(math.Floor(math.Min(a.Y, b.Y)-half-1)), int(math.Ceil(math.Max(a.Y, b.Y)+half+1))
+
+	dx, dy := b.X-a.X, b.Y-a.Y
+	lengthSquared := dx*dx + dy*dy
+	for y := minY; y <= maxY; y++ {
+		for x := minX; x <= maxX; x++ {
+			// Distance from the pixel center to the nearest point of the line
+			px, py := float64(x)+0.5, float64(y)+0.5
+			t := 0.0
+			if lengthSquared > 0 {
+				t = math.Max(0, math.Min(1, ((px-a.X)*dx+(py-a.Y)*dy)/lengthSquared))
+			}
+			distance := math.Hypot(px-(a.X+t*dx), py-(a.Y+t*dy))
+			c.blend(x, y, fill, half+0.5-distance)
+		}
+	}
+}
+
+func (c *rasterCanvas) circle(center chartPoint, radius float64, fill color.RGBA) {
+	for y := int(math.Floor(center.Y - radius - 1)); y <= int(math.Ceil(center.Y+radius+1)); y++ {
+		for x := int(math.Floor(center.X - radius - 1)); x <= int(math.Ceil(center.X+radius+1)); x++ {
+			distance := math.Hypot(float64(x)+0.5-center.X, float64(y)+0.5-center.Y)
+			c.blend(x, y, fill, radius+0.5-distance)
+		}
+	}
+}
+
+func (c *rasterCanvas) text(at chartPoint, s string, style textStyle) {
+	scale := glyphScale(style.size)
+	x := at.X
+	switch style.anchor {
+	case anchorMiddle:
+		x -= textWidth(s, style.size) / 2
+	case anchorEnd:
+		x -= textWidth(s, style.size)
+	}
+
+	left := int(math.Round(x))
+	top := int(math.Round(at.Y)) - glyphHeight*scale/2
+	for _, r := range s {
+		glyph := glyphFor(r)
+		for column, bits := range glyph {
+			for row := 0; row < glyphHeight; row++ {
+				if bits&(1<<row) == 0 {
+					continue
+				}
+				px, py := left+column*scale, top+row*scale
+				c.fillRect(float64(px), float64(py), float64(scale), float64(scale), style.color)
+			}
+		}
+		left += glyphAdvance * scale
+	}
+}
+
+// svgCanvas draws charts as SVG elements, collected in buf
+type svgCanvas struct {
+	buf bytes.Buffer
+}
+
+func (c *svgCanvas) fillRect(x, y, width, height float64, fill color.RGBA) {
+	fmt.Fprintf(&c.buf, `<rect x="%s" y="%s" width="%s" height="%s" fill="%s"/>`+"\n",
+		svgNumber(x), svgNumber(y), svgNumber(width), svgNumber(height), svgColor(fill))
+}
+
+func (c *svgCanvas) polyline(points []chartPoint, style strokeStyle) {
+	coordinates := make([]string, len(points))
+	for i, point := range points {
+		coordinates[i] = svgNumber(point.X) + "," + svgNumber(point.Y)
+	}
+
+	fmt.Fprintf(&c.buf, `<polyline points="%s" fill="none" stroke="%s" stroke-width="%s" stroke-linecap="round" stroke-linejoin="round"`,
+		strings.Join(coordinates, " "), svgColor(style.color), svgNumber(style.width))
+	if style.dashes != nil {
+		dashes := make([]string, len(style.dashes))
+		for i, dash := range style.dashes {
+			dashes[i] = svgNumber(dash)
+		}
+		fmt.Fprintf(&c.buf, ` stroke-dasharray="%s"`, strings.Join(dashes, " "))
+	}
+	c.buf.WriteString("/>\n")
+}
+
+func (c *svgCanvas) circle(center chartPoint, radius float64, fill color.RGBA) {
+	fmt.Fprintf(&c.buf, `<circle cx="%s" cy="%s" r="%s" fill="%s"/>`+"\n",
+		svgNumber(center.X), svgNumber(center.Y), svgNumber(radius), svgColor(fill))
+}
+
+func (c *svgCanvas) text(at chartPoint, s string, style textStyle) {
+	fmt.Fprintf(&c.buf, `<text x="%s" y="%s" font-family="sans-serif" font-size="%s" fill="%s" text-anchor="%s" dominant-baseline="middle">`,
+		svgNumber(at.X), svgNumber(at.Y), svgNumber(style.size), svgColor(style.color), style.anchor)
+	// Escaping into a bytes.Buffer cannot fail
+	_ = xml.EscapeText(&c.buf, []byte(s))
+	c.buf.WriteString("</text>\n")
+}
+
+// svgNumber formats a coordinate or length with at most two decimals
+func svgNumber(value float64) string {
+	return strconv.FormatFloat(math.Round(value*100)/100, 'f', -1, 64)
+}
+
+// svgColor formats a color as a hex triplet
+func svgColor(c color.RGBA) string {
+	return fmt.Sprintf("#%02x%02x%02x", c.R, c.G, c.B)
+}
@@ -0,0 +1,239 @@
+package tracker
+
+import (
+	"bytes"
+	"encoding/xml"
+	"image/color"
+	"image/png"
+	"io"
+	"math"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+// graph_image_test.go - PNG and SVG chart tests
+// * purpose: tests the static chart renderers and the drawing helpers they share.
+// * tests: decoding the PNG and parsing the SVG output at several sizes, axis ticks, dashes
+// * focus: requested dimensions, well-formed output, drawn series and escaped text.
+
+// chartTestEntries returns a few entries spread over a month, for chart rendering
+func chartTestEntries() []WeightEntry {
+	baseDate := time.Date(2024, 1, 1, 8, 0, 0, 0, time.UTC)
+	return []WeightEntry{
+		{ID: 1, Weight: 75.5, Date: baseDate, Unit: "kg"},
+		{ID: 2, Weight: 76.0, Date: baseDate.AddDate(0, 0, 14), Unit: "kg"},
+		{ID: 3, Weight: 75.2, Date: baseDate.AddDate(0, 1, 0), Unit: "kg"},
+	}
+}
+
+func TestRenderPNGChart(t *testing.T) {
+	tests := []struct {
+		name       string
+		options    GraphOptions
+		wantWidth  int
+		wantHeight int
+		wantErr    bool
+	}{
+		{name: "default size", options: GraphOptions{Title: "Test Chart"}, wantWidth: 800, wantHeight: 600},
+		{name: "wide chart", options: GraphOptions{Title: "Test Chart", Width: 1200, Height: 400}, wantWidth: 1200, wantHeight: 400},
+		{name: "minimum size with overlays", options: GraphOptions{Title: "Test Chart", Width: 320, Height: 240, Trend: TrendOptions{Method: TrendEMA}, Goal: &Goal{TargetWeight: 74, Unit: "kg"}}, wantWidth: 320, wantHeight: 240},
+		{name: "too small", options: GraphOptions{Width: 100, Height: 600}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			err := renderPNGChart(&buf, chartTestEntries(), tt.options)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("renderPNGChart() expected error, got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(unexpectedErrorString(err))
+			}
+
+			img, err := png.Decode(&buf)
+			if err != nil {
+				t.Fatalf("output is not a PNG image: %v", err)
+			}
+			if bounds := img.Bounds(); bounds.Dx() != tt.wantWidth || bounds.Dy() != tt.wantHeight {
+				t.Fatalf("image size = %dx%d, want %dx%d", bounds.Dx(), bounds.Dy(), tt.wantWidth, tt.wantHeight)
+			}
+
+			// The weight line is drawn in its own color
+			weightPixels := 0
+			bounds := img.Bounds()
+			for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+				for x := bounds.Min.X; x < bounds.Max.X; x++ {
+					if color.RGBAModel.Convert(img.At(x, y)) == chartWeightLine {
+						weightPixels++
+					}
+				}
+			}
+			if weightPixels < 50 {
+				t.Errorf("found %d pixels of the weight line, want a drawn line", weightPixels)
+			}
+		})
+	}
+}
+
+// svgElement is the part of an SVG element checked by the tests
+type svgElement struct {
+	name  string
+	attrs map[string]string
+	text  string
+}
+
+// parseSVG parses an SVG document into its elements, failing the test if it is not
+// well-formed XML
+func parseSVG(t *testing.T, document string) []svgElement {
+	t.Helper()
+	decoder := xml.NewDecoder(strings.NewReader(document))
+	var elements []svgElement
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			return elements
+		}
+		if err != nil {
+			t.Fatalf("output is not well-formed XML: %v", err)
+		}
+		switch token := token.(type) {
+		case xml.StartElement:
+			element := svgElement{name: token.Name.Local, attrs: map[string]string{}}
+			for _, attr := range token.Attr {
+				element.attrs[attr.Name.Local] = attr.Value
+			}
+			elements = append(elements, element)
+		case xml.CharData:
+			if len(elements) > 0 {
+				elements[len(elements)-1].text += string(token)
+			}
+		}
+	}
+}
+
+func TestRenderSVGChart(t *testing.T) {
+	var buf bytes.Buffer
+	options := GraphOptions{
+		Title:  "Alice & Bob <weekly>",
+		Width:  1000,
+		Height: 500,
+		Trend:  TrendOptions{Method: TrendSMA, WindowDays: 14},
+		Goal:   &Goal{TargetWeight: 74, Unit: "kg"},
+	}
+	if err := renderSVGChart(&buf, chartTestEntries(), options); err != nil {
+		t.Fatal(unexpectedErrorString(err))
+	}
+
+	elements := parseSVG(t, buf.String())
+	if len(elements) == 0 || elements[0].name != "svg" {
+		t.Fatalf("root element is missing, got %d elements", len(elements))
+	}
+	if root := elements[0]; root.attrs["width"] != "1000" || root.attrs["height"] != "500" {
+		t.Errorf("svg size = %sx%s, want 1000x500", root.attrs["width"], root.attrs["height"])
+	}
+
+	var circles, dashed int
+	var texts []string
+	for _, element := range elements {
+		switch element.name {
+		case "circle":
+			circles++
+		case "polyline":
+			if element.attrs["stroke-dasharray"] != "" {
+				dashed++
+			}
+		case "text":
+			texts = append(texts, strings.TrimSpace(element.text))
+		}
+	}
+	if circles != 3 {
+		t.Errorf("got %d data points, want 3", circles)
+	}
+	// The trend and goal lines, and their legend swatches
+	if dashed != 4 {
+		t.Errorf("got %d dashed lines, want 4", dashed)
+	}
+	for _, want := range []string{"Alice & Bob <weekly>", "Period: 2024-01-01 to 2024-02-01", "Weight (kg)", "Goal: 74.00 kg"} {
+		if !slices.Contains(texts, want) {
+			t.Errorf("texts %q do not include %q", texts, want)
+		}
+	}
+}
+
+func TestWeightTicks(t *testing.T) {
+	tests := []struct {
+		name      string
+		low, high float64
+		wantTicks []float64
+		wantStep  float64
+	}{
+		{name: "whole kilograms", low: 75.2, high: 79.6, wantTicks: []float64{75, 76, 77, 78, 79, 80}, wantStep: 1},
+		{name: "tenths", low: 75.2, high: 75.9, wantTicks: []float64{75.2, 75.4, 75.6, 75.8, 76}, wantStep: 0.2},
+		{name: "quarter steps", low: 60, high: 61.2, wantTicks: []float64{60, 60.25, 60.5, 60.75, 61, 61.25}, wantStep: 0.25},
+		{name: "single weight", low: 70, high: 70, wantTicks: []float64{69, 69.5, 70, 70.5, 71}, wantStep: 0.5},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ticks, step := weightTicks(tt.low, tt.high)
+			if math.Abs(step-tt.wantStep) > 1e-9 {
+				t.Errorf("step = %v, want %v", step, tt.wantStep)
+			}
+			if len(ticks) != len(tt.wantTicks) {
+				t.Fatalf("ticks = %v, want %v", ticks, tt.wantTicks)
+			}
+			for i, want := range tt.wantTicks {
+				if math.Abs(ticks[i]-want) > 1e-9 {
+					t.Errorf("ticks = %v, want %v", ticks, tt.wantTicks)
+					break
+				}
+			}
+		})
+	}
+}
+
+func TestStepDecimals(t *testing.T) {
+	tests := []struct {
+		step float64
+		want int
+	}{
+		{step: 5, want: 0},
+		{step: 20, want: 0},
+		{step: 0.5, want: 1},
+		{step: 2.5, want: 1},
+		{step: 0.25, want: 2},
+		{step: 0.2, want: 1},
+	}
+
+	for _, tt := range tests {
+		if got := stepDecimals(tt.step); got != tt.want {
+			t.Errorf("stepDecimals(%v) = %d, want %d", tt.step, got, tt.want)
+		}
+	}
+}
+
+func TestDashedSegments(t *testing.T) {
+	// A 30px line bending after 10px, dashed 8 on and 5 off: dashes at 0-8, 13-21 and 26-30
+	points := []chartPoint{{0, 0}, {10, 0}, {10, 20}}
+	segments := dashedSegments(points, []float64{8, 5})
+
+	want := [][]chartPoint{
+		{{0, 0}, {8, 0}},
+		{{10, 3}, {10, 11}},
+		{{10, 16}, {10, 20}},
+	}
+	if len(segments) != len(want) {
+		t.Fatalf("segments = %v, want %v", segments, want)
+	}
+	for i := range want {
+		if !slices.Equal(segments[i], want[i]) {
+			t.Errorf("segment %d = %v, want %v", i, segments[i], want[i])
+		}
+	}
+}
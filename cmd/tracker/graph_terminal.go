@@ -0,0 +1,411 @@
+package tracker
+
+// graph_terminal.go - Braille charts for terminal display
+// Related files: graph.go (GenerateWeightChart selects the output), graph_image.go (shared
+// axis helpers), terminal_size_linux.go (terminal size), graph_terminal_test.go (tests)
+// Each character cell of a chart holds a 2x4 grid of Braille dots, so a chart in an 80
+// column terminal has over 140 points across. Entries are placed by date; entries
+// landing in the same dot column are averaged, with their range drawn as a bar.
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Terminal sizes in characters: the default when the terminal size is unknown, and the
+// smallest plot area drawn
+const (
+	defaultTerminalColumns = 80
+	defaultTerminalRows    = 24
+	minTerminalPlotColumns = 20
+	minTerminalPlotRows    = 5
+)
+
+// terminalChartListLimit is the largest number of entries listed below a terminal chart
+const terminalChartListLimit = 20
+
+// Lines of a terminal chart besides the plot: title, subtitle, legend and a blank line
+// above it, the axis and its labels below it, and room for the shell prompt
+const terminalChartChromeRows = 8
+
+// ANSI colors of the trend and goal lines when writing to a terminal
+const (
+	ansiReset = "\x1b[0m"
+	ansiRed   = "\x1b[31m"
+	ansiGreen = "\x1b[32m"
+)
+
+// brailleDots maps a dot position within a cell, [column][row], to its bit in the
+// Braille pattern block starting at brailleBase
+var brailleDots = [2][4]rune{
+	{0x01, 0x02, 0x04, 0x40},
+	{0x08, 0x10, 0x20, 0x80},
+}
+
+const brailleBase = 0x2800
+
+// terminalSize returns the size in characters of the terminal w writes to, falling back
+// to the COLUMNS and LINES variables and then to 80x24
+func terminalSize(w io.Writer, getEnv func(string) string) (int, int) {
+	if columns, rows, ok := windowSize(w); ok {
+		return columns, rows
+	}
+
+	columns, rows := defaultTerminalColumns, defaultTerminalRows
+	if value, err := strconv.Atoi(getEnv("COLUMNS")); err == nil && value > 0 {
+		columns = value
+	}
+	if value, err := strconv.Atoi(getEnv("LINES")); err == nil && value > 0 {
+		rows = value
+	}
+	return columns, rows
+}
+
+// dotLayer is one series of a chart drawn on a grid of Braille dots
+type dotLayer struct {
+	width, height int
+	dots          []bool
+}
+
+func newDotLayer(width, height int) *dotLayer {
+	return &dotLayer{width: width, height: height, dots: make([]bool, width*height)}
+}
+
+func (l *dotLayer) set(x, y int) {
+	if x >= 0 && x < l.width && y >= 0 && y < l.height {
+		l.dots[y*l.width+x] = true
+	}
+}
+
+// line sets the dots from (x0, y0) to (x1, y1); with every > 1 only every that many
+// dots are set, drawing a dotted line
+func (l *dotLayer) line(x0, y0, x1, y1, every int) {
+	dx, dy := abs(x1-x0), -abs(y1-y0)
+	stepX, stepY := 1, 1
+	if x0 > x1 {
+		stepX = -1
+	}
+	if y0 > y1 {
+		stepY = -1
+	}
+
+	// Bresenham's line algorithm
+	err := dx + dy
+	for count := 0; ; count++ {
+		if count%every == 0 {
+			l.set(x0, y0)
+		}
+		if x0 == x1 && y0 == y1 {
+			return
+		}
+		e2 := 2 * err
+		if e2 >= dy {
+			err += dy
+			x0 += stepX
+		}
+		if e2 <= dx {
+			err += dx
+			y0 += stepY
+		}
+	}
+}
+
+// cell returns the Braille dots set in the character cell at column, row
+func (l *dotLayer) cell(column, row int) rune {
+	var pattern rune
+	for dx := 0; dx < 2; dx++ {
+		for dy := 0; dy < 4; dy++ {
+			x, y := column*2+dx, row*4+dy
+			if x < l.width && y < l.height && l.dots[y*l.width+x] {
+				pattern |= brailleDots[dx][dy]
+			}
+		}
+	}
+	return pattern
+}
+
+func abs(value int) int {
+	if value < 0 {
+		return -value
+	}
+	return value
+}
+
+// dotBucket collects the weights plotted in one dot column
+type dotBucket struct {
+	x              int
+	sum, low, high float64
+	count          int
+}
+
+func (b dotBucket) mean() float64 {
+	return b.sum / float64(b.count)
+}
+
+// bucketWeights groups weights by the dot column of their entry, in column order
+func bucketWeights(xs []int, weights []float64) []dotBucket {
+	var buckets []dotBucket
+	for i, x := range xs {
+		if len(buckets) > 0 && buckets[len(buckets)-1].x == x {
+			bucket := &buckets[len(buckets)-1]
+			bucket.sum += weights[i]
+			bucket.count++
+			bucket.low = math.Min(bucket.low, weights[i])
+			bucket.high = math.Max(bucket.high, weights[i])
+			continue
+		}
+		buckets = append(buckets, dotBucket{x: x, sum: weights[i], low: weights[i], high: weights[i], count: 1})
+	}
+	return buckets
+}
+
+// terminalDotX returns the dot column of each entry (sorted by date) on a plot width dots
+// wide: proportional to its date when the entries span some time, otherwise evenly spaced
+func terminalDotX(entries []WeightEntry, width int) []int {
+	xs := make([]int, len(entries))
+	if len(entries) == 1 {
+		xs[0] = width / 2
+		return xs
+	}
+
+	start, end := entries[0].Date, entries[len(entries)-1].Date
+	timed := entriesHaveDates(entries) && end.After(start)
+	for i, entry := range entries {
+		position := float64(i) / float64(len(entries)-1)
+		if timed {
+			position = entry.Date.Sub(start).Seconds() / end.Sub(start).Seconds()
+		}
+		xs[i] = int(math.Round(position * float64(width-1)))
+	}
+	return xs
+}
+
+// generateASCIIChart draws a Braille chart of entries (sorted by date) sized to the
+// terminal, with the trend and goal overlays selected in options, to options.Out
+func generateASCIIChart(entries []WeightEntry, options GraphOptions) error {
+	if len(entries) == 0 {
+		return fmt.Errorf("no entries to display")
+	}
+
+	out := options.Out
+	if out == nil {
+		out = os.Stdout
+	}
+	color := isTerminal(out)
+
+	trend, err := chartTrend(entries, options)
+	if err != nil {
+		return err
+	}
+
+	// Weight scale, covering the weights, the trend and the goal
+	low, high := entries[0].Weight, entries[0].Weight
+	for _, entry := range entries {
+		low, high = math.Min(low, entry.Weight), math.Max(high, entry.Weight)
+	}
+	dataLow, dataHigh := low, high
+	for _, point := range trend {
+		low, high = math.Min(low, point.Weight), math.Max(high, point.Weight)
+	}
+	if options.Goal != nil {
+		low, high = math.Min(low, options.Goal.TargetWeight), math.Max(high, options.Goal.TargetWeight)
+	}
+	ticks, step := weightTicks(low, high)
+	minWeight, maxWeight := ticks[0], ticks[len(ticks)-1]
+	decimals := stepDecimals(step)
+
+	// Plot size: the terminal less the weight labels and the lines around the plot
+	columns, rows := options.Columns, options.Rows
+	if columns <= 0 {
+		columns = defaultTerminalColumns
+	}
+	if rows <= 0 {
+		rows = defaultTerminalRows
+	}
+	labelWidth := 0
+	for _, tick := range ticks {
+		labelWidth = max(labelWidth, len(strconv.FormatFloat(tick, 'f', decimals, 64)))
+	}
+	plotColumns := max(minTerminalPlotColumns, columns-labelWidth-3)
+	plotRows := max(minTerminalPlotRows, rows-terminalChartChromeRows)
+	dotWidth, dotHeight := plotColumns*2, plotRows*4
+	dotY := func(weight float64) int {
+		return int(math.Round((maxWeight - weight) / (maxWeight - minWeight) * float64(dotHeight-1)))
+	}
+
+	// Weights: the mean of each dot column, joined by lines, with the range of the
+	// column as a bar
+	xs := terminalDotX(entries, dotWidth)
+	weights := make([]float64, len(entries))
+	for i, entry := range entries {
+		weights[i] = entry.Weight
+	}
+	buckets := bucketWeights(xs, weights)
+	weightLayer := newDotLayer(dotWidth, dotHeight)
+	for i, bucket := range buckets {
+		weightLayer.line(bucket.x, dotY(bucket.low), bucket.x, dotY(bucket.high), 1)
+		if i > 0 {
+			previous := buckets[i-1]
+			weightLayer.line(previous.x, dotY(previous.mean()), bucket.x, dotY(bucket.mean()), 1)
+		}
+	}
+
+	trendLayer := newDotLayer(dotWidth, dotHeight)
+	if trend != nil {
+		trendWeights := make([]float64, len(trend))
+		for i, point := range trend {
+			trendWeights[i] = point.Weight
+		}
+		trendBuckets := bucketWeights(xs, trendWeights)
+		for i := 1; i < len(trendBuckets); i++ {
+			from, to := trendBuckets[i-1], trendBuckets[i]
+			trendLayer.line(from.x, dotY(from.mean()), to.x, dotY(to.mean()), 2)
+		}
+	}
+
+	goalLayer := newDotLayer(dotWidth, dotHeight)
+	if options.Goal != nil {
+		y := dotY(options.Goal.TargetWeight)
+		for x := 0; x < dotWidth; x += 4 {
+			goalLayer.set(x, y)
+			goalLayer.set(x+1, y)
+		}
+	}
+
+	// Header
+	fmt.Fprintf(out, "\n%s\n", options.Title)
+	fmt.Fprintf(out, "%s, range %.1f - %.1f %s\n", chartSubtitle(entries), dataLow, dataHigh, chartUnit(entries, options))
+	legend := []string{fmt.Sprintf("⣀⣀ Weight (%s)", chartUnit(entries, options))}
+	if trend != nil {
+		legend = append(legend, colorize("⡀⢀", ansiRed, color)+" "+trendLabel(options.Trend))
+	}
+	if options.Goal != nil {
+		legend = append(legend, colorize("⠉⠁", ansiGreen, color)+" Goal: "+formatGoal(*options.Goal, options.DateLayout))
+	}
+	fmt.Fprintf(out, "%s\n\n", strings.Join(legend, "   "))
+
+	// Plot rows, with the weight of each tick labelled on the row it falls in
+	rowLabels := make(map[int]string, len(ticks))
+	for _, tick := range ticks {
+		rowLabels[dotY(tick)/4] = strconv.FormatFloat(tick, 'f', decimals, 64)
+	}
+	for row := 0; row < plotRows; row++ {
+		label, ok := rowLabels[row]
+		axis := "│"
+		if ok {
+			axis = "┤"
+		}
+		var line strings.Builder
+		fmt.Fprintf(&line, "%*s %s", labelWidth, label, axis)
+		for column := 0; column < plotColumns; column++ {
+			weightDots := weightLayer.cell(column, row)
+			trendDots := trendLayer.cell(column, row)
+			goalDots := goalLayer.cell(column, row)
+			pattern := weightDots | trendDots | goalDots
+			switch {
+			case pattern == 0:
+				line.WriteByte(' ')
+			case weightDots == 0 && trendDots != 0:
+				line.WriteString(colorize(string(brailleBase+pattern), ansiRed, color))
+			case weightDots == 0:
+				line.WriteString(colorize(string(brailleBase+pattern), ansiGreen, color))
+			default:
+				line.WriteRune(brailleBase + pattern)
+			}
+		}
+		fmt.Fprintln(out, strings.TrimRight(line.String(), " "))
+	}
+
+	// Time axis with dates below it
+	labels := terminalAxisLabels(entries, xs, plotColumns)
+	axis := []rune(strings.Repeat("─", plotColumns))
+	labelLine := []rune(strings.Repeat(" ", plotColumns))
+	for _, label := range labels {
+		axis[label.column] = '┬'
+		text := []rune(label.text)
+		start := min(max(0, label.column-len(text)/2), plotColumns-len(text))
+		copy(labelLine[start:], text)
+	}
+	fmt.Fprintf(out, "%*s └%s\n", labelWidth, "", string(axis))
+	fmt.Fprintf(out, "%*s  %s\n", labelWidth, "", strings.TrimRight(string(labelLine), " "))
+
+	if len(buckets) < len(entries) {
+		fmt.Fprintf(out, "\nAveraged %d entries into %d columns (bars show the range of each column)\n", len(entries), len(buckets))
+	}
+
+	if len(entries) > terminalChartListLimit {
+		return nil
+	}
+
+	// Entry details
+	fmt.Fprintf(out, "\nWeight Entries:\n")
+	for i, entry := range entries {
+		fmt.Fprintf(out, "  %2d. %s: %.1f %s", i+1, entry.Date.Format("2006-01-02 15:04"), entry.Weight, entry.Unit)
+		if trend != nil {
+			fmt.Fprintf(out, " [trend %.1f]", trend[i].Weight)
+		}
+		if entry.Note != "" {
+			fmt.Fprintf(out, " (%s)", entry.Note)
+		}
+		fmt.Fprintln(out)
+	}
+	return nil
+}
+
+// colorize wraps s in an ANSI color when color is set
+func colorize(s, ansiColor string, color bool) string {
+	if !color {
+		return s
+	}
+	return ansiColor + s + ansiReset
+}
+
+// terminalLabel is a label of the time axis, centered on a character column
+type terminalLabel struct {
+	column int
+	text   string
+}
+
+// terminalAxisLabels returns labels spread along the time axis of a plot plotColumns
+// wide: the dates of the entries nearest to evenly spaced columns (or their numbers when
+// entries have no dates), as many as fit without overlapping
+func terminalAxisLabels(entries []WeightEntry, xs []int, plotColumns int) []terminalLabel {
+	layout := chartDateLayout(entries)
+	label := func(i int) string {
+		if layout == "" {
+			return fmt.Sprintf("#%d", i+1)
+		}
+		return entries[i].Date.Format(layout)
+	}
+
+	width := len(label(len(entries)-1)) + 2
+	count := max(1, min(len(entries), plotColumns/width))
+	var labels []terminalLabel
+	used := []int{}
+	for i := 0; i < count; i++ {
+		target := 0
+		if count > 1 {
+			target = i * (plotColumns - 1) * 2 / (count - 1)
+		}
+
+		// The entry nearest to the target dot column
+		nearest := 0
+		for j, x := range xs {
+			if abs(x-target) < abs(xs[nearest]-target) {
+				nearest = j
+			}
+		}
+		column := xs[nearest] / 2
+		if slices.Contains(used, nearest) || (len(labels) > 0 && column-labels[len(labels)-1].column < width) {
+			continue
+		}
+		used = append(used, nearest)
+		labels = append(labels, terminalLabel{column: column, text: label(nearest)})
+	}
+	return labels
+}
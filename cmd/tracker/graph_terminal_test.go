@@ -0,0 +1,216 @@
+package tracker
+
+import (
+	"bytes"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+)
+
+// graph_terminal_test.go - Terminal chart tests
+// * purpose: tests the Braille chart renderer and its sizing, placement and aggregation.
+// * tests: rendered charts at fixed sizes, dot placement, column buckets, terminal size fallback
+// * focus: charts fit the terminal, keep every entry, space entries by date and label dates.
+
+// dailyEntries returns count entries one day apart, slowly losing weight
+func dailyEntries(count int) []WeightEntry {
+	baseDate := time.Date(2025, 1, 1, 7, 0, 0, 0, time.UTC)
+	entries := make([]WeightEntry, count)
+	for i := range entries {
+		entries[i] = WeightEntry{ID: int64(i + 1), Weight: 80 - float64(i)*0.1, Date: baseDate.AddDate(0, 0, i), Unit: "kg"}
+	}
+	return entries
+}
+
+func TestGenerateTerminalChart(t *testing.T) {
+	tests := []struct {
+		name           string
+		entries        []WeightEntry
+		options        GraphOptions
+		wantPlotRows   int
+		wantLabels     []string
+		wantContain    []string
+		wantNotContain []string
+	}{
+		{
+			name:         "few entries are listed",
+			entries:      dailyEntries(3),
+			options:      GraphOptions{Title: "Chart", Columns: 80, Rows: 24},
+			wantPlotRows: 16,
+			wantLabels:   []string{"Jan 1", "Jan 3"},
+			wantContain:  []string{"Period: 2025-01-01 to 2025-01-03, range 79.8 - 80.0 kg", "Weight Entries:", "3. 2025-01-03 07:00: 79.8 kg"},
+		},
+		{
+			name:           "two months of entries are all plotted",
+			entries:        dailyEntries(60),
+			options:        GraphOptions{Title: "Chart", Columns: 100, Rows: 30},
+			wantPlotRows:   22,
+			wantLabels:     []string{"Jan 1", "Mar 1"},
+			wantContain:    []string{"range 74.1 - 80.0 kg"},
+			wantNotContain: []string{"Weight Entries:", "Averaged"},
+		},
+		{
+			name:         "more entries than columns are averaged",
+			entries:      dailyEntries(365),
+			options:      GraphOptions{Title: "Chart", Columns: 40, Rows: 12},
+			wantPlotRows: 5,
+			wantLabels:   []string{"Jan 1"},
+			wantContain:  []string{"Averaged 365 entries into"},
+		},
+		{
+			name:         "trend and goal overlays",
+			entries:      dailyEntries(15),
+			options:      GraphOptions{Title: "Chart", Columns: 80, Rows: 20, Trend: TrendOptions{Method: TrendEMA}, Goal: &Goal{TargetWeight: 75, Unit: "kg"}},
+			wantPlotRows: 12,
+			wantContain:  []string{"Goal: 75.00 kg", "[trend"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			tt.options.Out = &out
+			if err := generateASCIIChart(tt.entries, tt.options); err != nil {
+				t.Fatal(unexpectedErrorString(err))
+			}
+			output := out.String()
+
+			plotRows := 0
+			for _, line := range strings.Split(output, "\n") {
+				if width := utf8.RuneCountInString(line); strings.ContainsAny(line, "┤│└") && width > tt.options.Columns {
+					t.Errorf("line is %d columns wide, want at most %d: %q", width, tt.options.Columns, line)
+				}
+				if strings.ContainsAny(line, "┤│") {
+					plotRows++
+				}
+			}
+			if plotRows != tt.wantPlotRows {
+				t.Errorf("got %d plot rows, want %d\n%s", plotRows, tt.wantPlotRows, output)
+			}
+
+			// The last entry is plotted at the right edge of the plot
+			lastRow := ""
+			for _, line := range strings.Split(output, "\n") {
+				if strings.ContainsAny(line, "┤│") && utf8.RuneCountInString(line) > utf8.RuneCountInString(lastRow) {
+					lastRow = line
+				}
+			}
+			if width := utf8.RuneCountInString(lastRow); width < tt.options.Columns-2 {
+				t.Errorf("widest plot row is %d columns, want the chart to reach the right edge\n%s", width, output)
+			}
+
+			for _, want := range append(tt.wantLabels, tt.wantContain...) {
+				if !strings.Contains(output, want) {
+					t.Errorf("output does not contain %q\n%s", want, output)
+				}
+			}
+			for _, unwanted := range tt.wantNotContain {
+				if strings.Contains(output, unwanted) {
+					t.Errorf("output contains %q\n%s", unwanted, output)
+				}
+			}
+		})
+	}
+}
+
+func TestTerminalDotX(t *testing.T) {
+	baseDate := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name    string
+		entries []WeightEntry
+		want    []int
+	}{
+		{
+			name:    "spaced by date",
+			entries: []WeightEntry{{Date: baseDate}, {Date: baseDate.AddDate(0, 0, 1)}, {Date: baseDate.AddDate(0, 0, 10)}},
+			want:    []int{0, 10, 100},
+		},
+		{
+			name:    "same time spaced evenly",
+			entries: []WeightEntry{{Date: baseDate}, {Date: baseDate}, {Date: baseDate}},
+			want:    []int{0, 50, 100},
+		},
+		{
+			name:    "no dates spaced evenly",
+			entries: []WeightEntry{{}, {}},
+			want:    []int{0, 100},
+		},
+		{
+			name:    "single entry centered",
+			entries: []WeightEntry{{Date: baseDate}},
+			want:    []int{50},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := terminalDotX(tt.entries, 101); !slices.Equal(got, tt.want) {
+				t.Errorf("terminalDotX() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBucketWeights(t *testing.T) {
+	buckets := bucketWeights([]int{0, 0, 3, 7, 7, 7}, []float64{70, 72, 71, 69, 70, 74})
+
+	want := []dotBucket{
+		{x: 0, sum: 142, low: 70, high: 72, count: 2},
+		{x: 3, sum: 71, low: 71, high: 71, count: 1},
+		{x: 7, sum: 213, low: 69, high: 74, count: 3},
+	}
+	if !slices.Equal(buckets, want) {
+		t.Errorf("bucketWeights() = %+v, want %+v", buckets, want)
+	}
+	if mean := buckets[2].mean(); mean != 71 {
+		t.Errorf("mean() = %v, want 71", mean)
+	}
+}
+
+func TestDotLayer(t *testing.T) {
+	layer := newDotLayer(4, 8)
+	layer.line(0, 0, 3, 3, 1)
+
+	// The diagonal sets the top left and bottom right dots of the first cell, then the
+	// top left and bottom right dots of the lower half of the next cell
+	if got := layer.cell(0, 0); got != 0x01|0x10 {
+		t.Errorf("cell(0, 0) = %#x, want %#x", got, 0x01|0x10)
+	}
+	if got := layer.cell(1, 0); got != 0x04|0x80 {
+		t.Errorf("cell(1, 0) = %#x, want %#x", got, 0x04|0x80)
+	}
+	if got := layer.cell(1, 1); got != 0 {
+		t.Errorf("cell(1, 1) = %#x, want empty", got)
+	}
+
+	dotted := newDotLayer(8, 4)
+	dotted.line(0, 0, 7, 0, 2)
+	if got := dotted.cell(0, 0) | dotted.cell(1, 0)<<8; got != 0x01|0x01<<8 {
+		t.Errorf("dotted line cells = %#x, want every other dot", got)
+	}
+}
+
+func TestTerminalSize(t *testing.T) {
+	tests := []struct {
+		name        string
+		env         map[string]string
+		wantColumns int
+		wantRows    int
+	}{
+		{name: "defaults", env: map[string]string{}, wantColumns: 80, wantRows: 24},
+		{name: "environment", env: map[string]string{"COLUMNS": "132", "LINES": "50"}, wantColumns: 132, wantRows: 50},
+		{name: "invalid environment", env: map[string]string{"COLUMNS": "wide", "LINES": "-3"}, wantColumns: 80, wantRows: 24},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// A buffer is not a terminal, so the size comes from the environment
+			columns, rows := terminalSize(&bytes.Buffer{}, func(key string) string { return tt.env[key] })
+			if columns != tt.wantColumns || rows != tt.wantRows {
+				t.Errorf("terminalSize() = %dx%d, want %dx%d", columns, rows, tt.wantColumns, tt.wantRows)
+			}
+		})
+	}
+}
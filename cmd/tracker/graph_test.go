@@ -2,11 +2,12 @@ package tracker
 
 // graph_test.go - Unit tests for chart generation functionality
 // Related files: graph.go (main functionality), list.go (uses graph features), list_test.go (integration tests)
-// Tests ASCII, HTML, and PNG chart generation, file output, and error handling
+// Tests terminal, HTML, PNG and SVG chart generation, file output, and error handling
 
 import (
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 )
@@ -65,6 +66,27 @@ func TestGenerateWeightChart(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name:    "generate PNG chart",
+			entries: testEntries,
+			options: GraphOptions{
+				OutputType: OutputPNG,
+				Title:      "Test Chart",
+				Width:      640,
+				Height:     480,
+			},
+			wantErr: false,
+		},
+		{
+			name:    "generate SVG chart",
+			entries: testEntries,
+			options: GraphOptions{
+				OutputType: OutputSVG,
+				Title:      "Test Chart",
+				OutputFile: "test-chart",
+			},
+			wantErr: false,
+		},
 		{
 			name:    "empty entries",
 			entries: []WeightEntry{},
@@ -96,16 +118,19 @@ func TestGenerateWeightChart(t *testing.T) {
 				return
 			}
 
-			// For HTML output, verify file was created
-			if tt.options.OutputType == OutputHTML {
+			// For file output, verify the file was created with the extension of its type
+			if tt.options.OutputType != OutputTerminal {
 				if outputPath == "" {
-					t.Errorf("GenerateWeightChart() expected output path for HTML chart")
+					t.Errorf("GenerateWeightChart() expected output path for %s chart", tt.options.OutputType)
 					return
 				}
 
 				// Check if file exists
 				if _, err := os.Stat(outputPath); os.IsNotExist(err) {
-					t.Errorf("GenerateWeightChart() HTML file was not created: %s", outputPath)
+					t.Errorf("GenerateWeightChart() %s file was not created: %s", tt.options.OutputType, outputPath)
+				}
+				if ext := filepath.Ext(outputPath); ext != "."+string(tt.options.OutputType) {
+					t.Errorf("GenerateWeightChart() = %s, want a .%s file", outputPath, tt.options.OutputType)
 				}
 
 				// No cleanup needed - t.TempDir() automatically cleans up
@@ -172,26 +197,52 @@ func TestEnsureOutputDir(t *testing.T) {
 	tests := []struct {
 		name       string
 		outputFile string
+		extension  string
 		wantErr    bool
 		expectDir  string
+		expectExt  string
 	}{
 		{
 			name:       "custom filename",
 			outputFile: "my-chart.html",
+			extension:  ".html",
 			wantErr:    false,
 			expectDir:  "charts",
+			expectExt:  ".html",
 		},
 		{
 			name:       "empty filename",
 			outputFile: "",
+			extension:  ".html",
 			wantErr:    false,
 			expectDir:  "charts",
+			expectExt:  ".html",
 		},
 		{
 			name:       "nested path",
 			outputFile: "subdir/chart.html",
+			extension:  ".html",
 			wantErr:    false,
 			expectDir:  "charts/subdir",
+			expectExt:  ".html",
+		},
+		{
+			name:       "empty filename for png chart",
+			outputFile: "",
+			extension:  ".png",
+			expectExt:  ".png",
+		},
+		{
+			name:       "filename without extension",
+			outputFile: "weekly",
+			extension:  ".svg",
+			expectExt:  ".svg",
+		},
+		{
+			name:       "explicit extension is kept",
+			outputFile: "chart.img",
+			extension:  ".png",
+			expectExt:  ".img",
 		},
 	}
 
@@ -199,7 +250,7 @@ func TestEnsureOutputDir(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			// Use temporary directory for test charts
 			tempDir := t.TempDir()
-			outputPath, err := ensureOutputDir(tt.outputFile, tempDir)
+			outputPath, err := ensureOutputDir(tt.outputFile, tempDir, tt.extension)
 
 			if tt.wantErr {
 				if err == nil {
@@ -218,6 +269,9 @@ func TestEnsureOutputDir(t *testing.T) {
 			if _, err := os.Stat(dir); os.IsNotExist(err) {
 				t.Errorf("ensureOutputDir() directory was not created: %s", dir)
 			}
+			if ext := filepath.Ext(outputPath); ext != tt.expectExt {
+				t.Errorf("ensureOutputDir() = %s, want extension %s", outputPath, tt.expectExt)
+			}
 
 			// No cleanup needed - t.TempDir() automatically cleans up
 		})
@@ -244,3 +298,87 @@ func TestGraphOptions(t *testing.T) {
 		t.Errorf("Expected default height 600, got %d", options.Height)
 	}
 }
+
+func TestEchartsTimeFormat(t *testing.T) {
+	tests := []struct {
+		layout string
+		want   string
+	}{
+		{layout: "02-01-2006", want: "{dd}-{MM}-{yyyy}"},
+		{layout: "2006-01-02", want: "{yyyy}-{MM}-{dd}"},
+		{layout: "01/02/06", want: "{MM}/{dd}/{yy}"},
+		{layout: "2 Jan 2006", want: "{d} {MMM} {yyyy}"},
+		{layout: "Monday, January 2", want: "{eeee}, {MMMM} {d}"},
+		{layout: "2006-01-02 Mon", want: "{yyyy}-{MM}-{dd} {ee}"},
+		{layout: "02.01.2006 15:04", want: "{dd}.{MM}.{yyyy} {HH}:{mm}"},
+	}
+
+	for _, tt := range tests {
+		if got := echartsTimeFormat(tt.layout); got != tt.want {
+			t.Errorf("echartsTimeFormat(%q) = %q, want %q", tt.layout, got, tt.want)
+		}
+	}
+}
+
+func TestChartDataZoom(t *testing.T) {
+	baseDate := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name       string
+		spanDays   int
+		timed      bool
+		wantSlider bool
+		wantStart  float32
+	}{
+		{name: "short history zooms without slider", spanDays: 30, timed: true},
+		{name: "long history opens on the last 90 days", spanDays: 360, timed: true, wantSlider: true, wantStart: 75},
+		{name: "entries without dates", spanDays: 360, timed: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entries := []WeightEntry{{Date: baseDate}, {Date: baseDate.AddDate(0, 0, tt.spanDays)}}
+			zoom := chartDataZoom(entries, tt.timed)
+
+			if zoom[0].Type != "inside" {
+				t.Errorf("first zoom = %q, want inside", zoom[0].Type)
+			}
+			if hasSlider := len(zoom) == 2 && zoom[1].Type == "slider"; hasSlider != tt.wantSlider {
+				t.Fatalf("zoom = %+v, want slider %v", zoom, tt.wantSlider)
+			}
+			if zoom[0].Start != tt.wantStart {
+				t.Errorf("zoom start = %v, want %v", zoom[0].Start, tt.wantStart)
+			}
+		})
+	}
+}
+
+func TestRenderHTMLChart_TimeAxis(t *testing.T) {
+	baseDate := time.Date(2024, 1, 1, 7, 30, 0, 0, time.UTC)
+	entries := []WeightEntry{
+		{ID: 1, Weight: 75.5, Date: baseDate, Unit: "kg"},
+		{ID: 2, Weight: 75.0, Date: baseDate.AddDate(0, 0, 2), Unit: "kg"},
+		{ID: 3, Weight: 73.0, Date: baseDate.AddDate(0, 0, 30), Unit: "kg"}, // After a month away
+	}
+
+	var out strings.Builder
+	options := GraphOptions{Title: "Test Chart", DateLayout: "02-01-2006", Trend: TrendOptions{Method: TrendLinear}}
+	if err := RenderHTMLChart(&out, entries, options); err != nil {
+		t.Fatal(unexpectedErrorString(err))
+	}
+
+	html := out.String()
+	for _, want := range []string{
+		`"type":"time"`,
+		`"formatter":"{dd}-{MM}-{yyyy}"`,
+		`{"value":["2024-01-01 07:30:00",75.5]}`,
+		`{"value":["2024-01-31 07:30:00",73]}`,
+		`"dataZoom":[{"type":"inside"`,
+	} {
+		if !strings.Contains(html, want) {
+			t.Errorf("chart does not contain %s", want)
+		}
+	}
+	if strings.Contains(html, `"Start"`) || strings.Contains(html, `"type":"category"`) {
+		t.Errorf("chart still uses category labels")
+	}
+}
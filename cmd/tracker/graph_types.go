@@ -0,0 +1,532 @@
+package tracker
+
+// graph_types.go - HTML charts other than the weight line
+// Related files: graph.go (chart options and the line chart), list.go (--chart flag),
+// graph_types_test.go (tests)
+// Bar and boxplot charts summarize each week or month, the histogram counts how often
+// each weight was logged, and the calendar charts show one cell per day like a GitHub
+// contribution graph: the daily change (heatmap) or the number of entries (calendar).
+
+import (
+	"fmt"
+	"io"
+	"maps"
+	"math"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/go-echarts/go-echarts/v2/charts"
+	"github.com/go-echarts/go-echarts/v2/opts"
+)
+
+// ChartType selects the kind of chart drawn from the entries
+type ChartType string
+
+const (
+	ChartLine      ChartType = "line"      // Weight over time, with trend and goal overlays
+	ChartBar       ChartType = "bar"       // Mean weight of each week or month
+	ChartBoxplot   ChartType = "boxplot"   // Spread of the weights of each week or month
+	ChartHistogram ChartType = "histogram" // Number of entries in each weight range
+	ChartHeatmap   ChartType = "heatmap"   // Calendar of the change from the previous logged day
+	ChartCalendar  ChartType = "calendar"  // Calendar of the number of entries each day
+)
+
+// ChartPeriod is the span summarized by each bar or box
+type ChartPeriod string
+
+const (
+	PeriodWeek  ChartPeriod = "week"  // Monday to Sunday
+	PeriodMonth ChartPeriod = "month" // Calendar month
+)
+
+const (
+	minHistogramBins = 5
+	maxHistogramBins = 20
+	// calendarHeight is the height in pixels of the calendar of one year, and
+	// calendarSpacing the distance between the tops of consecutive years
+	calendarHeight  = 140
+	calendarSpacing = 200
+	calendarTop     = 150
+)
+
+// calendarColors shade the calendar chart from no entries to the most entries in a day
+var calendarColors = []string{"#ebedf0", "#9be9a8", "#40c463", "#30a14e", "#216e39"}
+
+// changeColors shade the heatmap from the largest loss through no change to the largest gain
+var changeColors = []string{"#5470c6", "#f5f5f5", "#ee6666"}
+
+// parseChartType validates a chart type name
+func parseChartType(chartType string) (ChartType, error) {
+	switch ChartType(strings.ToLower(chartType)) {
+	case ChartLine:
+		return ChartLine, nil
+	case ChartBar:
+		return ChartBar, nil
+	case ChartBoxplot:
+		return ChartBoxplot, nil
+	case ChartHistogram:
+		return ChartHistogram, nil
+	case ChartHeatmap:
+		return ChartHeatmap, nil
+	case ChartCalendar:
+		return ChartCalendar, nil
+	default:
+		return "", fmt.Errorf("invalid chart type '%s': must be 'line', 'bar', 'boxplot', 'histogram', 'heatmap' or 'calendar'", chartType)
+	}
+}
+
+// parseChartPeriod validates the period of bar and boxplot charts
+func parseChartPeriod(period string) (ChartPeriod, error) {
+	switch ChartPeriod(strings.ToLower(period)) {
+	case PeriodWeek:
+		return PeriodWeek, nil
+	case PeriodMonth:
+		return PeriodMonth, nil
+	default:
+		return "", fmt.Errorf("invalid chart period '%s': must be 'week' or 'month'", period)
+	}
+}
+
+// validateChartType checks that the chart type can be drawn with the other options:
+// only line charts are drawn outside HTML, carry a trend or compare series
+func validateChartType(options GraphOptions) error {
+	if len(options.Series) > 0 {
+		if options.ChartType != "" && options.ChartType != ChartLine {
+			return fmt.Errorf("comparison charts are line charts: chart type '%s' cannot compare series", options.ChartType)
+		}
+		if options.OutputType != OutputHTML {
+			return fmt.Errorf("comparison charts are only available as HTML: add --graph-output html")
+		}
+	}
+	if options.ChartType == "" || options.ChartType == ChartLine {
+		return nil
+	}
+	if options.OutputType != OutputHTML {
+		return fmt.Errorf("chart type '%s' is only available as HTML: add --graph-output html", options.ChartType)
+	}
+	if options.Trend.Method != "" {
+		return fmt.Errorf("the trend is only drawn on line charts, not on chart type '%s'", options.ChartType)
+	}
+	return nil
+}
+
+// htmlChart is a go-echarts chart of any type
+type htmlChart interface {
+	Render(w io.Writer) error
+}
+
+// buildTypedHTMLChart builds the HTML chart of entries (in date order) selected by
+// options.ChartType, or the comparison chart of options.Series
+func buildTypedHTMLChart(entries []WeightEntry, options GraphOptions) (htmlChart, error) {
+	if len(options.Series) > 0 {
+		return buildComparisonChart(options)
+	}
+
+	switch options.ChartType {
+	case "", ChartLine:
+		return buildHTMLChart(entries, options)
+	case ChartBar:
+		return buildBarChart(entries, options)
+	case ChartBoxplot:
+		return buildBoxplotChart(entries, options)
+	case ChartHistogram:
+		return buildHistogramChart(entries, options)
+	case ChartHeatmap:
+		return buildCalendarChart(entries, options, true)
+	case ChartCalendar:
+		return buildCalendarChart(entries, options, false)
+	default:
+		return nil, fmt.Errorf("unsupported chart type: %s", options.ChartType)
+	}
+}
+
+// periodGroup is the weights logged in one week or month
+type periodGroup struct {
+	Start   time.Time
+	Weights []float64
+}
+
+// periodStart returns the first day of the week (Monday) or month containing date
+func periodStart(date time.Time, period ChartPeriod) time.Time {
+	day := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, date.Location())
+	if period == PeriodMonth {
+		return day.AddDate(0, 0, 1-day.Day())
+	}
+	return day.AddDate(0, 0, -daysSinceMonday(day))
+}
+
+// groupByPeriod splits dated entries (in date order) into weeks or months; periods
+// without entries are left out
+func groupByPeriod(entries []WeightEntry, period ChartPeriod) []periodGroup {
+	var groups []periodGroup
+	for _, entry := range entries {
+		start := periodStart(entry.Date, period)
+		if len(groups) == 0 || !groups[len(groups)-1].Start.Equal(start) {
+			groups = append(groups, periodGroup{Start: start})
+		}
+		groups[len(groups)-1].Weights = append(groups[len(groups)-1].Weights, entry.Weight)
+	}
+	return groups
+}
+
+// periodLabel names a week by the date of its Monday (in the display layout) or a
+// month by its name and year
+func periodLabel(start time.Time, period ChartPeriod, layout string) string {
+	if period == PeriodMonth {
+		return start.Format("Jan 2006")
+	}
+	if layout == "" {
+		layout = "2006-01-02"
+	}
+	return "Week of " + start.Format(layout)
+}
+
+// periodAxisName names the axis of weeks or months
+func periodAxisName(period ChartPeriod) string {
+	if period == PeriodMonth {
+		return "Month"
+	}
+	return "Week"
+}
+
+// quantile returns the q-quantile of sorted weights, interpolating between the two
+// closest weights
+func quantile(sorted []float64, q float64) float64 {
+	position := q * float64(len(sorted)-1)
+	below := int(math.Floor(position))
+	if below+1 >= len(sorted) {
+		return sorted[len(sorted)-1]
+	}
+	return sorted[below] + (position-float64(below))*(sorted[below+1]-sorted[below])
+}
+
+// boxplotValues returns the minimum, lower quartile, median, upper quartile and maximum
+// of weights, in the order drawn by a boxplot
+func boxplotValues(weights []float64) []float64 {
+	sorted := slices.Clone(weights)
+	slices.Sort(sorted)
+	values := make([]float64, 0, 5)
+	for _, q := range []float64{0, 0.25, 0.5, 0.75, 1} {
+		values = append(values, roundTrend(quantile(sorted, q)))
+	}
+	return values
+}
+
+// datedEntries returns the entries with dates, failing when chartType needs dates and
+// none have one
+func datedEntries(entries []WeightEntry, chartType ChartType) ([]WeightEntry, error) {
+	var dated []WeightEntry
+	for _, entry := range entries {
+		if !entry.Date.IsZero() && entry.Date.Year() > 1 {
+			dated = append(dated, entry)
+		}
+	}
+	if len(dated) == 0 {
+		return nil, fmt.Errorf("chart type '%s' needs entries with dates", chartType)
+	}
+	return dated, nil
+}
+
+// chartPeriod returns the period of bar and boxplot charts, weekly by default
+func chartPeriod(options GraphOptions) ChartPeriod {
+	if options.Period == "" {
+		return PeriodWeek
+	}
+	return options.Period
+}
+
+// chartPeriodSubtitle describes the entries summarized by a chart
+func chartPeriodSubtitle(entries []WeightEntry) string {
+	return fmt.Sprintf("Period: %s to %s", entries[0].Date.Format("2006-01-02"), entries[len(entries)-1].Date.Format("2006-01-02"))
+}
+
+// typedChartOptions returns the options shared by bar, boxplot and histogram charts;
+// weights are scaled to their range, while counts start from zero
+func typedChartOptions(options GraphOptions, subtitle, xName, yName string, scaled bool) []charts.GlobalOpts {
+	return []charts.GlobalOpts{
+		charts.WithInitializationOpts(chartInitialization(options)),
+		charts.WithGridOpts(opts.Grid{Left: "10%", Right: "10%", Top: "20%", Bottom: "15%"}),
+		charts.WithTitleOpts(opts.Title{
+			Title:    options.Title,
+			Subtitle: subtitle,
+			Left:     "center",
+			Top:      "5%",
+		}),
+		charts.WithTooltipOpts(opts.Tooltip{Show: &[]bool{true}[0]}),
+		charts.WithLegendOpts(opts.Legend{Show: &[]bool{true}[0], Top: "bottom"}),
+		charts.WithXAxisOpts(opts.XAxis{Name: xName, Type: "category"}),
+		charts.WithYAxisOpts(opts.YAxis{Name: yName, Scale: &[]bool{scaled}[0]}),
+	}
+}
+
+// goalMarkLine returns the series options drawing the goal as a dashed horizontal line
+// labelled with its target date in dateLayout (none when no goal is set)
+func goalMarkLine(goal *Goal, dateLayout string) []charts.SeriesOpts {
+	if goal == nil {
+		return nil
+	}
+	return []charts.SeriesOpts{
+		charts.WithMarkLineNameYAxisItemOpts(opts.MarkLineNameYAxisItem{
+			Name:  "Goal",
+			YAxis: roundTrend(goal.TargetWeight),
+		}),
+		charts.WithMarkLineStyleOpts(opts.MarkLineStyle{
+			Symbol: []string{"none", "none"},
+			Label: &opts.Label{
+				Show:      &[]bool{true}[0],
+				Formatter: fmt.Sprintf("Goal: %s", formatGoal(*goal, dateLayout)),
+			},
+			LineStyle: &opts.LineStyle{Color: "#91cc75", Type: "dashed", Width: 2},
+		}),
+	}
+}
+
+// buildBarChart builds a bar chart of the mean weight of each week or month
+func buildBarChart(entries []WeightEntry, options GraphOptions) (*charts.Bar, error) {
+	entries, err := datedEntries(entries, ChartBar)
+	if err != nil {
+		return nil, err
+	}
+	period := chartPeriod(options)
+	unit := chartUnit(entries, options)
+
+	var labels []string
+	var data []opts.BarData
+	for _, group := range groupByPeriod(entries, period) {
+		sum := 0.0
+		for _, weight := range group.Weights {
+			sum += weight
+		}
+		labels = append(labels, periodLabel(group.Start, period, options.DateLayout))
+		data = append(data, opts.BarData{Value: roundTrend(sum / float64(len(group.Weights)))})
+	}
+
+	bar := charts.NewBar()
+	bar.SetGlobalOptions(typedChartOptions(options, chartPeriodSubtitle(entries), periodAxisName(period), fmt.Sprintf("Weight (%s)", unit), true)...)
+	bar.SetXAxis(labels)
+	bar.AddSeries(fmt.Sprintf("Mean weight (%s)", unit), data,
+		append(goalMarkLine(options.Goal, options.DateLayout), charts.WithItemStyleOpts(opts.ItemStyle{Color: "#5470c6"}))...)
+	return bar, nil
+}
+
+// buildBoxplotChart builds a boxplot of the weights of each week or month
+func buildBoxplotChart(entries []WeightEntry, options GraphOptions) (*charts.BoxPlot, error) {
+	entries, err := datedEntries(entries, ChartBoxplot)
+	if err != nil {
+		return nil, err
+	}
+	period := chartPeriod(options)
+	unit := chartUnit(entries, options)
+
+	var labels []string
+	var data []opts.BoxPlotData
+	for _, group := range groupByPeriod(entries, period) {
+		labels = append(labels, periodLabel(group.Start, period, options.DateLayout))
+		data = append(data, opts.BoxPlotData{Value: boxplotValues(group.Weights)})
+	}
+
+	boxplot := charts.NewBoxPlot()
+	boxplot.SetGlobalOptions(typedChartOptions(options, chartPeriodSubtitle(entries), periodAxisName(period), fmt.Sprintf("Weight (%s)", unit), true)...)
+	boxplot.SetXAxis(labels)
+	boxplot.AddSeries(fmt.Sprintf("Weight (%s)", unit), data,
+		append(goalMarkLine(options.Goal, options.DateLayout), charts.WithItemStyleOpts(opts.ItemStyle{Color: "#e8edf9", BorderColor: "#5470c6"}))...)
+	return boxplot, nil
+}
+
+// histogramBin is a weight range and the number of entries in it
+type histogramBin struct {
+	Low   float64
+	High  float64
+	Count int
+}
+
+// histogramBins splits the range of weights into about the square root of their number
+// of equal bins (5 to 20) with round boundaries, and counts the weights in each
+func histogramBins(weights []float64) []histogramBin {
+	low, high := slices.Min(weights), slices.Max(weights)
+	target := min(max(int(math.Ceil(math.Sqrt(float64(len(weights))))), minHistogramBins), maxHistogramBins)
+
+	step := 0.1
+	if high-low > 1e-9 {
+		step = niceStep((high - low) / float64(target))
+	}
+	start := math.Floor(low/step+1e-9) * step
+	count := int(math.Floor((high-start)/step+1e-9)) + 1
+
+	bins := make([]histogramBin, count)
+	for i := range bins {
+		bins[i].Low = start + float64(i)*step
+		bins[i].High = bins[i].Low + step
+	}
+	for _, weight := range weights {
+		index := min(int(math.Floor((weight-start)/step+1e-9)), count-1)
+		bins[index].Count++
+	}
+	return bins
+}
+
+// buildHistogramChart builds a histogram of how many entries fall in each weight range
+func buildHistogramChart(entries []WeightEntry, options GraphOptions) (*charts.Bar, error) {
+	weights := make([]float64, len(entries))
+	for i, entry := range entries {
+		weights[i] = entry.Weight
+	}
+	unit := chartUnit(entries, options)
+
+	bins := histogramBins(weights)
+	decimals := stepDecimals(bins[0].High - bins[0].Low)
+	var labels []string
+	var data []opts.BarData
+	for _, bin := range bins {
+		labels = append(labels, fmt.Sprintf("%.*f-%.*f", decimals, bin.Low, decimals, bin.High))
+		data = append(data, opts.BarData{Value: bin.Count})
+	}
+
+	subtitle := fmt.Sprintf("Total entries: %d", len(entries))
+	if dated, err := datedEntries(entries, ChartHistogram); err == nil {
+		subtitle = fmt.Sprintf("%s, %s", chartPeriodSubtitle(dated), strings.ToLower(subtitle))
+	}
+
+	histogram := charts.NewBar()
+	histogram.SetGlobalOptions(typedChartOptions(options, subtitle, fmt.Sprintf("Weight (%s)", unit), "Entries", false)...)
+	histogram.SetXAxis(labels)
+	histogram.AddSeries("Entries", data,
+		charts.WithBarChartOpts(opts.BarChart{BarCategoryGap: "2%"}),
+		charts.WithItemStyleOpts(opts.ItemStyle{Color: "#5470c6"}),
+	)
+	return histogram, nil
+}
+
+// dayWeight is the mean weight and number of entries of one calendar day
+type dayWeight struct {
+	Day    time.Time
+	Weight float64
+	Count  int
+}
+
+// dailyWeights averages dated entries (in date order) by day
+func dailyWeights(entries []WeightEntry) []dayWeight {
+	var days []dayWeight
+	for _, entry := range entries {
+		day := time.Date(entry.Date.Year(), entry.Date.Month(), entry.Date.Day(), 0, 0, 0, 0, entry.Date.Location())
+		if len(days) == 0 || !days[len(days)-1].Day.Equal(day) {
+			days = append(days, dayWeight{Day: day})
+		}
+		last := &days[len(days)-1]
+		last.Weight = (last.Weight*float64(last.Count) + entry.Weight) / float64(last.Count+1)
+		last.Count++
+	}
+	return days
+}
+
+// calendarValues returns the value shown in each calendar cell, keyed by day
+// ("2006-01-02"): the change in mean weight from the previous logged day for the
+// heatmap (nothing on the first day), otherwise the number of entries, including the
+// days without any between the first and last entry
+func calendarValues(days []dayWeight, change bool) map[string]float64 {
+	values := map[string]float64{}
+	if change {
+		for i := 1; i < len(days); i++ {
+			values[days[i].Day.Format("2006-01-02")] = roundTrend(days[i].Weight - days[i-1].Weight)
+		}
+		return values
+	}
+
+	for day := days[0].Day; !day.After(days[len(days)-1].Day); day = day.AddDate(0, 0, 1) {
+		values[day.Format("2006-01-02")] = 0
+	}
+	for _, day := range days {
+		values[day.Day.Format("2006-01-02")] = float64(day.Count)
+	}
+	return values
+}
+
+// buildCalendarChart builds a calendar with one row of cells per week and a calendar
+// per year, shaded by the daily change in weight (change) or the number of entries
+func buildCalendarChart(entries []WeightEntry, options GraphOptions, change bool) (*charts.HeatMap, error) {
+	chartType, seriesName := ChartCalendar, "Entries"
+	if change {
+		chartType, seriesName = ChartHeatmap, fmt.Sprintf("Change (%s)", chartUnit(entries, options))
+	}
+	entries, err := datedEntries(entries, chartType)
+	if err != nil {
+		return nil, err
+	}
+
+	days := dailyWeights(entries)
+	values := calendarValues(days, change)
+	firstYear, lastYear := days[0].Day.Year(), days[len(days)-1].Day.Year()
+
+	visualMap := opts.VisualMap{
+		Type:       "continuous",
+		Calculable: &[]bool{true}[0],
+		Orient:     "horizontal",
+		Left:       "center",
+		Top:        "12%",
+	}
+	if change {
+		// Symmetric around no change, so losses and gains of the same size match
+		largest := 0.1
+		for _, value := range values {
+			largest = max(largest, math.Abs(value))
+		}
+		visualMap.Min, visualMap.Max = float32(-largest), float32(largest)
+		visualMap.Text = []string{"Gain", "Loss"}
+		visualMap.InRange = &opts.VisualMapInRange{Color: changeColors}
+	} else {
+		most := 1.0
+		for _, value := range values {
+			most = max(most, value)
+		}
+		visualMap.Max = float32(most)
+		visualMap.Text = []string{"More", "Less"}
+		visualMap.InRange = &opts.VisualMapInRange{Color: calendarColors}
+	}
+
+	initialization := chartInitialization(options)
+	if !options.Responsive {
+		initialization.Height = fmt.Sprintf("%dpx", max(800, calendarTop+(lastYear-firstYear+1)*calendarSpacing))
+	}
+
+	calendar := charts.NewHeatMap()
+	calendar.SetGlobalOptions(
+		charts.WithInitializationOpts(initialization),
+		charts.WithTitleOpts(opts.Title{
+			Title:    options.Title,
+			Subtitle: chartPeriodSubtitle(entries),
+			Left:     "center",
+			Top:      "2%",
+		}),
+		charts.WithTooltipOpts(opts.Tooltip{Show: &[]bool{true}[0]}),
+		charts.WithLegendOpts(opts.Legend{Show: &[]bool{false}[0]}),
+		charts.WithVisualMapOpts(visualMap),
+	)
+
+	// One calendar and series per year, most recent first like a contribution graph
+	for year := lastYear; year >= firstYear; year-- {
+		index := lastYear - year
+		calendar.AddCalendar(&opts.Calendar{
+			Range:      []string{fmt.Sprint(year)},
+			Top:        fmt.Sprint(calendarTop + index*calendarSpacing),
+			Left:       "80",
+			Right:      "40",
+			Height:     fmt.Sprint(calendarHeight),
+			DayLabel:   &opts.CalendarLabel{FirstDay: 1},
+			YearLabel:  &opts.CalendarLabel{Show: &[]bool{true}[0]},
+			MonthLabel: &opts.CalendarLabel{Show: &[]bool{true}[0]},
+		})
+
+		var data []opts.HeatMapData
+		for _, day := range slices.Sorted(maps.Keys(values)) {
+			if strings.HasPrefix(day, fmt.Sprint(year)) {
+				data = append(data, opts.HeatMapData{Value: []interface{}{day, values[day]}})
+			}
+		}
+		calendar.AddSeries(seriesName, data,
+			charts.WithCoordinateSystem("calendar"),
+			charts.WithCalendarIndex(index),
+		)
+	}
+	return calendar, nil
+}
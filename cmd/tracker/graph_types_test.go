@@ -0,0 +1,283 @@
+package tracker
+
+import (
+	"bytes"
+	"context"
+	"math"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+// graph_types_test.go - Chart type tests
+// * purpose: tests the bar, boxplot, histogram, heatmap and calendar charts and the --chart flag.
+// * tests: parsing and validation, grouping by week and month, quartiles, histogram bins,
+//   calendar values, rendered HTML of each type, list --graph --chart end to end
+// * focus: summaries match the entries, and types other than line are only drawn as HTML.
+
+func TestParseChartType(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    ChartType
+		wantErr bool
+	}{
+		{input: "line", want: ChartLine},
+		{input: "Boxplot", want: ChartBoxplot},
+		{input: "histogram", want: ChartHistogram},
+		{input: "calendar", want: ChartCalendar},
+		{input: "pie", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := parseChartType(tt.input)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("parseChartType(%q) = %q, %v, want %q (error %v)", tt.input, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+func TestValidateChartType(t *testing.T) {
+	tests := []struct {
+		name    string
+		options GraphOptions
+		wantErr string
+	}{
+		{name: "line in the terminal", options: GraphOptions{ChartType: ChartLine, OutputType: OutputTerminal, Trend: TrendOptions{Method: TrendEMA}}},
+		{name: "default type as PNG", options: GraphOptions{OutputType: OutputPNG}},
+		{name: "boxplot as HTML", options: GraphOptions{ChartType: ChartBoxplot, OutputType: OutputHTML}},
+		{name: "boxplot as SVG", options: GraphOptions{ChartType: ChartBoxplot, OutputType: OutputSVG}, wantErr: "only available as HTML"},
+		{name: "bar with trend", options: GraphOptions{ChartType: ChartBar, OutputType: OutputHTML, Trend: TrendOptions{Method: TrendSMA}}, wantErr: "only drawn on line charts"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateChartType(tt.options)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Error(unexpectedErrorString(err))
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("validateChartType() error = %v, want one containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestGroupByPeriod(t *testing.T) {
+	// Wednesday 1 January 2025 to Monday 3 February 2025
+	entries := []WeightEntry{
+		{Weight: 80, Date: time.Date(2025, 1, 1, 7, 0, 0, 0, time.UTC)},
+		{Weight: 79, Date: time.Date(2025, 1, 5, 21, 0, 0, 0, time.UTC)},
+		{Weight: 78, Date: time.Date(2025, 1, 6, 7, 0, 0, 0, time.UTC)},
+		{Weight: 77, Date: time.Date(2025, 2, 3, 7, 0, 0, 0, time.UTC)},
+	}
+
+	tests := []struct {
+		period      ChartPeriod
+		wantStarts  []string
+		wantWeights [][]float64
+	}{
+		{
+			period:      PeriodWeek,
+			wantStarts:  []string{"2024-12-30", "2025-01-06", "2025-02-03"},
+			wantWeights: [][]float64{{80, 79}, {78}, {77}},
+		},
+		{
+			period:      PeriodMonth,
+			wantStarts:  []string{"2025-01-01", "2025-02-01"},
+			wantWeights: [][]float64{{80, 79, 78}, {77}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.period), func(t *testing.T) {
+			groups := groupByPeriod(entries, tt.period)
+			if len(groups) != len(tt.wantStarts) {
+				t.Fatalf("got %d groups, want %d: %+v", len(groups), len(tt.wantStarts), groups)
+			}
+			for i, group := range groups {
+				if start := group.Start.Format("2006-01-02"); start != tt.wantStarts[i] || !slices.Equal(group.Weights, tt.wantWeights[i]) {
+					t.Errorf("group %d = %s %v, want %s %v", i, start, group.Weights, tt.wantStarts[i], tt.wantWeights[i])
+				}
+			}
+		})
+	}
+}
+
+func TestBoxplotValues(t *testing.T) {
+	tests := []struct {
+		name    string
+		weights []float64
+		want    []float64
+	}{
+		{name: "single weight", weights: []float64{75}, want: []float64{75, 75, 75, 75, 75}},
+		{name: "unsorted", weights: []float64{78, 76, 80, 77, 79}, want: []float64{76, 77, 78, 79, 80}},
+		{name: "interpolated", weights: []float64{70, 71, 72, 74}, want: []float64{70, 70.75, 71.5, 72.5, 74}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := boxplotValues(tt.weights); !slices.Equal(got, tt.want) {
+				t.Errorf("boxplotValues() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHistogramBins(t *testing.T) {
+	tests := []struct {
+		name       string
+		weights    []float64
+		wantLow    float64
+		wantStep   float64
+		wantCounts []int
+	}{
+		{
+			name:       "round bins",
+			weights:    []float64{75.2, 75.8, 76.1, 76.4, 77.9, 79.6},
+			wantLow:    75,
+			wantStep:   1,
+			wantCounts: []int{2, 2, 1, 0, 1},
+		},
+		{
+			name:       "single weight",
+			weights:    []float64{70, 70},
+			wantLow:    70,
+			wantStep:   0.1,
+			wantCounts: []int{2},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bins := histogramBins(tt.weights)
+			counts := make([]int, len(bins))
+			total := 0
+			for i, bin := range bins {
+				counts[i] = bin.Count
+				total += bin.Count
+				if math.Abs(bin.High-bin.Low-tt.wantStep) > 1e-9 {
+					t.Errorf("bin %d is %v-%v, want a width of %v", i, bin.Low, bin.High, tt.wantStep)
+				}
+			}
+			if math.Abs(bins[0].Low-tt.wantLow) > 1e-9 || !slices.Equal(counts, tt.wantCounts) {
+				t.Errorf("bins start at %v with counts %v, want %v with %v", bins[0].Low, counts, tt.wantLow, tt.wantCounts)
+			}
+			if total != len(tt.weights) {
+				t.Errorf("bins hold %d weights, want %d", total, len(tt.weights))
+			}
+		})
+	}
+}
+
+func TestCalendarValues(t *testing.T) {
+	baseDate := time.Date(2025, 1, 1, 7, 0, 0, 0, time.UTC)
+	days := dailyWeights([]WeightEntry{
+		{Weight: 80, Date: baseDate},
+		{Weight: 79, Date: baseDate.Add(12 * time.Hour)},
+		{Weight: 80, Date: baseDate.AddDate(0, 0, 3)},
+	})
+
+	changes := calendarValues(days, true)
+	wantChanges := map[string]float64{"2025-01-04": 0.5}
+	if len(changes) != len(wantChanges) || changes["2025-01-04"] != 0.5 {
+		t.Errorf("daily changes = %v, want %v", changes, wantChanges)
+	}
+
+	counts := calendarValues(days, false)
+	wantCounts := map[string]float64{"2025-01-01": 2, "2025-01-02": 0, "2025-01-03": 0, "2025-01-04": 1}
+	if len(counts) != len(wantCounts) {
+		t.Fatalf("entry counts = %v, want %v", counts, wantCounts)
+	}
+	for day, want := range wantCounts {
+		if counts[day] != want {
+			t.Errorf("entry counts = %v, want %v", counts, wantCounts)
+			break
+		}
+	}
+}
+
+func TestRenderHTMLChart_ChartTypes(t *testing.T) {
+	// Two years of entries, so the calendars have one year each
+	entries := dailyEntries(400)
+
+	tests := []struct {
+		chartType ChartType
+		period    ChartPeriod
+		want      []string
+	}{
+		{chartType: ChartBar, period: PeriodMonth, want: []string{`"type":"bar"`, "Mean weight (kg)", "Jan 2025", "Feb 2026", "Goal: 75.00 kg"}},
+		{chartType: ChartBoxplot, want: []string{`"type":"boxplot"`, "Week of 2024-12-30", "Goal: 75.00 kg"}},
+		{chartType: ChartHistogram, want: []string{`"type":"bar"`, `"name":"Entries"`, "Period: 2025-01-01 to 2026-02-04, total entries: 400"}},
+		{chartType: ChartHeatmap, want: []string{`"type":"heatmap"`, `"coordinateSystem":"calendar"`, `"range":["2026"]`, `"range":["2025"]`, "Change (kg)", "Loss"}},
+		{chartType: ChartCalendar, want: []string{`"type":"heatmap"`, `"calendarIndex":1`, `"2025-01-01",1`, "More"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.chartType), func(t *testing.T) {
+			var buf bytes.Buffer
+			options := GraphOptions{Title: "Chart", ChartType: tt.chartType, Period: tt.period, Goal: &Goal{TargetWeight: 75, Unit: "kg"}}
+			if err := RenderHTMLChart(&buf, entries, options); err != nil {
+				t.Fatal(unexpectedErrorString(err))
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(buf.String(), want) {
+					t.Errorf("chart does not contain %s", want)
+				}
+			}
+		})
+	}
+
+	// Summaries over time need dates
+	err := RenderHTMLChart(&bytes.Buffer{}, []WeightEntry{{Weight: 70}, {Weight: 71}}, GraphOptions{ChartType: ChartBoxplot})
+	if err == nil || !strings.Contains(err.Error(), "needs entries with dates") {
+		t.Errorf("boxplot without dates error = %v, want a missing dates error", err)
+	}
+}
+
+func TestListCommand_ChartType(t *testing.T) {
+	store := NewMockStore()
+	for _, entry := range dailyEntries(30) {
+		if _, err := store.AddWeight(context.Background(), entry); err != nil {
+			t.Fatal(failedTestEntryAdditionString(err))
+		}
+	}
+	app, stdout, stderr, exitCode := newTestApp(store, map[string]string{})
+	t.Chdir(t.TempDir())
+
+	tests := []struct {
+		name      string
+		args      []string
+		wantFile  string
+		wantError string
+	}{
+		{name: "boxplot defaults to HTML", args: []string{"--chart", "boxplot", "--file", "box.html"}, wantFile: "charts/box.html"},
+		{name: "monthly bars", args: []string{"--chart", "bar", "--chart-period", "month", "--graph-output", "html", "--file", "bar.html"}, wantFile: "charts/bar.html"},
+		{name: "heatmap as PNG", args: []string{"--chart", "heatmap", "--graph-output", "png"}, wantError: "only available as HTML"},
+		{name: "unknown chart type", args: []string{"--chart", "pie"}, wantError: "invalid chart type 'pie'"},
+		{name: "unknown period", args: []string{"--chart", "bar", "--chart-period", "day"}, wantError: "invalid chart period 'day'"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := runCommand(t, app, stdout, stderr, exitCode, append([]string{"list", "--graph"}, tt.args...)...)
+			if tt.wantError != "" {
+				if result.exitCode != 1 || !strings.Contains(result.stderr, tt.wantError) {
+					t.Errorf("exit code %d, stderr %q, want an error containing %q", result.exitCode, result.stderr, tt.wantError)
+				}
+				return
+			}
+			if result.exitCode != 0 || !strings.Contains(result.stdout, "Chart generated successfully: "+filepath.FromSlash(tt.wantFile)) {
+				t.Fatalf("exit code %d, stdout %q, stderr %q", result.exitCode, result.stdout, result.stderr)
+			}
+			if _, err := os.Stat(tt.wantFile); err != nil {
+				t.Errorf("chart file not written: %v", err)
+			}
+		})
+	}
+}
@@ -2,31 +2,56 @@ package tracker
 
 import (
 	"fmt"
+	"io"
 )
 
-// printWeightEntry prints a WeightEntry struct (new function for Store interface)
-func printWeightEntry(entry WeightEntry) {
-	fmt.Printf("* Weight Entry ID: %d\n", entry.ID)
-	fmt.Printf("* Date: %s\n", FormatDate(entry.Date))
-	fmt.Printf("* Weight: %.2f %s\n", entry.Weight, entry.Unit)
+// printWeightEntry prints a WeightEntry struct (new function for Store interface),
+// with its date formatted as configured in config
+func printWeightEntry(w io.Writer, entry WeightEntry, config AppConfig) {
+	fmt.Fprintf(w, "* Weight Entry ID: %d\n", entry.ID)
+	fmt.Fprintf(w, "* Date: %s\n", config.FormatDateTime(entry.Date))
+	fmt.Fprintf(w, "* Weight: %.2f %s\n", entry.Weight, entry.Unit)
 	if entry.Note != "" {
-		fmt.Printf("* Note: %s\n", entry.Note)
+		fmt.Fprintf(w, "* Note: %s\n", entry.Note)
 	}
 	if entry.UserID != "" {
-		fmt.Printf("* UserID: %s\n", entry.UserID)
+		fmt.Fprintf(w, "* UserID: %s\n", entry.UserID)
 	}
-	fmt.Println()
+	fmt.Fprintln(w)
 }
 
 // printWeightEntries prints a slice of WeightEntry structs
-func printWeightEntries(entries []WeightEntry) {
+func printWeightEntries(w io.Writer, entries []WeightEntry, config AppConfig) {
 	if len(entries) == 0 {
-		fmt.Println("No weight entries found.")
+		fmt.Fprintln(w, "No weight entries found.")
 		return
 	}
 
-	fmt.Printf("Found %d weight entries:\n\n", len(entries))
+	fmt.Fprintf(w, "Found %d weight entries:\n\n", len(entries))
 	for _, entry := range entries {
-		printWeightEntry(entry)
+		printWeightEntry(w, entry, config)
 	}
 }
+
+// writeTable prints an added or updated entry (add, update)
+func (entry WeightEntry) writeTable(w io.Writer, config AppConfig) error {
+	printWeightEntry(w, entry, config)
+	return nil
+}
+
+// entryList is the result of list
+type entryList []WeightEntry
+
+func (entries entryList) writeTable(w io.Writer, config AppConfig) error {
+	printWeightEntries(w, entries, config)
+	return nil
+}
+
+// confirm asks a yes/no question and reports whether the answer was yes
+func confirm(in io.Reader, out io.Writer, question string) bool {
+	fmt.Fprintf(out, "%s (y/N): ", question)
+	var response string
+	fmt.Fscanln(in, &response)
+
+	return response == "y" || response == "Y" || response == "yes" || response == "Yes"
+}
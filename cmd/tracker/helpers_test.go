@@ -1,37 +1,39 @@
 package tracker
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
-	"path/filepath"
+	"os"
 	"testing"
+	"time"
 
+	"github.com/BlochLior/weight-tracker/internal/db"
 	_ "github.com/mattn/go-sqlite3"
-	"github.com/pressly/goose/v3"
 )
 
+// TestMain runs the tests in UTC, so that expected dates do not depend on the time zone
+// of the machine (the default TIMEZONE is the machine's local zone)
+func TestMain(m *testing.M) {
+	time.Local = time.UTC
+	os.Unsetenv("TIMEZONE")
+	os.Exit(m.Run())
+}
+
 // setupTestDB creates an in-memory SQLite database and runs all migrations.
 // This helper can be used by all command tests to ensure consistent test database setup.
 func setupTestDB(t *testing.T) *sql.DB {
-	db, err := sql.Open("sqlite3", ":memory:")
+	database, err := sql.Open("sqlite3", ":memory:")
 	if err != nil {
 		t.Fatalf("failed to open test database: %v", err)
 	}
 
-	// Get the path to migrations directory
-	// Assuming we're in cmd/tracker/, we need to go up two levels to get to migrations/
-	migrationsDir := filepath.Join("..", "..", "migrations")
-
-	// Set goose dialect and run migrations
-	if err := goose.SetDialect("sqlite3"); err != nil {
-		t.Fatalf("failed to set goose dialect: %v", err)
-	}
-
-	if err := goose.Up(db, migrationsDir); err != nil {
+	// Run the migrations embedded in the binary, as OpenDB does on startup
+	if _, err := db.Migrate(context.Background(), database, time.Local); err != nil {
 		t.Fatalf("failed to run migrations: %v", err)
 	}
 
-	return db
+	return database
 }
 
 // failedEntryCreationString returns a formatted error message for test entry creation failures.
@@ -0,0 +1,436 @@
+package tracker
+
+// import.go - CSV import command
+// Related files: store.go (AddWeights, ValidateWeightEntry), app_config.go (date format mappings)
+// Reads weight history from CSV files with configurable column mapping.
+// Every row is validated before anything is written, and the entries are added in a
+// single transaction, so an import either succeeds completely or leaves the database
+// untouched.
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/spf13/cobra"
+)
+
+var importCmd = &cobra.Command{
+	Use:   "import <file.csv>",
+	Short: "Import weight entries from a CSV file",
+	Long: `Import weight entries from a CSV file.
+
+Columns are matched by header name (case-insensitive) or by 1-based position.
+The date column is parsed with DATE_INPUT_FORMAT unless --date-format is given,
+optionally followed by a time of day (HH:MM or HH:MM:SS). The unit and note
+columns are optional; rows without a unit use DEFAULT_UNIT.
+
+All rows are validated before any entry is written. Use --dry-run to only
+validate the file and report line-numbered errors.
+
+Examples:
+  weight-tracker import history.csv
+  weight-tracker import history.csv --dry-run
+  weight-tracker import export.csv --date-column Day --weight-column "Weight (kg)"
+  weight-tracker import data.csv --no-header --date-column 1 --weight-column 3
+  weight-tracker import data.csv --date-format yyyy-mm-dd --delimiter ";"`,
+	Args: cobra.ExactArgs(1),
+	Run:  runImport,
+}
+
+var importDateColumn string
+var importWeightColumn string
+var importUnitColumn string
+var importNoteColumn string
+var importDateFormat string
+var importDelimiter string
+var importNoHeader bool
+var importDryRun bool
+
+func init() {
+	importCmd.Flags().StringVar(&importDateColumn, "date-column", "date", "Header name or 1-based index of the date column")
+	importCmd.Flags().StringVar(&importWeightColumn, "weight-column", "weight", "Header name or 1-based index of the weight column")
+	importCmd.Flags().StringVar(&importUnitColumn, "unit-column", "unit", "Header name or 1-based index of the unit column (optional)")
+	importCmd.Flags().StringVar(&importNoteColumn, "note-column", "note", "Header name or 1-based index of the note column (optional)")
+	importCmd.Flags().StringVar(&importDateFormat, "date-format", "", "Date format of the date column (dd-mm-yyyy, yyyy-mm-dd, %d.%m.%Y, 2 Jan 2006, ...) - default from DATE_INPUT_FORMAT")
+	importCmd.Flags().StringVar(&importDelimiter, "delimiter", ",", "Field delimiter")
+	importCmd.Flags().BoolVar(&importNoHeader, "no-header", false, "The file has no header row (columns must be given as indexes)")
+	importCmd.Flags().BoolVar(&importDryRun, "dry-run", false, "Validate every row and report errors without writing")
+}
+
+// CSVColumnMapping maps weight entry fields to CSV columns.
+// Each value is either a header name or a 1-based column index.
+type CSVColumnMapping struct {
+	Date   string
+	Weight string
+	Unit   string
+	Note   string
+}
+
+// CSVImportOptions controls how a CSV file is turned into weight entries
+type CSVImportOptions struct {
+	Mapping     CSVColumnMapping
+	HasHeader   bool
+	Delimiter   rune
+	DateLayout  string         // Go layout used to parse the date column
+	DefaultUnit string         // Unit for rows without a unit value
+	UserID      string         // User the imported entries belong to
+	Location    *time.Location // Time zone of the dates in the file (UTC when nil)
+	// RequireUnit and RequireNote make a missing optional column an error
+	// (used when the column was explicitly requested)
+	RequireUnit bool
+	RequireNote bool
+}
+
+// ImportError describes a problem with a single CSV row
+type ImportError struct {
+	Line int
+	Err  error
+}
+
+func (e ImportError) Error() string {
+	return fmt.Sprintf("line %d: %v", e.Line, e.Err)
+}
+
+// ImportRowError is the rendered form of an ImportError
+type ImportRowError struct {
+	Line  int    `json:"line" yaml:"line"`
+	Error string `json:"error" yaml:"error"`
+}
+
+// ImportResult is the outcome of the import command
+type ImportResult struct {
+	Valid    int              `json:"valid" yaml:"valid"`       // Rows that passed validation
+	Invalid  int              `json:"invalid" yaml:"invalid"`   // Rows that failed validation
+	Imported int              `json:"imported" yaml:"imported"` // Entries added to the store
+	DryRun   bool             `json:"dry_run" yaml:"dry_run"`
+	Errors   []ImportRowError `json:"errors" yaml:"errors"`
+}
+
+// newImportResult summarizes the parsed entries and invalid rows of a CSV file
+func newImportResult(entries []WeightEntry, importErrors []ImportError, dryRun bool) ImportResult {
+	result := ImportResult{Valid: len(entries), Invalid: len(importErrors), DryRun: dryRun, Errors: []ImportRowError{}}
+	for _, importErr := range importErrors {
+		result.Errors = append(result.Errors, ImportRowError{Line: importErr.Line, Error: importErr.Err.Error()})
+	}
+	return result
+}
+
+// csvColumns holds the resolved 0-based column positions (-1 when absent)
+type csvColumns struct {
+	date, weight, unit, note int
+}
+
+// resolveColumn finds the position of a column by header name or 1-based index
+func resolveColumn(header []string, spec string, required bool) (int, error) {
+	if spec == "" {
+		if required {
+			return -1, fmt.Errorf("column mapping must not be empty")
+		}
+		return -1, nil
+	}
+
+	if index, err := strconv.Atoi(spec); err == nil {
+		if index < 1 {
+			return -1, fmt.Errorf("column index must be 1 or greater, got: %d", index)
+		}
+		return index - 1, nil
+	}
+
+	if header == nil {
+		return -1, fmt.Errorf("column '%s' must be a 1-based index when the file has no header", spec)
+	}
+	for i, name := range header {
+		if strings.EqualFold(strings.TrimSpace(name), spec) {
+			return i, nil
+		}
+	}
+
+	if required {
+		return -1, fmt.Errorf("column '%s' not found in header", spec)
+	}
+	return -1, nil
+}
+
+// parseImportDate parses a CSV date value in location, optionally followed by a time of day
+func parseImportDate(value string, layout string, location *time.Location) (time.Time, error) {
+	if location == nil {
+		location = time.UTC
+	}
+	for _, candidate := range []string{layout, layout + " 15:04", layout + " 15:04:05"} {
+		if t, err := time.ParseInLocation(candidate, value, location); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid date '%s'", value)
+}
+
+// field returns the trimmed value of a column, or "" when the column is absent or short
+func field(record []string, column int) string {
+	if column < 0 || column >= len(record) {
+		return ""
+	}
+	return strings.TrimSpace(record[column])
+}
+
+// parseCSVEntries reads weight entries from CSV data. Row-level problems are
+// collected as ImportErrors so that every bad line can be reported at once;
+// the returned error is reserved for problems with the file as a whole.
+func parseCSVEntries(r io.Reader, options CSVImportOptions) ([]WeightEntry, []ImportError, error) {
+	reader := csv.NewReader(r)
+	if options.Delimiter != 0 {
+		reader.Comma = options.Delimiter
+	}
+	reader.FieldsPerRecord = -1 // Tolerate ragged rows, missing fields are reported per row
+	reader.TrimLeadingSpace = true
+
+	var header []string
+	if options.HasHeader {
+		record, err := reader.Read()
+		if err == io.EOF {
+			return nil, nil, fmt.Errorf("file is empty")
+		}
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to read header: %w", err)
+		}
+		header = record
+	}
+
+	var columns csvColumns
+	var err error
+	if columns.date, err = resolveColumn(header, options.Mapping.Date, true); err != nil {
+		return nil, nil, fmt.Errorf("date column: %w", err)
+	}
+	if columns.weight, err = resolveColumn(header, options.Mapping.Weight, true); err != nil {
+		return nil, nil, fmt.Errorf("weight column: %w", err)
+	}
+	if columns.unit, err = resolveColumn(header, options.Mapping.Unit, options.RequireUnit); err != nil {
+		return nil, nil, fmt.Errorf("unit column: %w", err)
+	}
+	if columns.note, err = resolveColumn(header, options.Mapping.Note, options.RequireNote); err != nil {
+		return nil, nil, fmt.Errorf("note column: %w", err)
+	}
+
+	var entries []WeightEntry
+	var importErrors []ImportError
+
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			var parseErr *csv.ParseError
+			if errors.As(err, &parseErr) {
+				importErrors = append(importErrors, ImportError{Line: parseErr.Line, Err: parseErr.Err})
+				continue
+			}
+			return nil, nil, fmt.Errorf("failed to read CSV: %w", err)
+		}
+		// FieldPos is only valid after a successful Read
+		line, _ := reader.FieldPos(0)
+
+		// Skip blank lines
+		if len(record) == 1 && strings.TrimSpace(record[0]) == "" {
+			continue
+		}
+
+		entry, err := parseCSVRecord(record, columns, options)
+		if err != nil {
+			importErrors = append(importErrors, ImportError{Line: line, Err: err})
+			continue
+		}
+		entries = append(entries, entry)
+	}
+
+	return entries, importErrors, nil
+}
+
+// parseCSVRecord converts a single CSV record into a validated WeightEntry
+func parseCSVRecord(record []string, columns csvColumns, options CSVImportOptions) (WeightEntry, error) {
+	dateStr := field(record, columns.date)
+	if dateStr == "" {
+		return WeightEntry{}, fmt.Errorf("missing date")
+	}
+	date, err := parseImportDate(dateStr, options.DateLayout, options.Location)
+	if err != nil {
+		return WeightEntry{}, err
+	}
+
+	weightStr := field(record, columns.weight)
+	if weightStr == "" {
+		return WeightEntry{}, fmt.Errorf("missing weight")
+	}
+	weight, err := strconv.ParseFloat(weightStr, 64)
+	if err != nil {
+		return WeightEntry{}, fmt.Errorf("invalid weight '%s'", weightStr)
+	}
+
+	entry := WeightEntry{
+		Weight: weight,
+		Date:   date,
+		Unit:   field(record, columns.unit),
+		Note:   field(record, columns.note),
+		UserID: options.UserID,
+	}
+	if entry.Unit == "" {
+		entry.Unit = options.DefaultUnit
+	}
+
+	if err := ValidateWeightEntry(entry); err != nil {
+		return WeightEntry{}, err
+	}
+
+	return entry, nil
+}
+
+// resolveImportDateLayoutFromEnv returns the Go layout for a --date-format value,
+// falling back to the DATE_INPUT_FORMAT configured in getEnv
+func resolveImportDateLayoutFromEnv(format string, getEnv func(string) string) (string, error) {
+	if format == "" {
+		return GetDateFormatConfigFromEnv(getEnv).InputFormat, nil
+	}
+	return parseDateFormatName(format)
+}
+
+// runImportInternal contains the core logic and returns errors instead of terminating
+func runImportInternal(cmd *cobra.Command, args []string) error {
+	path := args[0]
+	app := appFor(cmd)
+
+	dateFormat, _ := cmd.Flags().GetString("date-format")
+	dateLayout, err := resolveImportDateLayoutFromEnv(dateFormat, app.Getenv)
+	if err != nil {
+		return err
+	}
+
+	delimiter, _ := cmd.Flags().GetString("delimiter")
+	if utf8.RuneCountInString(delimiter) != 1 {
+		return fmt.Errorf("delimiter must be a single character, got: '%s'", delimiter)
+	}
+	delimiterRune, _ := utf8.DecodeRuneInString(delimiter)
+
+	noHeader, _ := cmd.Flags().GetBool("no-header")
+	dateColumn, _ := cmd.Flags().GetString("date-column")
+	weightColumn, _ := cmd.Flags().GetString("weight-column")
+	unitColumn, _ := cmd.Flags().GetString("unit-column")
+	noteColumn, _ := cmd.Flags().GetString("note-column")
+
+	options := CSVImportOptions{
+		Mapping: CSVColumnMapping{
+			Date:   dateColumn,
+			Weight: weightColumn,
+			Unit:   unitColumn,
+			Note:   noteColumn,
+		},
+		HasHeader:   !noHeader,
+		Delimiter:   delimiterRune,
+		DateLayout:  dateLayout,
+		Location:    GetLocationFromEnv(app.Getenv),
+		DefaultUnit: GetDefaultUnitFromEnv(app.Getenv),
+		UserID:      resolveUser(cmd),
+		RequireUnit: cmd.Flags().Changed("unit-column"),
+		RequireNote: cmd.Flags().Changed("note-column"),
+	}
+
+	// Without a header the default names can't be resolved; optional columns are simply skipped
+	if noHeader {
+		if !options.RequireUnit {
+			options.Mapping.Unit = ""
+		}
+		if !options.RequireNote {
+			options.Mapping.Note = ""
+		}
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("could not open CSV file: %w", err)
+	}
+	defer file.Close()
+
+	entries, importErrors, err := parseCSVEntries(file, options)
+	if err != nil {
+		return fmt.Errorf("failed to import '%s': %w", path, err)
+	}
+
+	renderer, err := rendererFor(cmd)
+	if err != nil {
+		return err
+	}
+
+	dryRun, _ := cmd.Flags().GetBool("dry-run")
+	result := newImportResult(entries, importErrors, dryRun)
+	if dryRun || len(importErrors) > 0 || len(entries) == 0 {
+		if err := renderer.Render(result); err != nil {
+			return err
+		}
+		if len(importErrors) == 0 {
+			return nil
+		}
+		if dryRun {
+			return fmt.Errorf("%d rows failed validation", len(importErrors))
+		}
+		return fmt.Errorf("%d rows failed validation, nothing was imported (use --dry-run to check a file)", len(importErrors))
+	}
+
+	// Create store instance
+	store, err := app.NewStore()
+	if err != nil {
+		return fmt.Errorf("could not create store: %w", err)
+	}
+	defer store.Close()
+
+	result.Imported, err = importEntries(context.Background(), store, entries)
+	if err != nil {
+		return fmt.Errorf("nothing was imported: %w", err)
+	}
+	return renderer.Render(result)
+}
+
+func (result ImportResult) writeTable(w io.Writer, config AppConfig) error {
+	_ = config
+	if len(result.Errors) > 0 {
+		fmt.Fprintf(w, "Found %d invalid rows:\n", len(result.Errors))
+		for _, rowErr := range result.Errors {
+			fmt.Fprintf(w, "  line %d: %s\n", rowErr.Line, rowErr.Error)
+		}
+		fmt.Fprintln(w)
+	}
+
+	switch {
+	case result.DryRun:
+		fmt.Fprintf(w, "Dry run: %d valid rows, %d invalid rows. Nothing was imported.\n", result.Valid, result.Invalid)
+	case result.Invalid > 0:
+		// The command fails, the error explains that nothing was imported
+	case result.Valid == 0:
+		fmt.Fprintln(w, "No weight entries found.")
+	default:
+		fmt.Fprintf(w, "Successfully imported %d weight entries.\n", result.Imported)
+	}
+	return nil
+}
+
+// importEntries adds entries to the store in one transaction and returns how many were
+// added; on error none are
+func importEntries(ctx context.Context, store Store, entries []WeightEntry) (int, error) {
+	added, err := store.AddWeights(ctx, entries)
+	if err != nil {
+		return 0, err
+	}
+	return len(added), nil
+}
+
+// runImport is the cobra command wrapper that handles errors appropriately for CLI usage
+func runImport(cmd *cobra.Command, args []string) {
+	if err := runImportInternal(cmd, args); err != nil {
+		exitWithError(cmd, err)
+	}
+}
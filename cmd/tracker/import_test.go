@@ -0,0 +1,369 @@
+package tracker
+
+import (
+	"context"
+	"encoding/json"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// import_test.go - CSV import tests
+// * purpose: tests CSV parsing, column mapping and validation reporting.
+// * tests: parsing (no store), MockStore (mock) and the import command output
+// * focus: column mapping, date formats and line-numbered errors.
+
+func TestParseCSVEntries(t *testing.T) {
+	defaultOptions := CSVImportOptions{
+		Mapping:     CSVColumnMapping{Date: "date", Weight: "weight", Unit: "unit", Note: "note"},
+		HasHeader:   true,
+		DateLayout:  "02-01-2006",
+		DefaultUnit: "kg",
+	}
+
+	tests := []struct {
+		name          string
+		csv           string
+		options       CSVImportOptions
+		expected      []WeightEntry
+		expectedLines []int // lines reported as invalid
+		wantErr       bool
+	}{
+		{
+			name: "header with all columns",
+			csv: "date,weight,unit,note\n" +
+				"01-01-2025,75.5,kg,new year\n" +
+				"02-01-2025,165.2,lbs,\n",
+			options: defaultOptions,
+			expected: []WeightEntry{
+				{Weight: 75.5, Date: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC), Unit: "kg", Note: "new year"},
+				{Weight: 165.2, Date: time.Date(2025, 1, 2, 0, 0, 0, 0, time.UTC), Unit: "lbs"},
+			},
+		},
+		{
+			name: "optional columns missing use default unit",
+			csv: "Weight,Date\n" +
+				"80.1,15-06-2025\n",
+			options: defaultOptions,
+			expected: []WeightEntry{
+				{Weight: 80.1, Date: time.Date(2025, 6, 15, 0, 0, 0, 0, time.UTC), Unit: "kg"},
+			},
+		},
+		{
+			name: "custom header names and time of day",
+			csv: "Day;Weight (kg);Comment\n" +
+				"2025-03-10 07:30;70.2;morning\n",
+			options: CSVImportOptions{
+				Mapping:     CSVColumnMapping{Date: "Day", Weight: "Weight (kg)", Note: "Comment"},
+				HasHeader:   true,
+				Delimiter:   ';',
+				DateLayout:  "2006-01-02",
+				DefaultUnit: "kg",
+			},
+			expected: []WeightEntry{
+				{Weight: 70.2, Date: time.Date(2025, 3, 10, 7, 30, 0, 0, time.UTC), Unit: "kg", Note: "morning"},
+			},
+		},
+		{
+			name: "no header with column indexes",
+			csv: "01/31/2025,x,81.0\n" +
+				"02/01/2025,y,80.4\n",
+			options: CSVImportOptions{
+				Mapping:     CSVColumnMapping{Date: "1", Weight: "3"},
+				DateLayout:  "01/02/2006",
+				DefaultUnit: "lbs",
+			},
+			expected: []WeightEntry{
+				{Weight: 81.0, Date: time.Date(2025, 1, 31, 0, 0, 0, 0, time.UTC), Unit: "lbs"},
+				{Weight: 80.4, Date: time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC), Unit: "lbs"},
+			},
+		},
+		{
+			name: "invalid rows are reported with line numbers",
+			csv: "date,weight,unit\n" +
+				"01-01-2025,75.5,kg\n" +
+				"2025-01-02,75.1,kg\n" + // wrong date format
+				"03-01-2025,heavy,kg\n" + // not a number
+				"\n" +
+				"04-01-2025,-3,kg\n" + // fails validation
+				"05-01-2025,74.9,stone\n" + // invalid unit
+				"06-01-2025\n", // missing weight
+			options: defaultOptions,
+			expected: []WeightEntry{
+				{Weight: 75.5, Date: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC), Unit: "kg"},
+			},
+			expectedLines: []int{3, 4, 6, 7, 8},
+		},
+		{
+			name: "malformed quote in the first field",
+			csv: "date,weight\n" +
+				"1\"5-09-2024,76\n" +
+				"16-09-2024,75.8\n",
+			options: defaultOptions,
+			expected: []WeightEntry{
+				{Weight: 75.8, Date: time.Date(2024, 9, 16, 0, 0, 0, 0, time.UTC), Unit: "kg"},
+			},
+			expectedLines: []int{2},
+		},
+		{
+			name:    "required column missing from header",
+			csv:     "day,weight\n01-01-2025,75.5\n",
+			options: defaultOptions,
+			wantErr: true,
+		},
+		{
+			name: "header name used without a header",
+			csv:  "01-01-2025,75.5\n",
+			options: CSVImportOptions{
+				Mapping:    CSVColumnMapping{Date: "date", Weight: "2"},
+				DateLayout: "02-01-2006",
+			},
+			wantErr: true,
+		},
+		{
+			name: "explicitly requested optional column missing",
+			csv:  "date,weight\n01-01-2025,75.5\n",
+			options: CSVImportOptions{
+				Mapping:     CSVColumnMapping{Date: "date", Weight: "weight", Unit: "units"},
+				HasHeader:   true,
+				DateLayout:  "02-01-2006",
+				RequireUnit: true,
+			},
+			wantErr: true,
+		},
+		{
+			name:    "empty file",
+			csv:     "",
+			options: defaultOptions,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entries, importErrors, err := parseCSVEntries(strings.NewReader(tt.csv), tt.options)
+
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("parseCSVEntries() expected error but got none")
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatal(unexpectedErrorString(err))
+			}
+
+			if len(entries) != len(tt.expected) {
+				t.Fatalf("parseCSVEntries() got %d entries, want %d", len(entries), len(tt.expected))
+			}
+			for i, expected := range tt.expected {
+				got := entries[i]
+				if got.Weight != expected.Weight || !got.Date.Equal(expected.Date) ||
+					got.Unit != expected.Unit || got.Note != expected.Note {
+					t.Errorf("entry %d = %+v, want %+v", i, got, expected)
+				}
+			}
+
+			if len(importErrors) != len(tt.expectedLines) {
+				t.Fatalf("parseCSVEntries() got %d errors (%v), want %d", len(importErrors), importErrors, len(tt.expectedLines))
+			}
+			for i, line := range tt.expectedLines {
+				if importErrors[i].Line != line {
+					t.Errorf("error %d reported on line %d, want %d (%v)", i, importErrors[i].Line, line, importErrors[i])
+				}
+			}
+		})
+	}
+}
+
+func TestResolveImportDateLayout(t *testing.T) {
+	env := map[string]string{"DATE_INPUT_FORMAT": "yyyy/mm/dd"}
+	getEnv := func(key string) string { return env[key] }
+
+	layout, err := resolveImportDateLayoutFromEnv("yyyy-mm-dd", getEnv)
+	if err != nil {
+		t.Fatal(unexpectedErrorString(err))
+	}
+	if layout != "2006-01-02" {
+		t.Errorf("resolveImportDateLayoutFromEnv(yyyy-mm-dd) = %s, want 2006-01-02", layout)
+	}
+
+	layout, err = resolveImportDateLayoutFromEnv("", getEnv)
+	if err != nil {
+		t.Fatal(unexpectedErrorString(err))
+	}
+	if layout != "2006/01/02" {
+		t.Errorf("resolveImportDateLayoutFromEnv(\"\") = %s, want the configured input format 2006/01/02", layout)
+	}
+
+	if _, err := resolveImportDateLayoutFromEnv("dd.mm.yy", getEnv); err == nil {
+		t.Errorf("resolveImportDateLayoutFromEnv() expected error for unsupported format but got none")
+	}
+}
+
+func TestImportEntries_MockStore(t *testing.T) {
+	store := NewMockStore()
+	ctx := context.Background()
+
+	csvData := "date,weight,note\n" +
+		"01-01-2025,75.5,first\n" +
+		"02-01-2025,75.0,second\n"
+
+	entries, importErrors, err := parseCSVEntries(strings.NewReader(csvData), CSVImportOptions{
+		Mapping:     CSVColumnMapping{Date: "date", Weight: "weight", Note: "note"},
+		HasHeader:   true,
+		DateLayout:  "02-01-2006",
+		DefaultUnit: "kg",
+		UserID:      "alice",
+	})
+	if err != nil {
+		t.Fatal(unexpectedErrorString(err))
+	}
+	if len(importErrors) != 0 {
+		t.Fatalf("unexpected import errors: %v", importErrors)
+	}
+
+	imported, err := importEntries(ctx, store, entries)
+	if err != nil {
+		t.Fatal(unexpectedErrorString(err))
+	}
+	if imported != 2 {
+		t.Errorf("importEntries() imported %d entries, want 2", imported)
+	}
+
+	stored, err := store.ListWeights(ctx, ListOptions{UserID: "alice"})
+	if err != nil {
+		t.Fatal(unexpectedErrorString(err))
+	}
+	if len(stored) != 2 {
+		t.Errorf("store has %d entries for alice, want 2", len(stored))
+	}
+}
+
+func TestImportEntries_DBStoreRollsBack(t *testing.T) {
+	testDB := setupTestDB(t)
+	defer testDB.Close()
+	store := NewDBStoreWithDB(testDB, time.UTC)
+	ctx := context.Background()
+
+	// SQLite stores NaN as NULL, so the second entry violates the NOT NULL weight column
+	baseDate := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	entries := []WeightEntry{
+		{Weight: 75.5, Date: baseDate, Unit: "kg"},
+		{Weight: math.NaN(), Date: baseDate.AddDate(0, 0, 1), Unit: "kg"},
+	}
+
+	imported, err := importEntries(ctx, store, entries)
+	if err == nil {
+		t.Fatal("importEntries() expected error for an entry the database rejects, got nil")
+	}
+	if imported != 0 {
+		t.Errorf("importEntries() imported %d entries, want 0", imported)
+	}
+
+	stored, err := store.ListWeights(ctx, ListOptions{})
+	if err != nil {
+		t.Fatal(unexpectedErrorString(err))
+	}
+	if len(stored) != 0 {
+		t.Errorf("store has %d entries after a failed import, want none", len(stored))
+	}
+}
+
+func TestImportCommand_Output(t *testing.T) {
+	dir := t.TempDir()
+	validCSV := filepath.Join(dir, "valid.csv")
+	invalidCSV := filepath.Join(dir, "invalid.csv")
+	malformedCSV := filepath.Join(dir, "malformed.csv")
+	if err := os.WriteFile(validCSV, []byte("date,weight\n2025-01-01,75.5\n2025-01-02,75.0\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(invalidCSV, []byte("date,weight\n2025-01-01,75.5\n2025-01-02,heavy\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(malformedCSV, []byte("date,weight\n1\"5-09-2024,76\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name         string
+		args         []string
+		wantExitCode int
+		wantStdout   []string
+		wantResult   *ImportResult // decoded from JSON output
+		wantStored   int
+	}{
+		{
+			name:       "table",
+			args:       []string{validCSV},
+			wantStdout: []string{"Successfully imported 2 weight entries."},
+			wantStored: 2,
+		},
+		{
+			name:         "table with invalid rows",
+			args:         []string{invalidCSV},
+			wantExitCode: 1,
+			wantStdout:   []string{"Found 1 invalid rows:", "line 3: "},
+		},
+		{
+			name:       "json",
+			args:       []string{validCSV, "--output", "json"},
+			wantResult: &ImportResult{Valid: 2, Imported: 2},
+			wantStored: 2,
+		},
+		{
+			name:         "json dry run with invalid rows",
+			args:         []string{invalidCSV, "--dry-run", "-o", "json"},
+			wantExitCode: 1,
+			wantResult:   &ImportResult{Valid: 1, Invalid: 1, DryRun: true},
+		},
+		{
+			name:         "dry run with a malformed quote",
+			args:         []string{malformedCSV, "--dry-run"},
+			wantExitCode: 1,
+			wantStdout:   []string{"Found 1 invalid rows:", "line 2: "},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := NewMockStore()
+			app, stdout, stderr, exitCode := newTestApp(store, map[string]string{"DATE_INPUT_FORMAT": "yyyy-mm-dd"})
+			result := runCommand(t, app, stdout, stderr, exitCode, append([]string{"import"}, tt.args...)...)
+
+			if result.exitCode != tt.wantExitCode {
+				t.Fatalf("exit code %d, want %d (stderr %q)", result.exitCode, tt.wantExitCode, result.stderr)
+			}
+			for _, want := range tt.wantStdout {
+				if !strings.Contains(result.stdout, want) {
+					t.Errorf("stdout %q does not contain %q", result.stdout, want)
+				}
+			}
+			if tt.wantResult != nil {
+				var decoded ImportResult
+				if err := json.Unmarshal([]byte(result.stdout), &decoded); err != nil {
+					t.Fatalf("output is not valid JSON: %v\n%s", err, result.stdout)
+				}
+				if decoded.Valid != tt.wantResult.Valid || decoded.Invalid != tt.wantResult.Invalid ||
+					decoded.Imported != tt.wantResult.Imported || decoded.DryRun != tt.wantResult.DryRun {
+					t.Errorf("import result = %+v, want %+v", decoded, *tt.wantResult)
+				}
+				if len(decoded.Errors) != decoded.Invalid {
+					t.Errorf("import result has %d row errors, want %d", len(decoded.Errors), decoded.Invalid)
+				}
+			}
+
+			stored, err := store.ListWeights(context.Background(), ListOptions{})
+			if err != nil {
+				t.Fatal(unexpectedErrorString(err))
+			}
+			if len(stored) != tt.wantStored {
+				t.Errorf("store has %d entries, want %d", len(stored), tt.wantStored)
+			}
+		})
+	}
+}
@@ -7,7 +7,7 @@ package tracker
 import (
 	"context"
 	"fmt"
-	"os"
+	"strings"
 	"time"
 
 	"github.com/spf13/cobra"
@@ -18,149 +18,221 @@ var listCmd = &cobra.Command{
 	Short: "List weight entries",
 	Long: `List weight entries from the database with optional filtering and sorting.
 
+--graph draws the entries as a chart instead, of the type selected by --graph-output.
+Chart types passed to --output (terminal, html, png) are still accepted with --graph
+for older scripts, but that use is deprecated: --output selects the result format.
+
 Examples:
   weight-tracker list                              # List all entries (default: date desc)
   weight-tracker list --from 01-01-2025           # List entries from date
   weight-tracker list --to 31-12-2025             # List entries until date
   weight-tracker list --from 01-01-2025 --to 31-12-2025  # List entries in date range
+  weight-tracker list --from -7d                   # List entries of the last 7 days
+  weight-tracker list --from "last month" --to "last month"  # List entries of last month
+  weight-tracker list --from 2025-W10 --to 2025-W10   # List entries of ISO week 10
   weight-tracker list --limit 10                  # List last 10 entries
   weight-tracker list --sort date                 # Sort by date (ascending)
   weight-tracker list --sort date --desc          # Sort by date (descending)
   weight-tracker list --sort weight --desc        # Sort by weight (descending)
+  weight-tracker list --sort -weight,date         # Heaviest first, earliest first among equal weights
+  weight-tracker list --sort unit,-date           # Group by unit, newest first within each unit
   weight-tracker list --unit kg                   # Filter by unit
-  weight-tracker list --graph                     # Display ASCII chart in terminal
-  weight-tracker list --graph --output html       # Generate HTML chart in charts/ directory
-  weight-tracker list --graph --output html --file my-chart.html # Generate HTML chart with custom filename
+  weight-tracker list --note holiday              # Entries whose note contains "holiday"
+  weight-tracker list --limit 20 --page 2         # Second page of 20 entries
+  weight-tracker list --limit 20 --after 118      # Next 20 entries after entry 118
+  weight-tracker list --display-unit lbs          # Show all weights converted to lbs
+  weight-tracker list --graph                     # Display a chart sized to the terminal
+  weight-tracker list --output json               # Print entries as JSON for scripts
+  weight-tracker list --graph --graph-output html # Generate HTML chart in charts/ directory
+  weight-tracker list --graph --graph-output html --file my-chart.html # Generate HTML chart with custom filename
+  weight-tracker list --graph --graph-output png --width 1200 --height 600 # Generate a PNG image of the chart
+  weight-tracker list --graph --trend             # Overlay the EMA trend line
+  weight-tracker list --graph --chart boxplot --chart-period month # Monthly boxplots of the weights (HTML)
+  weight-tracker list --graph --chart calendar    # Calendar of the days entries were logged (HTML)
+  weight-tracker list --graph --compare-users alice,bob --from "this month" # One line per user (HTML)
+  weight-tracker list --graph --from 01-01-2026 --to 31-01-2026 --compare-years 1 # This January over last January (HTML)
+  weight-tracker list --graph --graph-output html --trend --trend-method sma --trend-window 14
 `,
 	Run: runList,
 }
 
 func init() {
-	listCmd.Flags().StringVarP(&fromDate, "from", "f", "", "Start date for filtering (format configurable via DATE_INPUT_FORMAT)")
-	listCmd.Flags().StringVarP(&toDate, "to", "t", "", "End date for filtering (format configurable via DATE_INPUT_FORMAT)")
+	listCmd.Flags().StringVarP(&fromDate, "from", "f", "", "Start date for filtering (DATE_INPUT_FORMAT or relative: today, -7d, last monday, this month, 2025-W10)")
+	listCmd.Flags().StringVarP(&toDate, "to", "t", "", "End date for filtering (DATE_INPUT_FORMAT or relative: today, -7d, last monday, this month, 2025-W10)")
 	listCmd.Flags().IntVarP(&limit, "limit", "l", 0, "Maximum number of entries to list (0 = no limit)")
-	listCmd.Flags().StringVarP(&sortField, "sort", "s", "date", "Field to sort by (date, weight)")
-	listCmd.Flags().BoolVarP(&desc, "desc", "d", true, "Sort in descending order")
+	listCmd.Flags().StringVarP(&sortField, "sort", "s", "date", sortFlagUsage)
+	listCmd.Flags().BoolVarP(&desc, "desc", "d", true, "Sort in descending order (with a single sort field)")
 	listCmd.Flags().StringVarP(&unitFilter, "unit", "u", "", "Filter by unit (kg, lbs)")
+	listCmd.Flags().StringVarP(&noteFilter, "note", "n", "", "Filter by note (case-insensitive substring)")
+	listCmd.Flags().IntVarP(&page, "page", "p", 0, fmt.Sprintf("Page of entries to list, counting from 1 (page size: --limit, default %d)", DefaultPageSize))
+	listCmd.Flags().Int64VarP(&afterID, "after", "a", 0, "List the entries following the entry with this ID in the sort order (page size: --limit)")
+	listCmd.Flags().StringVarP(&displayUnit, "display-unit", "", "", "Unit to convert weights to for display (kg, lbs) - default configurable via DEFAULT_UNIT")
 	listCmd.Flags().BoolVarP(&showGraph, "graph", "g", false, "Display weight chart")
-	listCmd.Flags().StringVarP(&graphOutput, "output", "o", "terminal", "Graph output type (terminal, html, png)")
+	listCmd.Flags().StringVarP(&graphOutput, "graph-output", "", "terminal", "Graph output type (terminal, html, png, svg), formerly --output (deprecated with --graph)")
 	listCmd.Flags().StringVarP(&graphFile, "file", "", "", "Output filename for graph (saved in charts/ directory)")
+	listCmd.Flags().IntVarP(&graphWidth, "width", "", defaultChartWidth, "Width of PNG and SVG charts in pixels")
+	listCmd.Flags().IntVarP(&graphHeight, "height", "", defaultChartHeight, "Height of PNG and SVG charts in pixels")
+	listCmd.Flags().StringVarP(&graphChart, "chart", "", string(ChartLine), "Chart type (line, bar, boxplot, histogram, heatmap, calendar); types other than line are HTML only")
+	listCmd.Flags().StringVarP(&graphPeriod, "chart-period", "", string(PeriodWeek), "Period of each bar or box of bar and boxplot charts (week, month)")
+	listCmd.Flags().StringVarP(&compareUsers, "compare-users", "", "", "Users to draw on one chart, separated by commas (with --graph, HTML only)")
+	listCmd.Flags().IntVarP(&compareYears, "compare-years", "", 0, "Number of previous years whose --from/--to period is overlaid on the chart, aligned by day (with --graph, HTML only)")
+	addTrendFlags(listCmd, "Overlay the smoothed trend on the chart (with --graph)")
 }
 
+// sortFlagUsage is the help text of the --sort flag of list and export
+const sortFlagUsage = "Fields to sort by, separated by commas, '-' for descending (date, weight, id, unit, note, user), e.g. date,-weight"
+
+// DefaultPageSize is the number of entries per page when --page or --after is given without --limit
+const DefaultPageSize = 20
+
 var fromDate string
 var toDate string
 var limit int
 var sortField string
 var desc bool
 var unitFilter string
+var noteFilter string
+var page int
+var afterID int64
+var displayUnit string
 var showGraph bool
 var graphOutput string
 var graphFile string
+var graphWidth int
+var graphHeight int
+var graphChart string
+var graphPeriod string
+var compareUsers string
+var compareYears int
 
 // runListInternal contains the core logic and returns errors instead of terminating
 func runListInternal(cmd *cobra.Command, args []string) error {
 	// Note: args are not used for list command as all options are handled via flags
 	_ = args
+	app := appFor(cmd)
+
 	// Create store instance
-	store, err := NewDBStore()
+	store, err := app.NewStore()
 	if err != nil {
 		return fmt.Errorf("could not create store: %w", err)
 	}
 	defer store.Close()
 
-	// --- 1. Handle Optional Flags ---
-
-	// Get limit (0 means no limit)
-	limitValue, _ := cmd.Flags().GetInt("limit")
-
-	// Parse date filters
-	var fromDate, toDate *time.Time
-	if cmd.Flags().Changed("from") {
-		dateStr, _ := cmd.Flags().GetString("from")
-		if dateStr != "" {
-			parsedDate, err := ParseDate(dateStr)
-			if err != nil {
-				return fmt.Errorf("invalid from date format '%s': use %s format", dateStr, GetInputFormatDescription())
-			}
-			fromDate = &parsedDate
-		}
-	}
-
-	if cmd.Flags().Changed("to") {
-		dateStr, _ := cmd.Flags().GetString("to")
-		if dateStr != "" {
-			parsedDate, err := ParseDate(dateStr)
-			if err != nil {
-				return fmt.Errorf("invalid to date format '%s': use %s format", dateStr, GetInputFormatDescription())
-			}
-			toDate = &parsedDate
-		}
+	// --- 1-4. Build ListOptions from the filter and sort flags ---
+	options, err := buildListOptions(cmd)
+	if err != nil {
+		return err
 	}
 
-	// --- 2. Handle Sorting ---
-	sortColumn, _ := cmd.Flags().GetString("sort")
-	sortDesc, _ := cmd.Flags().GetBool("desc")
-
-	// Validate sort column
-	if sortColumn != "date" && sortColumn != "weight" {
-		return fmt.Errorf("invalid sort column '%s': must be 'date' or 'weight'", sortColumn)
+	// Resolve the unit all weights are displayed in
+	displayUnitValue, _ := cmd.Flags().GetString("display-unit")
+	targetUnit, err := resolveDisplayUnitFromEnv(displayUnitValue, app.Getenv)
+	if err != nil {
+		return err
 	}
 
-	// --- 3. Handle Unit Filter ---
-	unitFilter, _ := cmd.Flags().GetString("unit")
-
-	// --- 4. Build ListOptions ---
-	options := ListOptions{
-		FromDate: fromDate,
-		ToDate:   toDate,
-		Limit:    limitValue,
-		SortBy:   sortColumn,
-		SortDesc: sortDesc,
-		Unit:     unitFilter,
-	}
+	// Comparison charts list each user or period with the filters as given
+	seriesOptions := options
 
 	// --- 5. Call the store method ---
+	// When paginating, fetch one entry more than the page holds to learn whether
+	// another page follows
+	paginated := options.Offset > 0 || options.AfterID != 0 || cmd.Flags().Changed("page")
+	if paginated {
+		options.Limit++
+	}
 	entries, err := store.ListWeights(context.Background(), options)
 	if err != nil {
 		return fmt.Errorf("failed to list weights: %w", err)
 	}
+	hasMore := false
+	if paginated && len(entries) == options.Limit {
+		entries = entries[:len(entries)-1]
+		hasMore = true
+	}
+
+	// Normalize mixed kg/lbs histories to a single display unit
+	entries, err = NormalizeEntries(entries, targetUnit)
+	if err != nil {
+		return fmt.Errorf("failed to convert weights to %s: %w", targetUnit, err)
+	}
 
 	// --- 6. Handle output (table or graph) ---
 	showGraph, _ := cmd.Flags().GetBool("graph")
 	if showGraph {
 		// Generate graph
-		graphOutput, _ := cmd.Flags().GetString("output")
 		graphFile, _ := cmd.Flags().GetString("file")
 
 		// Determine output type
-		var outputType GraphOutputType
-		switch graphOutput {
-		case "html":
+		outputType, err := resolveGraphOutput(cmd)
+		if err != nil {
+			return err
+		}
+
+		chartValue, _ := cmd.Flags().GetString("chart")
+		chartType, err := parseChartType(chartValue)
+		if err != nil {
+			return err
+		}
+		periodValue, _ := cmd.Flags().GetString("chart-period")
+		period, err := parseChartPeriod(periodValue)
+		if err != nil {
+			return err
+		}
+		series, err := loadComparisonSeries(cmd, store, seriesOptions)
+		if err != nil {
+			return err
+		}
+		// Chart types other than line and comparisons are only drawn as HTML, so they
+		// default to it
+		if (chartType != ChartLine || series != nil) && !graphOutputChanged(cmd) {
 			outputType = OutputHTML
-		case "png":
-			outputType = OutputPNG
-		default:
-			outputType = OutputTerminal
 		}
 
-		// Set default filename if not provided (will be handled by ensureOutputDir)
-		if graphFile == "" && outputType != OutputTerminal {
-			graphFile = "" // Let ensureOutputDir generate a timestamped filename
+		trendOptions, err := trendOptionsFromFlags(cmd)
+		if err != nil {
+			return err
+		}
+
+		// Draw the selected user's goal, if one is set (users compared on one chart
+		// each have their own)
+		var goal *Goal
+		if !cmd.Flags().Changed("compare-users") {
+			goal, err = loadGoal(context.Background(), store, options.UserID, targetUnit)
+			if err != nil {
+				return fmt.Errorf("failed to load goal: %w", err)
+			}
 		}
 
 		// Generate chart title
 		title := "Weight Tracking Chart"
-		if len(entries) > 0 {
+		if series != nil {
+			title = fmt.Sprintf("Weight Comparison (%s)", seriesNames(series))
+		} else if len(entries) > 0 {
 			title = fmt.Sprintf("Weight Tracking Chart (%d entries)", len(entries))
 		}
 
+		width, _ := cmd.Flags().GetInt("width")
+		height, _ := cmd.Flags().GetInt("height")
+		columns, rows := terminalSize(cmd.OutOrStdout(), app.Getenv)
+
 		// Generate the chart
 		graphOptions := GraphOptions{
-			OutputType: outputType,
-			OutputFile: graphFile,
-			Width:      800,
-			Height:     600,
-			Title:      title,
+			OutputType:  outputType,
+			OutputFile:  graphFile,
+			ChartType:   chartType,
+			Period:      period,
+			Width:       width,
+			Height:      height,
+			Columns:     columns,
+			Rows:        rows,
+			Out:         cmd.OutOrStdout(),
+			DateLayout:  GetAppConfigFromEnv(app.Getenv).DateFormat.DisplayFormat,
+			Title:       title,
+			DisplayUnit: targetUnit,
+			Trend:       trendOptions,
+			Goal:        goal,
+			Series:      series,
 		}
 
 		outputPath, err := GenerateWeightChart(entries, graphOptions)
@@ -170,23 +242,261 @@ func runListInternal(cmd *cobra.Command, args []string) error {
 
 		// Print success message for file output
 		if outputType != OutputTerminal {
-			fmt.Printf("Chart generated successfully: %s\n", outputPath)
+			fmt.Fprintf(cmd.OutOrStdout(), "Chart generated successfully: %s\n", outputPath)
 			if outputType == OutputHTML {
-				fmt.Printf("Open %s in your browser to view the chart.\n", outputPath)
+				fmt.Fprintf(cmd.OutOrStdout(), "Open %s in your browser to view the chart.\n", outputPath)
+			}
+		}
+		return nil
+	}
+
+	// Print entries in the selected output format
+	renderer, err := rendererFor(cmd)
+	if err != nil {
+		return err
+	}
+	if err := renderer.Render(entryList(entries)); err != nil {
+		return err
+	}
+
+	if hasMore && !renderer.Structured() {
+		printNextPageHint(cmd, entries)
+	}
+	return nil
+}
+
+// printNextPageHint tells how to list the page following entries
+func printNextPageHint(cmd *cobra.Command, entries []WeightEntry) {
+	if pageValue, _ := cmd.Flags().GetInt("page"); cmd.Flags().Changed("page") {
+		fmt.Fprintf(cmd.OutOrStdout(), "More entries: use --page %d or --after %d\n", pageValue+1, entries[len(entries)-1].ID)
+		return
+	}
+	fmt.Fprintf(cmd.OutOrStdout(), "More entries: use --after %d\n", entries[len(entries)-1].ID)
+}
+
+// resolveGraphOutput determines the chart output from --graph-output. Chart outputs
+// passed to --output (terminal, html, png) are still accepted for compatibility
+// with scripts written before --output selected the result format, with a
+// deprecation notice on stderr like cobra prints for deprecated flags.
+func resolveGraphOutput(cmd *cobra.Command) (GraphOutputType, error) {
+	graphOutput := "terminal"
+	if cmd.Flags().Changed("graph-output") {
+		graphOutput, _ = cmd.Flags().GetString("graph-output")
+	} else if flag := cmd.Flags().Lookup("output"); flag != nil && flag.Changed {
+		switch value := flag.Value.String(); value {
+		case "terminal", "html", "png":
+			fmt.Fprintf(cmd.ErrOrStderr(), "Flag --output %s has been deprecated, use --graph-output %s\n", value, value)
+			graphOutput = value
+		case string(OutputFormatTable):
+		default:
+			return "", fmt.Errorf("--output %s cannot be combined with --graph: use --graph-output to choose the chart type", value)
+		}
+	}
+
+	switch graphOutput {
+	case "html":
+		return OutputHTML, nil
+	case "png":
+		return OutputPNG, nil
+	case "svg":
+		return OutputSVG, nil
+	case "terminal":
+		return OutputTerminal, nil
+	default:
+		return "", fmt.Errorf("invalid graph output '%s': must be 'terminal', 'html', 'png' or 'svg'", graphOutput)
+	}
+}
+
+// loadComparisonSeries lists the series of the comparison chart selected by
+// --compare-users or --compare-years, filtered by options; nil when neither is given.
+// Years are compared over the --from/--to period, which ends today without --to.
+func loadComparisonSeries(cmd *cobra.Command, store Store, options ListOptions) ([]ChartSeries, error) {
+	usersValue, _ := cmd.Flags().GetString("compare-users")
+	years, _ := cmd.Flags().GetInt("compare-years")
+
+	switch {
+	case usersValue != "" && years != 0:
+		return nil, fmt.Errorf("--compare-users and --compare-years cannot be combined")
+	case usersValue != "":
+		var users []string
+		for _, user := range strings.Split(usersValue, ",") {
+			if user = strings.TrimSpace(user); user != "" {
+				users = append(users, user)
 			}
 		}
-	} else {
-		// Print table format
-		printWeightEntries(entries)
+		if len(users) < 2 {
+			return nil, fmt.Errorf("--compare-users needs at least two users, separated by commas")
+		}
+		return compareUserSeries(context.Background(), store, options, users)
+	case years < 0:
+		return nil, fmt.Errorf("invalid --compare-years %d: must be a positive number of years", years)
+	case years > 0:
+		if options.FromDate == nil {
+			return nil, fmt.Errorf("--compare-years needs --from to choose the period to compare")
+		}
+		if options.ToDate == nil {
+			app := appFor(cmd)
+			today := EndOfDay(app.Now().In(GetLocationFromEnv(app.Getenv)))
+			options.ToDate = &today
+		}
+		return compareYearSeries(context.Background(), store, options, years)
+	default:
+		return nil, nil
+	}
+}
+
+// graphOutputChanged reports whether the chart output was chosen with --graph-output
+// or the older --output
+func graphOutputChanged(cmd *cobra.Command) bool {
+	if cmd.Flags().Changed("graph-output") {
+		return true
+	}
+	flag := cmd.Flags().Lookup("output")
+	return flag != nil && flag.Changed && flag.Value.String() != string(OutputFormatTable)
+}
+
+// buildListOptions builds ListOptions from the shared filter and sort flags
+// (--from, --to, --limit, --sort, --desc, --unit, --note), the pagination flags
+// (--page, --after) of the commands that have them and the selected user.
+// It is used by every command that accepts the same filters as list.
+func buildListOptions(cmd *cobra.Command) (ListOptions, error) {
+	// --- 1. Handle Optional Flags ---
+
+	// Get limit (0 means no limit)
+	limitValue, _ := cmd.Flags().GetInt("limit")
+
+	// Parse date filters
+	fromDate, toDate, err := parseDateRangeFlags(cmd)
+	if err != nil {
+		return ListOptions{}, err
+	}
+
+	// --- 2. Handle Sorting ---
+	sortSpec, _ := cmd.Flags().GetString("sort")
+	sortDesc, _ := cmd.Flags().GetBool("desc")
+	sortBy, sortKeys, err := resolveSortSpec(sortSpec, cmd.Flags().Changed("desc"))
+	if err != nil {
+		return ListOptions{}, err
+	}
+
+	// --- 3. Handle Unit and Note Filters ---
+	unitFilter, _ := cmd.Flags().GetString("unit")
+	if unitFilter != "" && !IsValidUnit(unitFilter) {
+		return ListOptions{}, fmt.Errorf("invalid unit '%s': must be 'kg' or 'lbs'", unitFilter)
+	}
+	noteFilter, _ := cmd.Flags().GetString("note")
+
+	// --- 4. Build ListOptions ---
+	options := ListOptions{
+		FromDate: fromDate,
+		ToDate:   toDate,
+		Limit:    limitValue,
+		SortBy:   sortBy,
+		SortDesc: sortDesc,
+		Sort:     sortKeys,
+		Unit:     unitFilter,
+		Note:     noteFilter,
+		UserID:   resolveUser(cmd),
 	}
 
+	// --- 5. Handle Pagination ---
+	if err := applyPaginationFlags(cmd, &options); err != nil {
+		return ListOptions{}, err
+	}
+	return options, nil
+}
+
+// resolveSortSpec validates a --sort specification. A single field without a '-' or '+'
+// prefix is returned as sortBy, sorted in the direction chosen by --desc; compound
+// orders such as "date,-weight" are returned as keys, and --desc may not be given with them.
+func resolveSortSpec(spec string, descChanged bool) (string, []SortKey, error) {
+	keys, err := ParseSortSpec(spec)
+	if err != nil {
+		return "", nil, err
+	}
+	if isSimpleSortSpec(spec) {
+		return keys[0].Field, nil, nil
+	}
+	if descChanged {
+		return "", nil, fmt.Errorf("desc only applies to a single sort field: prefix fields with '-' to sort them in descending order")
+	}
+	return "", keys, nil
+}
+
+// applyPaginationFlags sets the offset or cursor of options from --page or --after,
+// for commands that have these flags. A page holds --limit entries, or
+// DefaultPageSize when no limit is given.
+func applyPaginationFlags(cmd *cobra.Command, options *ListOptions) error {
+	pageChanged := cmd.Flags().Lookup("page") != nil && cmd.Flags().Changed("page")
+	afterChanged := cmd.Flags().Lookup("after") != nil && cmd.Flags().Changed("after")
+	if !pageChanged && !afterChanged {
+		return nil
+	}
+	if pageChanged && afterChanged {
+		return fmt.Errorf("--page and --after cannot be combined")
+	}
+	if options.Limit < 0 {
+		return fmt.Errorf("invalid limit %d: must not be negative", options.Limit)
+	}
+	if options.Limit == 0 {
+		options.Limit = DefaultPageSize
+	}
+
+	if pageChanged {
+		pageValue, _ := cmd.Flags().GetInt("page")
+		if pageValue < 1 {
+			return fmt.Errorf("invalid page %d: pages are numbered from 1", pageValue)
+		}
+		options.Offset = (pageValue - 1) * options.Limit
+		return nil
+	}
+
+	afterValue, _ := cmd.Flags().GetInt64("after")
+	if afterValue <= 0 {
+		return fmt.Errorf("invalid --after %d: must be the ID of an entry", afterValue)
+	}
+	options.AfterID = afterValue
 	return nil
 }
 
+// parseDateRangeFlags parses the --from and --to flags; the end date includes
+// every entry logged on that day. Relative expressions naming a period (this month,
+// 2025-W10) start at its first day for --from and end at its last day for --to.
+// Nil is returned for flags that are not set.
+func parseDateRangeFlags(cmd *cobra.Command) (*time.Time, *time.Time, error) {
+	app := appFor(cmd)
+	getEnv := app.Getenv
+	var fromDate, toDate *time.Time
+	if cmd.Flags().Changed("from") {
+		dateStr, _ := cmd.Flags().GetString("from")
+		if dateStr != "" {
+			parsedDate, _, err := ParseDateSpanAt(dateStr, app.Now(), getEnv)
+			if err != nil {
+				return nil, nil, fmt.Errorf("invalid from date format '%s': %s", dateStr, dateInputHint(getEnv))
+			}
+			fromDate = &parsedDate
+		}
+	}
+
+	if cmd.Flags().Changed("to") {
+		dateStr, _ := cmd.Flags().GetString("to")
+		if dateStr != "" {
+			// The span ends at the last instant of the end date, so that every entry
+			// logged that day is included, not just those at midnight
+			_, parsedDate, err := ParseDateSpanAt(dateStr, app.Now(), getEnv)
+			if err != nil {
+				return nil, nil, fmt.Errorf("invalid to date format '%s': %s", dateStr, dateInputHint(getEnv))
+			}
+			toDate = &parsedDate
+		}
+	}
+
+	return fromDate, toDate, nil
+}
+
 // runList is the cobra command wrapper that handles errors appropriately for CLI usage
 func runList(cmd *cobra.Command, args []string) {
 	if err := runListInternal(cmd, args); err != nil {
-		fmt.Fprintf(cmd.ErrOrStderr(), "Error: %v\n", err)
-		os.Exit(1)
+		exitWithError(cmd, err)
 	}
 }
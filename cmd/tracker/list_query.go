@@ -0,0 +1,235 @@
+package tracker
+
+// list_query.go - SQL builder for listing weight entries
+// Related files: store.go (DBStore.ListWeights runs the query), list.go (--sort),
+// store_mock.go (same ordering in memory), list_query_test.go (tests)
+// Listing supports any combination of filters and compound sort orders, which a fixed
+// set of sqlc queries cannot express. The builder only ever writes the whitelisted
+// column expressions of sortColumns into the SQL; every value is bound as a parameter.
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// SortKey is one key of a compound sort order
+type SortKey struct {
+	Field string `json:"field" yaml:"field"` // One of sortFields
+	Desc  bool   `json:"desc,omitempty" yaml:"desc,omitempty"`
+}
+
+// sortFields lists the fields entries can be sorted by, in the order they are documented
+var sortFields = []string{"date", "weight", "id", "unit", "note", "user"}
+
+// sortColumns maps each sort field to the column expression it orders by, with %[1]s
+// standing for the table alias. Nullable text columns sort as empty strings so that the cursor
+// comparison, which cannot compare NULLs, agrees with ORDER BY.
+var sortColumns = map[string]string{
+	"date":   "%[1]s.date",
+	"weight": "%[1]s.weight",
+	"id":     "%[1]s.id",
+	"unit":   "COALESCE(%[1]s.unit, '')",
+	"note":   "COALESCE(%[1]s.note, '')",
+	"user":   "COALESCE(%[1]s.user_id, '')",
+}
+
+// ParseSortSpec parses a comma-separated sort specification such as "date,-weight":
+// each field sorts ascending, or descending when prefixed with '-'
+func ParseSortSpec(spec string) ([]SortKey, error) {
+	var keys []SortKey
+	for _, part := range strings.Split(spec, ",") {
+		part = strings.TrimSpace(part)
+		key := SortKey{Field: part}
+		switch {
+		case strings.HasPrefix(part, "-"):
+			key = SortKey{Field: part[1:], Desc: true}
+		case strings.HasPrefix(part, "+"):
+			key = SortKey{Field: part[1:]}
+		}
+		key.Field = strings.ToLower(strings.TrimSpace(key.Field))
+
+		if key.Field == "" {
+			return nil, fmt.Errorf("invalid sort '%s': empty sort key", spec)
+		}
+		if _, ok := sortColumns[key.Field]; !ok {
+			return nil, fmt.Errorf("invalid sort key '%s': must be one of %s", key.Field, strings.Join(sortFields, ", "))
+		}
+		if slices.ContainsFunc(keys, func(k SortKey) bool { return k.Field == key.Field }) {
+			return nil, fmt.Errorf("invalid sort '%s': %s is listed twice", spec, key.Field)
+		}
+		keys = append(keys, key)
+	}
+	return keys, nil
+}
+
+// isSimpleSortSpec reports whether spec is a single field without a direction prefix,
+// the form whose direction is chosen with --desc
+func isSimpleSortSpec(spec string) bool {
+	spec = strings.TrimSpace(spec)
+	return !strings.ContainsAny(spec, ",+-")
+}
+
+// sortKeysFor returns the complete order of options: Sort, or SortBy and SortDesc when
+// Sort is empty (date ascending by default), followed by date and id as tie-breakers in
+// the direction of the first key, so that every entry has a fixed place for the cursor
+func sortKeysFor(options ListOptions) ([]SortKey, error) {
+	keys := slices.Clone(options.Sort)
+	if len(keys) == 0 {
+		field := options.SortBy
+		if field == "" {
+			field = "date"
+		}
+		keys = []SortKey{{Field: field, Desc: options.SortDesc}}
+	}
+
+	seen := make(map[string]bool, len(keys))
+	for _, key := range keys {
+		if _, ok := sortColumns[key.Field]; !ok {
+			return nil, fmt.Errorf("invalid sort key '%s': must be one of %s", key.Field, strings.Join(sortFields, ", "))
+		}
+		if seen[key.Field] {
+			return nil, fmt.Errorf("invalid sort: %s is listed twice", key.Field)
+		}
+		seen[key.Field] = true
+	}
+
+	// The id is unique, so no key after it changes the order
+	for _, tieBreaker := range []string{"date", "id"} {
+		if seen["id"] {
+			break
+		}
+		if !seen[tieBreaker] {
+			keys = append(keys, SortKey{Field: tieBreaker, Desc: keys[0].Desc})
+			seen[tieBreaker] = true
+		}
+	}
+	return keys, nil
+}
+
+// Markers around the matched terms in search snippets
+const (
+	snippetMatchStart = "**"
+	snippetMatchEnd   = "**"
+	snippetEllipsis   = "..."
+	snippetTokens     = 12 // Approximate number of words in a snippet
+)
+
+// buildListQuery returns the SELECT listing the entries selected by options and its
+// arguments. The cursor entry (AfterID) is joined as c, and only entries sorting
+// after it in the requested order are kept.
+func buildListQuery(options ListOptions) (string, []any, error) {
+	return buildEntriesQuery(options, false)
+}
+
+// buildSearchQuery returns the query of buildListQuery with a snippet of each note, its
+// matches wrapped in snippetMatchStart and snippetMatchEnd, as an extra column.
+// options.NoteQuery must be set.
+func buildSearchQuery(options ListOptions) (string, []any, error) {
+	if options.NoteQuery == "" {
+		return "", nil, fmt.Errorf("a search query is required")
+	}
+	return buildEntriesQuery(options, true)
+}
+
+// buildEntriesQuery builds the queries of buildListQuery and buildSearchQuery. A note
+// query joins the full-text index weights_fts, whose auxiliary functions take the
+// table name, so it is not aliased.
+func buildEntriesQuery(options ListOptions, withSnippet bool) (string, []any, error) {
+	keys, err := sortKeysFor(options)
+	if err != nil {
+		return "", nil, err
+	}
+
+	var query strings.Builder
+	var args []any
+	query.WriteString("SELECT w.id, w.weight, w.date, w.unit, w.note, w.user_id")
+	if withSnippet {
+		query.WriteString(", snippet(weights_fts, ?, ?, ?, -1, ?)")
+		args = append(args, snippetMatchStart, snippetMatchEnd, snippetEllipsis, snippetTokens)
+	}
+	query.WriteString(" FROM weights w")
+	if options.NoteQuery != "" {
+		query.WriteString(" JOIN weights_fts ON weights_fts.docid = w.id")
+	}
+	if options.AfterID != 0 {
+		query.WriteString(" JOIN weights c ON c.id = ?")
+		args = append(args, options.AfterID)
+	}
+
+	var conditions []string
+	if options.FromDate != nil {
+		conditions = append(conditions, "w.date >= ?")
+		args = append(args, FormatDateForDB(*options.FromDate))
+	}
+	if options.ToDate != nil {
+		conditions = append(conditions, "w.date <= ?")
+		args = append(args, FormatDateForDB(*options.ToDate))
+	}
+	if options.UserID != "" {
+		conditions = append(conditions, "w.user_id = ?")
+		args = append(args, options.UserID)
+	}
+	if options.Unit != "" {
+		conditions = append(conditions, "w.unit = ?")
+		args = append(args, options.Unit)
+	}
+	if options.Note != "" {
+		conditions = append(conditions, "instr(lower(w.note), lower(?)) > 0")
+		args = append(args, options.Note)
+	}
+	if options.NoteQuery != "" {
+		conditions = append(conditions, "weights_fts MATCH ?")
+		args = append(args, options.NoteQuery)
+	}
+	if options.AfterID != 0 {
+		conditions = append(conditions, cursorCondition(keys))
+	}
+	if len(conditions) > 0 {
+		query.WriteString(" WHERE ")
+		query.WriteString(strings.Join(conditions, " AND "))
+	}
+
+	order := make([]string, len(keys))
+	for i, key := range keys {
+		direction := "ASC"
+		if key.Desc {
+			direction = "DESC"
+		}
+		order[i] = fmt.Sprintf(sortColumns[key.Field], "w") + " " + direction
+	}
+	query.WriteString(" ORDER BY ")
+	query.WriteString(strings.Join(order, ", "))
+
+	limit := options.Limit
+	if limit == 0 {
+		limit = -1 // SQLite treats -1 as no limit
+	}
+	query.WriteString(" LIMIT ? OFFSET ?")
+	args = append(args, limit, options.Offset)
+
+	return query.String(), args, nil
+}
+
+// cursorCondition returns the condition selecting the entries w that sort after the
+// cursor entry c: those greater in the first key, or equal in it and greater in the
+// second, and so on, where greater means smaller for descending keys
+func cursorCondition(keys []SortKey) string {
+	alternatives := make([]string, len(keys))
+	for i, key := range keys {
+		terms := make([]string, 0, i+1)
+		for _, previous := range keys[:i] {
+			column := sortColumns[previous.Field]
+			terms = append(terms, fmt.Sprintf(column, "w")+" = "+fmt.Sprintf(column, "c"))
+		}
+
+		operator := ">"
+		if key.Desc {
+			operator = "<"
+		}
+		column := sortColumns[key.Field]
+		terms = append(terms, fmt.Sprintf(column, "w")+" "+operator+" "+fmt.Sprintf(column, "c"))
+		alternatives[i] = "(" + strings.Join(terms, " AND ") + ")"
+	}
+	return "(" + strings.Join(alternatives, " OR ") + ")"
+}
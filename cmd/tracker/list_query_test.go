@@ -0,0 +1,180 @@
+package tracker
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// list_query_test.go - List query builder tests
+// * purpose: tests sort specifications and the SQL built for listing entries.
+// * tests: ParseSortSpec, resolveSortSpec, sortKeysFor, buildListQuery and --sort
+// * focus: whitelisted sort keys, tie-breakers, cursor conditions and bound arguments.
+// Results of the built queries are checked against the database in list_test.go.
+
+func TestParseSortSpec(t *testing.T) {
+	tests := []struct {
+		spec    string
+		want    []SortKey
+		wantErr string
+	}{
+		{spec: "date", want: []SortKey{{Field: "date"}}},
+		{spec: "-weight", want: []SortKey{{Field: "weight", Desc: true}}},
+		{spec: "date,-weight", want: []SortKey{{Field: "date"}, {Field: "weight", Desc: true}}},
+		{spec: " +Unit , -note ,user,id", want: []SortKey{{Field: "unit"}, {Field: "note", Desc: true}, {Field: "user"}, {Field: "id"}}},
+		{spec: "bmi", wantErr: "invalid sort key 'bmi'"},
+		{spec: "date,", wantErr: "empty sort key"},
+		{spec: "weight,-weight", wantErr: "weight is listed twice"},
+		{spec: "date; DROP TABLE weights", wantErr: "invalid sort key"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.spec, func(t *testing.T) {
+			got, err := ParseSortSpec(tt.spec)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("ParseSortSpec(%q) error = %v, want %q", tt.spec, err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(unexpectedErrorString(err))
+			}
+			if fmt.Sprint(got) != fmt.Sprint(tt.want) {
+				t.Errorf("ParseSortSpec(%q) = %v, want %v", tt.spec, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestResolveSortSpec(t *testing.T) {
+	tests := []struct {
+		name        string
+		spec        string
+		descChanged bool
+		wantSortBy  string
+		wantKeys    []SortKey
+		wantErr     bool
+	}{
+		{name: "single field uses --desc", spec: "weight", descChanged: true, wantSortBy: "weight"},
+		{name: "compound order", spec: "date,-weight", wantKeys: []SortKey{{Field: "date"}, {Field: "weight", Desc: true}}},
+		{name: "prefixed single field", spec: "-weight", wantKeys: []SortKey{{Field: "weight", Desc: true}}},
+		{name: "compound order with --desc", spec: "date,-weight", descChanged: true, wantErr: true},
+		{name: "unknown field", spec: "height", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sortBy, keys, err := resolveSortSpec(tt.spec, tt.descChanged)
+			if tt.wantErr {
+				if err == nil {
+					t.Error("expected error but got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(unexpectedErrorString(err))
+			}
+			if sortBy != tt.wantSortBy || fmt.Sprint(keys) != fmt.Sprint(tt.wantKeys) {
+				t.Errorf("resolveSortSpec(%q) = %q, %v, want %q, %v", tt.spec, sortBy, keys, tt.wantSortBy, tt.wantKeys)
+			}
+		})
+	}
+}
+
+func TestSortKeysFor(t *testing.T) {
+	tests := []struct {
+		name    string
+		options ListOptions
+		want    string
+	}{
+		{name: "default", options: ListOptions{}, want: "[{date false} {id false}]"},
+		{name: "single descending field", options: ListOptions{SortBy: "weight", SortDesc: true}, want: "[{weight true} {date true} {id true}]"},
+		{name: "tie-breakers follow the first key", options: ListOptions{Sort: []SortKey{{Field: "unit", Desc: true}, {Field: "weight"}}}, want: "[{unit true} {weight false} {date true} {id true}]"},
+		{name: "date already listed", options: ListOptions{Sort: []SortKey{{Field: "note"}, {Field: "date", Desc: true}}}, want: "[{note false} {date true} {id false}]"},
+		{name: "id ends the order", options: ListOptions{Sort: []SortKey{{Field: "id", Desc: true}}}, want: "[{id true}]"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			keys, err := sortKeysFor(tt.options)
+			if err != nil {
+				t.Fatal(unexpectedErrorString(err))
+			}
+			if got := fmt.Sprint(keys); got != tt.want {
+				t.Errorf("sortKeysFor() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBuildListQuery(t *testing.T) {
+	query, args, err := buildListQuery(ListOptions{
+		Sort:    []SortKey{{Field: "unit"}, {Field: "weight", Desc: true}},
+		UserID:  "alice",
+		Note:    "50%",
+		AfterID: 7,
+		Limit:   10,
+		Offset:  0,
+	})
+	if err != nil {
+		t.Fatal(unexpectedErrorString(err))
+	}
+
+	wantParts := []string{
+		"JOIN weights c ON c.id = ?",
+		"w.user_id = ?",
+		"instr(lower(w.note), lower(?)) > 0",
+		"((COALESCE(w.unit, '') > COALESCE(c.unit, '')) OR (COALESCE(w.unit, '') = COALESCE(c.unit, '') AND w.weight < c.weight) OR ",
+		"ORDER BY COALESCE(w.unit, '') ASC, w.weight DESC, w.date ASC, w.id ASC LIMIT ? OFFSET ?",
+	}
+	for _, part := range wantParts {
+		if !strings.Contains(query, part) {
+			t.Errorf("query is missing %q:\n%s", part, query)
+		}
+	}
+	if strings.Contains(query, "alice") || strings.Contains(query, "50%") {
+		t.Errorf("values must be bound as arguments, not written into the query:\n%s", query)
+	}
+	if want := "[7 alice 50% 10 0]"; fmt.Sprint(args) != want {
+		t.Errorf("args = %v, want %s", args, want)
+	}
+
+	// No limit is written as SQLite's -1
+	_, args, err = buildListQuery(ListOptions{})
+	if err != nil {
+		t.Fatal(unexpectedErrorString(err))
+	}
+	if want := "[-1 0]"; fmt.Sprint(args) != want {
+		t.Errorf("args = %v, want %s", args, want)
+	}
+
+	if _, _, err := buildListQuery(ListOptions{SortBy: "date DESC; --"}); err == nil {
+		t.Error("expected an error for a sort key outside the whitelist")
+	}
+}
+
+func TestListCommand_CompoundSort(t *testing.T) {
+	store := NewMockStore()
+	seedUserEntries(t, store)
+	app, stdout, stderr, exitCode := newTestApp(store, nil)
+
+	result := runCommand(t, app, stdout, stderr, exitCode, "list", "--sort", "user,-weight", "-o", "json")
+	if result.exitCode != 0 {
+		t.Fatalf("exit code %d, stderr: %s", result.exitCode, result.stderr)
+	}
+	weights := []string{`"weight": 70`, `"weight": 62`, `"weight": 61.5`, `"weight": 88`}
+	position := -1
+	for _, weight := range weights {
+		index := strings.Index(result.stdout, weight)
+		if index <= position {
+			t.Fatalf("output is not ordered by user, then weight descending:\n%s", result.stdout)
+		}
+		position = index
+	}
+
+	result = runCommand(t, app, stdout, stderr, exitCode, "list", "--sort", "user,-weight", "--desc")
+	if result.exitCode != 1 || !strings.Contains(result.stderr, "desc only applies to a single sort field") {
+		t.Errorf("exit code %d, stderr %q, want an error for --desc with a compound order", result.exitCode, result.stderr)
+	}
+}
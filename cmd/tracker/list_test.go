@@ -7,6 +7,7 @@ package tracker
 import (
 	"context"
 	"fmt"
+	"strings"
 	"testing"
 	"time"
 
@@ -25,7 +26,7 @@ func TestListCommand_Integration(t *testing.T) {
 	defer testDB.Close()
 
 	// Create store with test database
-	store := NewDBStoreWithDB(testDB)
+	store := NewDBStoreWithDB(testDB, time.UTC)
 	ctx := context.Background()
 
 	// Add test data with different dates and weights
@@ -192,6 +193,56 @@ func TestListCommand_Integration(t *testing.T) {
 	}
 }
 
+// TestListCommand_Integration_TimeOfDay tests that same-day entries keep their time
+// of day and are ordered by it
+func TestListCommand_Integration_TimeOfDay(t *testing.T) {
+	testDB := setupTestDB(t)
+	defer testDB.Close()
+
+	store := NewDBStoreWithDB(testDB, time.UTC)
+	ctx := context.Background()
+
+	day := time.Date(2025, 3, 10, 0, 0, 0, 0, time.UTC)
+	testEntries := []WeightEntry{
+		{Weight: 76.1, Date: day.Add(21 * time.Hour), Unit: "kg", Note: "evening"},
+		{Weight: 75.2, Date: day.Add(7 * time.Hour), Unit: "kg", Note: "morning"},
+		{Weight: 75.8, Date: day.Add(13 * time.Hour), Unit: "kg", Note: "lunch"},
+	}
+	for _, entry := range testEntries {
+		if _, err := store.AddWeight(ctx, entry); err != nil {
+			t.Fatal(failedTestEntryAdditionString(err))
+		}
+	}
+
+	result, err := store.ListWeights(ctx, ListOptions{SortBy: "date"})
+	if err != nil {
+		t.Fatal(unexpectedErrorString(err))
+	}
+
+	expectedNotes := []string{"morning", "lunch", "evening"}
+	if len(result) != len(expectedNotes) {
+		t.Fatalf("ListWeights() got %d entries, want %d", len(result), len(expectedNotes))
+	}
+	for i, note := range expectedNotes {
+		if result[i].Note != note {
+			t.Errorf("entry %d note = %s, want %s", i, result[i].Note, note)
+		}
+	}
+	if result[0].Date.Hour() != 7 {
+		t.Errorf("time of day was not preserved: got %v", result[0].Date)
+	}
+
+	// A date-only upper bound must include entries logged later that day
+	endOfDay := EndOfDay(day)
+	result, err = store.ListWeights(ctx, ListOptions{ToDate: &endOfDay})
+	if err != nil {
+		t.Fatal(unexpectedErrorString(err))
+	}
+	if len(result) != 3 {
+		t.Errorf("ListWeights() with end-of-day upper bound got %d entries, want 3", len(result))
+	}
+}
+
 // TestListCommand_MockStore tests the list command with MockStore (unit tests)
 func TestListCommand_MockStore(t *testing.T) {
 	store := NewMockStore()
@@ -390,7 +441,7 @@ func TestListCommand_CLI_Integration(t *testing.T) {
 			defer testDB.Close()
 
 			// Create store with test database
-			store := NewDBStoreWithDB(testDB)
+			store := NewDBStoreWithDB(testDB, time.UTC)
 			ctx := context.Background()
 
 			// Add test data
@@ -593,10 +644,10 @@ func TestListCommand_CLI_Integration_Graph(t *testing.T) {
 			description: "Should generate HTML chart with custom filename",
 		},
 		{
-			name:        "graph png output (not implemented)",
+			name:        "graph png output",
 			flags:       map[string]string{"graph": "true", "output": "png"},
-			shouldError: false, // Should default to terminal since PNG is not implemented
-			description: "Should fallback to terminal output for unimplemented PNG",
+			shouldError: false,
+			description: "Should generate PNG chart with auto-generated filename",
 		},
 		{
 			name:        "graph with invalid output type",
@@ -657,7 +708,7 @@ func TestListCommand_CLI_Integration_Graph(t *testing.T) {
 			defer testDB.Close()
 
 			// Create store with test database
-			store := NewDBStoreWithDB(testDB)
+			store := NewDBStoreWithDB(testDB, time.UTC)
 			ctx := context.Background()
 
 			// Add test data with multiple entries for meaningful graphs
@@ -798,3 +849,254 @@ func TestListCommand_CLI_Integration_Graph(t *testing.T) {
 		})
 	}
 }
+
+// TestListWeights_FiltersAndPagination tests that the unit and note filters, the offset
+// and the cursor are applied before the limit, for the database and the mock store
+func TestListWeights_FiltersAndPagination(t *testing.T) {
+	baseDate := time.Date(2025, 1, 1, 8, 0, 0, 0, time.UTC)
+	testEntries := []WeightEntry{
+		{Weight: 80.0, Date: baseDate, Unit: "kg", Note: "Holiday start"},
+		{Weight: 176.0, Date: baseDate.AddDate(0, 0, 1), Unit: "lbs", Note: "travel"},
+		{Weight: 79.5, Date: baseDate.AddDate(0, 0, 2), Unit: "kg"},
+		{Weight: 175.0, Date: baseDate.AddDate(0, 0, 3), Unit: "lbs", Note: "after holiday"},
+		{Weight: 79.0, Date: baseDate.AddDate(0, 0, 4), Unit: "kg", Note: "gym"},
+		{Weight: 78.5, Date: baseDate.AddDate(0, 0, 5), Unit: "kg", Note: "HOLIDAY again"},
+	}
+
+	tests := []struct {
+		name     string
+		options  ListOptions
+		expected []int64 // IDs in order
+		wantErr  bool
+	}{
+		{
+			name:     "unit filter counts towards the limit",
+			options:  ListOptions{SortBy: "date", SortDesc: true, Unit: "lbs", Limit: 2},
+			expected: []int64{4, 2},
+		},
+		{
+			name:     "unit filter with limit",
+			options:  ListOptions{SortBy: "date", SortDesc: true, Unit: "kg", Limit: 3},
+			expected: []int64{6, 5, 3},
+		},
+		{
+			name:     "note filter is a case-insensitive substring",
+			options:  ListOptions{SortBy: "date", Note: "holiday"},
+			expected: []int64{1, 4, 6},
+		},
+		{
+			name:     "note and unit filters combined",
+			options:  ListOptions{SortBy: "date", Note: "holiday", Unit: "kg"},
+			expected: []int64{1, 6},
+		},
+		{
+			name:     "offset skips matching entries",
+			options:  ListOptions{SortBy: "date", SortDesc: true, Limit: 2, Offset: 2},
+			expected: []int64{4, 3},
+		},
+		{
+			name:     "offset with filter",
+			options:  ListOptions{SortBy: "date", Unit: "kg", Limit: 2, Offset: 2},
+			expected: []int64{5, 6},
+		},
+		{
+			name:     "offset past the end",
+			options:  ListOptions{SortBy: "date", Offset: 10},
+			expected: []int64{},
+		},
+		{
+			name:     "cursor in date order",
+			options:  ListOptions{SortBy: "date", SortDesc: true, Limit: 2, AfterID: 4},
+			expected: []int64{3, 2},
+		},
+		{
+			name:     "cursor in weight order",
+			options:  ListOptions{SortBy: "weight", Limit: 3, AfterID: 5},
+			expected: []int64{3, 1, 4},
+		},
+		{
+			name:     "cursor entry excluded by the filter",
+			options:  ListOptions{SortBy: "date", Unit: "kg", AfterID: 2},
+			expected: []int64{3, 5, 6},
+		},
+		{
+			name:     "compound order",
+			options:  ListOptions{Sort: []SortKey{{Field: "unit"}, {Field: "weight", Desc: true}}},
+			expected: []int64{1, 3, 5, 6, 2, 4},
+		},
+		{
+			name:     "compound order with limit and filter",
+			options:  ListOptions{Sort: []SortKey{{Field: "unit", Desc: true}, {Field: "date"}}, Note: "holiday", Limit: 2},
+			expected: []int64{4, 1},
+		},
+		{
+			name:     "cursor in compound order",
+			options:  ListOptions{Sort: []SortKey{{Field: "unit"}, {Field: "weight", Desc: true}}, AfterID: 5, Limit: 2},
+			expected: []int64{6, 2},
+		},
+		{
+			name:     "sort by note, missing notes first",
+			options:  ListOptions{SortBy: "note", Unit: "kg"},
+			expected: []int64{3, 6, 1, 5},
+		},
+		{
+			name:    "unknown sort key",
+			options: ListOptions{SortBy: "weight; DROP TABLE weights"},
+			wantErr: true,
+		},
+		{
+			name:    "unknown cursor",
+			options: ListOptions{AfterID: 99},
+			wantErr: true,
+		},
+		{
+			name:    "negative offset",
+			options: ListOptions{Offset: -1},
+			wantErr: true,
+		},
+	}
+
+	stores := map[string]func(t *testing.T) Store{
+		"DBStore": func(t *testing.T) Store {
+			testDB := setupTestDB(t)
+			t.Cleanup(func() { testDB.Close() })
+			return NewDBStoreWithDB(testDB, time.UTC)
+		},
+		"MockStore": func(t *testing.T) Store { return NewMockStore() },
+	}
+
+	for storeName, newStore := range stores {
+		t.Run(storeName, func(t *testing.T) {
+			store := newStore(t)
+			ctx := context.Background()
+			for _, entry := range testEntries {
+				if _, err := store.AddWeight(ctx, entry); err != nil {
+					t.Fatal(failedTestEntryAdditionString(err))
+				}
+			}
+
+			for _, tt := range tests {
+				t.Run(tt.name, func(t *testing.T) {
+					result, err := store.ListWeights(ctx, tt.options)
+					if tt.wantErr {
+						if err == nil {
+							t.Errorf("ListWeights() expected error but got none")
+						}
+						return
+					}
+					if err != nil {
+						t.Fatal(unexpectedErrorString(err))
+					}
+
+					ids := make([]int64, len(result))
+					for i, entry := range result {
+						ids[i] = entry.ID
+					}
+					if fmt.Sprint(ids) != fmt.Sprint(tt.expected) {
+						t.Errorf("ListWeights() IDs = %v, want %v", ids, tt.expected)
+					}
+				})
+			}
+		})
+	}
+}
+
+// TestListCommand_Pagination tests the --page and --after flags
+func TestListCommand_Pagination(t *testing.T) {
+	store := NewMockStore()
+	ctx := context.Background()
+	baseDate := time.Date(2025, 1, 1, 8, 0, 0, 0, time.UTC)
+	for i := 0; i < 5; i++ {
+		entry := WeightEntry{Weight: 80 - float64(i), Date: baseDate.AddDate(0, 0, i), Unit: "kg"}
+		if _, err := store.AddWeight(ctx, entry); err != nil {
+			t.Fatal(failedTestEntryAdditionString(err))
+		}
+	}
+	app, stdout, stderr, exitCode := newTestApp(store, map[string]string{"DATE_INPUT_FORMAT": "yyyy-mm-dd"})
+
+	tests := []struct {
+		name        string
+		args        []string
+		wantDates   []string
+		wantHint    string
+		wantErr     string
+		wantNoMatch []string
+	}{
+		{
+			name:        "first page",
+			args:        []string{"list", "--page", "1", "--limit", "2"},
+			wantDates:   []string{"05-01-2025", "04-01-2025"},
+			wantHint:    "More entries: use --page 2 or --after 4",
+			wantNoMatch: []string{"03-01-2025"},
+		},
+		{
+			name:        "middle page",
+			args:        []string{"list", "--page", "2", "--limit", "2"},
+			wantDates:   []string{"03-01-2025", "02-01-2025"},
+			wantHint:    "More entries: use --page 3 or --after 2",
+			wantNoMatch: []string{"04-01-2025"},
+		},
+		{
+			name:        "last page has no hint",
+			args:        []string{"list", "--page", "3", "--limit", "2"},
+			wantDates:   []string{"01-01-2025"},
+			wantNoMatch: []string{"More entries"},
+		},
+		{
+			name:        "cursor",
+			args:        []string{"list", "--after", "4", "--limit", "2"},
+			wantDates:   []string{"03-01-2025", "02-01-2025"},
+			wantHint:    "More entries: use --after 2",
+			wantNoMatch: []string{"04-01-2025"},
+		},
+		{
+			name:        "default page size",
+			args:        []string{"list", "--page", "1"},
+			wantDates:   []string{"05-01-2025", "01-01-2025"},
+			wantNoMatch: []string{"More entries"},
+		},
+		{
+			name:    "page and cursor combined",
+			args:    []string{"list", "--page", "2", "--after", "4"},
+			wantErr: "--page and --after cannot be combined",
+		},
+		{
+			name:    "page zero",
+			args:    []string{"list", "--page", "0"},
+			wantErr: "pages are numbered from 1",
+		},
+		{
+			name:    "unknown cursor",
+			args:    []string{"list", "--after", "42"},
+			wantErr: "weight entry with id 42 not found",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := runCommand(t, app, stdout, stderr, exitCode, tt.args...)
+			if tt.wantErr != "" {
+				if result.exitCode != 1 || !strings.Contains(result.stderr, tt.wantErr) {
+					t.Errorf("exit code %d, stderr %q, want exit 1 and %q", result.exitCode, result.stderr, tt.wantErr)
+				}
+				return
+			}
+			if result.exitCode != 0 {
+				t.Fatalf("exit code %d, stderr: %s", result.exitCode, result.stderr)
+			}
+			for _, date := range tt.wantDates {
+				if !strings.Contains(result.stdout, date) {
+					t.Errorf("output is missing %s:\n%s", date, result.stdout)
+				}
+			}
+			if tt.wantHint != "" && !strings.Contains(result.stdout, tt.wantHint) {
+				t.Errorf("output is missing hint %q:\n%s", tt.wantHint, result.stdout)
+			}
+			for _, text := range tt.wantNoMatch {
+				if strings.Contains(result.stdout, text) {
+					t.Errorf("output unexpectedly contains %q:\n%s", text, result.stdout)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,319 @@
+package tracker
+
+// migrate.go - Database commands (db migrate, db status, db rollback, db path)
+// Related files: internal/db/migrate.go (goose provider), migrations/ (embedded SQL files),
+// migrate_test.go (tests)
+// Migrations are embedded in the binary and applied automatically when the database is
+// opened (unless AUTO_MIGRATE=false); these commands inspect and manage them by hand.
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"time"
+
+	"github.com/BlochLior/weight-tracker/internal/db"
+	"github.com/pressly/goose/v3"
+	"github.com/spf13/cobra"
+)
+
+var rollbackYes bool
+
+// Migration describes a schema migration and whether it is applied
+type Migration struct {
+	Version   int64      `json:"version" yaml:"version"`
+	Name      string     `json:"name" yaml:"name"`
+	Applied   bool       `json:"applied" yaml:"applied"`
+	AppliedAt *time.Time `json:"applied_at,omitempty" yaml:"applied_at,omitempty"`
+}
+
+// migrationList is the result of the db commands: migrations with their state
+type migrationList []Migration
+
+func (migrations migrationList) writeTable(w io.Writer, config AppConfig) error {
+	if len(migrations) == 0 {
+		fmt.Fprintln(w, "No migrations found.")
+		return nil
+	}
+
+	applied := 0
+	for _, migration := range migrations {
+		if migration.Applied {
+			applied++
+		}
+	}
+
+	fmt.Fprintf(w, "%d of %d migrations applied:\n\n", applied, len(migrations))
+	for _, migration := range migrations {
+		state := "pending"
+		if migration.Applied {
+			state = "applied"
+			if migration.AppliedAt != nil {
+				state += " " + config.FormatDateTime(*migration.AppliedAt)
+			}
+		}
+		fmt.Fprintf(w, "* %s (%s)\n", migration.Name, state)
+	}
+	return nil
+}
+
+var dbCmd = &cobra.Command{
+	Use:   "db",
+	Short: "Manage the database schema",
+	Long: `Manage the database schema. Migrations are embedded in the binary and applied
+automatically whenever the database is opened, so these commands are only needed to
+inspect the schema or to undo a migration. Set AUTO_MIGRATE=false to turn off
+automatic migrations (e.g. after a rollback).`,
+}
+
+var dbMigrateCmd = &cobra.Command{
+	Use:   "migrate",
+	Short: "Apply all pending migrations",
+	Long: `Apply all pending migrations to the database.
+
+Examples:
+  weight-tracker db migrate
+  weight-tracker db migrate -o json`,
+	Args: cobra.NoArgs,
+	Run:  runDBMigrate,
+}
+
+var dbStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Show applied and pending migrations",
+	Long: `Show every embedded migration and whether it has been applied.
+
+Examples:
+  weight-tracker db status
+  weight-tracker db status -o yaml`,
+	Args: cobra.NoArgs,
+	Run:  runDBStatus,
+}
+
+var dbPathCmd = &cobra.Command{
+	Use:   "path",
+	Short: "Show the database location",
+	Long: `Show the path of the database used by the other commands. It is taken from
+the --db flag, then DATABASE_PATH (environment or .env file), and defaults to
+$XDG_DATA_HOME/weight-tracker/weights.db (~/.local/share/weight-tracker/weights.db).
+
+Examples:
+  weight-tracker db path
+  weight-tracker --db ./weights.db db path`,
+	Args: cobra.NoArgs,
+	Run:  runDBPath,
+}
+
+var dbRollbackCmd = &cobra.Command{
+	Use:   "rollback",
+	Short: "Roll back the most recent migration",
+	Long: `Roll back the most recently applied migration. Rolling back may drop tables
+and the data in them, so you are asked to confirm unless --yes is given.
+
+The next command that opens the database applies the migration again; set
+AUTO_MIGRATE=false to keep the database at the rolled back version.
+
+Examples:
+  weight-tracker db rollback
+  AUTO_MIGRATE=false weight-tracker db rollback --yes`,
+	Args: cobra.NoArgs,
+	Run:  runDBRollback,
+}
+
+func init() {
+	dbCmd.AddCommand(dbMigrateCmd)
+	dbCmd.AddCommand(dbStatusCmd)
+	dbCmd.AddCommand(dbRollbackCmd)
+	dbCmd.AddCommand(dbPathCmd)
+
+	dbRollbackCmd.Flags().BoolVarP(&rollbackYes, "yes", "y", false, "Roll back without asking for confirmation")
+}
+
+// openDatabase opens the database of the command's App without migrating it
+func openDatabase(cmd *cobra.Command) (*sql.DB, error) {
+	database, err := appFor(cmd).OpenDatabase()
+	if err != nil {
+		return nil, fmt.Errorf("could not open database: %w", err)
+	}
+	return database, nil
+}
+
+// migrationFromResult converts a goose migration result
+func migrationFromResult(result *goose.MigrationResult, applied bool, appliedAt time.Time) Migration {
+	migration := Migration{
+		Version: result.Source.Version,
+		Name:    filepath.Base(result.Source.Path),
+		Applied: applied,
+	}
+	if applied {
+		migration.AppliedAt = &appliedAt
+	}
+	return migration
+}
+
+// runDBMigrateInternal contains the core logic and returns errors instead of terminating
+func runDBMigrateInternal(cmd *cobra.Command, args []string) error {
+	_ = args
+
+	renderer, err := rendererFor(cmd)
+	if err != nil {
+		return err
+	}
+
+	database, err := openDatabase(cmd)
+	if err != nil {
+		return err
+	}
+	defer database.Close()
+
+	results, err := db.Migrate(context.Background(), database, GetLocationFromEnv(appFor(cmd).Getenv))
+	if err != nil {
+		return err
+	}
+
+	applied := make([]Migration, 0, len(results))
+	now := appFor(cmd).Now()
+	for _, result := range results {
+		applied = append(applied, migrationFromResult(result, true, now))
+	}
+
+	if renderer.Structured() {
+		return renderer.Render(migrationList(applied))
+	}
+
+	if len(applied) == 0 {
+		fmt.Fprintln(cmd.OutOrStdout(), "Database schema is up to date.")
+		return nil
+	}
+	fmt.Fprintf(cmd.OutOrStdout(), "Applied %d migrations:\n", len(applied))
+	for i, migration := range applied {
+		fmt.Fprintf(cmd.OutOrStdout(), "* %s (%s)\n", migration.Name, results[i].Duration.Round(time.Millisecond))
+	}
+	return nil
+}
+
+// runDBMigrate is the wrapper that handles errors for the CLI
+func runDBMigrate(cmd *cobra.Command, args []string) {
+	if err := runDBMigrateInternal(cmd, args); err != nil {
+		exitWithError(cmd, err)
+	}
+}
+
+// runDBStatusInternal contains the core logic and returns errors instead of terminating
+func runDBStatusInternal(cmd *cobra.Command, args []string) error {
+	_ = args
+
+	renderer, err := rendererFor(cmd)
+	if err != nil {
+		return err
+	}
+
+	database, err := openDatabase(cmd)
+	if err != nil {
+		return err
+	}
+	defer database.Close()
+
+	statuses, err := db.MigrationStatus(context.Background(), database)
+	if err != nil {
+		return err
+	}
+
+	migrations := make([]Migration, 0, len(statuses))
+	for _, status := range statuses {
+		migration := Migration{
+			Version: status.Source.Version,
+			Name:    filepath.Base(status.Source.Path),
+			Applied: status.State == goose.StateApplied,
+		}
+		if migration.Applied {
+			appliedAt := status.AppliedAt
+			migration.AppliedAt = &appliedAt
+		}
+		migrations = append(migrations, migration)
+	}
+
+	return renderer.Render(migrationList(migrations))
+}
+
+// runDBStatus is the wrapper that handles errors for the CLI
+func runDBStatus(cmd *cobra.Command, args []string) {
+	if err := runDBStatusInternal(cmd, args); err != nil {
+		exitWithError(cmd, err)
+	}
+}
+
+// runDBPathInternal contains the core logic and returns errors instead of terminating
+func runDBPathInternal(cmd *cobra.Command, args []string) error {
+	_ = args
+
+	path, err := resolveDatabasePath(appFor(cmd).Getenv)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintln(cmd.OutOrStdout(), path)
+	return nil
+}
+
+// runDBPath is the wrapper that handles errors for the CLI
+func runDBPath(cmd *cobra.Command, args []string) {
+	if err := runDBPathInternal(cmd, args); err != nil {
+		exitWithError(cmd, err)
+	}
+}
+
+// runDBRollbackInternal contains the core logic and returns errors instead of terminating
+func runDBRollbackInternal(cmd *cobra.Command, args []string) error {
+	_ = args
+
+	renderer, err := rendererFor(cmd)
+	if err != nil {
+		return err
+	}
+
+	// Keep prompts off stdout when it carries machine-readable output
+	promptOut := cmd.OutOrStdout()
+	if renderer.Structured() {
+		promptOut = cmd.ErrOrStderr()
+	}
+
+	database, err := openDatabase(cmd)
+	if err != nil {
+		return err
+	}
+	defer database.Close()
+
+	yes, _ := cmd.Flags().GetBool("yes")
+	if !yes {
+		question := "Rolling back may drop tables and their data. Are you sure you want to roll back the latest migration?"
+		if !confirm(cmd.InOrStdin(), promptOut, question) {
+			fmt.Fprintln(promptOut, "Rollback cancelled.")
+			return nil
+		}
+	}
+
+	result, err := db.Rollback(context.Background(), database, GetLocationFromEnv(appFor(cmd).Getenv))
+	if errors.Is(err, db.ErrNoMigrationToRollback) {
+		return fmt.Errorf("nothing to roll back: no migration has been applied")
+	}
+	if err != nil {
+		return err
+	}
+
+	rolledBack := migrationFromResult(result, false, time.Time{})
+	if renderer.Structured() {
+		return renderer.Render(migrationList{rolledBack})
+	}
+	fmt.Fprintf(cmd.OutOrStdout(), "Rolled back %s.\n", rolledBack.Name)
+	return nil
+}
+
+// runDBRollback is the wrapper that handles errors for the CLI
+func runDBRollback(cmd *cobra.Command, args []string) {
+	if err := runDBRollbackInternal(cmd, args); err != nil {
+		exitWithError(cmd, err)
+	}
+}
@@ -0,0 +1,323 @@
+package tracker
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/BlochLior/weight-tracker/internal/db"
+	"github.com/BlochLior/weight-tracker/migrations"
+	"github.com/pressly/goose/v3"
+)
+
+// migrate_test.go - Schema migration tests
+// * purpose: tests the embedded migrations and the db migrate/status/rollback commands.
+// * tests: integration (real SQLite file per test) through ExecuteWithApp
+// * focus: a fresh database is created from the binary alone, rollback and status report correctly,
+//   AUTO_MIGRATE is honoured and invalid values are rejected.
+
+// newMigrationTestApp returns an App whose database is a new SQLite file
+func newMigrationTestApp(t *testing.T) (*App, func(args ...string) commandResult) {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "weights.db")
+
+	app, stdout, stderr, exitCode := newTestApp(nil, nil)
+	app.OpenDatabase = func() (*sql.DB, error) {
+		return sql.Open("sqlite3", path)
+	}
+	app.NewStore = func() (Store, error) {
+		database, err := sql.Open("sqlite3", path)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := db.Migrate(context.Background(), database, time.Local); err != nil {
+			return nil, err
+		}
+		return NewDBStoreWithDB(database, time.Local), nil
+	}
+
+	run := func(args ...string) commandResult {
+		return runCommand(t, app, stdout, stderr, exitCode, args...)
+	}
+	return app, run
+}
+
+// embeddedMigrationCount returns the number of migrations in the binary: the SQL
+// files and the Go migration converting dates to UTC
+func embeddedMigrationCount(t *testing.T) int {
+	t.Helper()
+	files, err := fs.Glob(migrations.FS, "*.sql")
+	if err != nil {
+		t.Fatal(unexpectedErrorString(err))
+	}
+	return len(files) + 1
+}
+
+// migrationStatus runs db status and decodes its JSON output
+func migrationStatus(t *testing.T, run func(args ...string) commandResult) []Migration {
+	t.Helper()
+	result := run("db", "status", "-o", "json")
+	if result.exitCode != 0 {
+		t.Fatalf("db status exit code = %d, stderr %s", result.exitCode, result.stderr)
+	}
+	var statuses []Migration
+	if err := json.Unmarshal([]byte(result.stdout), &statuses); err != nil {
+		t.Fatalf("db status output is not JSON: %v (%s)", err, result.stdout)
+	}
+	return statuses
+}
+
+func countApplied(statuses []Migration) int {
+	applied := 0
+	for _, status := range statuses {
+		if status.Applied {
+			applied++
+		}
+	}
+	return applied
+}
+
+func TestDBCommands_Integration(t *testing.T) {
+	_, run := newMigrationTestApp(t)
+	total := embeddedMigrationCount(t)
+
+	statuses := migrationStatus(t, run)
+	if len(statuses) != total || countApplied(statuses) != 0 {
+		t.Fatalf("fresh database status = %+v, want %d pending migrations", statuses, total)
+	}
+
+	result := run("db", "migrate")
+	if result.exitCode != 0 || !strings.Contains(result.stdout, "Applied") {
+		t.Fatalf("db migrate = %+v, want migrations applied", result)
+	}
+	if result := run("db", "migrate"); !strings.Contains(result.stdout, "up to date") {
+		t.Errorf("second db migrate output = %q, want up to date", result.stdout)
+	}
+
+	statuses = migrationStatus(t, run)
+	if countApplied(statuses) != total {
+		t.Errorf("status after migrate = %+v, want all %d applied", statuses, total)
+	}
+
+	// Rollback asks for confirmation; declining leaves the schema alone
+	if result := run("db", "rollback"); !strings.Contains(result.stdout, "Rollback cancelled") {
+		t.Errorf("db rollback without confirmation output = %q, want cancelled", result.stdout)
+	}
+	if applied := countApplied(migrationStatus(t, run)); applied != total {
+		t.Errorf("applied after cancelled rollback = %d, want %d", applied, total)
+	}
+
+	result = run("db", "rollback", "--yes", "-o", "json")
+	var rolledBack []Migration
+	if err := json.Unmarshal([]byte(result.stdout), &rolledBack); err != nil {
+		t.Fatalf("db rollback output is not JSON: %v (%s)", err, result.stdout)
+	}
+	last := statuses[len(statuses)-1]
+	if len(rolledBack) != 1 || rolledBack[0].Version != last.Version || rolledBack[0].Applied {
+		t.Errorf("db rollback = %+v, want migration %d rolled back", rolledBack, last.Version)
+	}
+
+	statuses = migrationStatus(t, run)
+	if countApplied(statuses) != total-1 || statuses[len(statuses)-1].Applied {
+		t.Errorf("status after rollback = %+v, want only the latest migration pending", statuses)
+	}
+}
+
+func TestDBRollback_NothingApplied(t *testing.T) {
+	_, run := newMigrationTestApp(t)
+
+	result := run("db", "rollback", "--yes")
+	if result.exitCode != 1 || !strings.Contains(result.stderr, "nothing to roll back") {
+		t.Errorf("db rollback on fresh database = %+v, want exit code 1 and nothing to roll back", result)
+	}
+}
+
+func TestStore_FreshDatabase(t *testing.T) {
+	// Commands work on a database that was never migrated by hand, with the store
+	// migrating on open like OpenDB
+	_, run := newMigrationTestApp(t)
+
+	if result := run("add", "72.5"); result.exitCode != 0 {
+		t.Fatalf("add on fresh database = %+v, want success", result)
+	}
+	result := run("list", "-o", "json")
+	if result.exitCode != 0 || !strings.Contains(result.stdout, `"weight": 72.5`) {
+		t.Errorf("list on fresh database = %+v, want the added entry", result)
+	}
+}
+
+func TestResolveDatabasePath(t *testing.T) {
+	tests := []struct {
+		name string
+		flag string
+		env  map[string]string
+		want string
+	}{
+		{name: "flag wins", flag: "/tmp/flag.db", env: map[string]string{"DATABASE_PATH": "/tmp/env.db", "HOME": "/home/u"}, want: "/tmp/flag.db"},
+		{name: "DATABASE_PATH", env: map[string]string{"DATABASE_PATH": "./env.db", "HOME": "/home/u"}, want: "./env.db"},
+		{name: "XDG_DATA_HOME", env: map[string]string{"XDG_DATA_HOME": "/data", "HOME": "/home/u"}, want: "/data/weight-tracker/weights.db"},
+		{name: "relative XDG_DATA_HOME ignored", env: map[string]string{"XDG_DATA_HOME": "data", "HOME": "/home/u"}, want: "/home/u/.local/share/weight-tracker/weights.db"},
+		{name: "home default", env: map[string]string{"HOME": "/home/u"}, want: "/home/u/.local/share/weight-tracker/weights.db"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := db.ResolvePath(tt.flag, func(key string) string { return tt.env[key] })
+			if err != nil {
+				t.Fatal(unexpectedErrorString(err))
+			}
+			if got != tt.want {
+				t.Errorf("ResolvePath() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDBPath_Command(t *testing.T) {
+	app, stdout, stderr, exitCode := newTestApp(nil, map[string]string{"HOME": "/home/u"})
+
+	result := runCommand(t, app, stdout, stderr, exitCode, "db", "path")
+	if strings.TrimSpace(result.stdout) != "/home/u/.local/share/weight-tracker/weights.db" {
+		t.Errorf("db path = %q, want the XDG default", result.stdout)
+	}
+	result = runCommand(t, app, stdout, stderr, exitCode, "--db", "/tmp/other.db", "db", "path")
+	if strings.TrimSpace(result.stdout) != "/tmp/other.db" {
+		t.Errorf("db path with --db = %q, want /tmp/other.db", result.stdout)
+	}
+}
+
+func TestConnect_CreatesDirectory(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "nested", "dir", "weights.db")
+	database, err := db.OpenDB(path, time.Local)
+	if err != nil {
+		t.Fatal(unexpectedErrorString(err))
+	}
+	defer database.Close()
+	if err := database.Ping(); err != nil {
+		t.Errorf("Ping() on %s error = %v", path, err)
+	}
+}
+
+func TestOpenDB_AutoMigrate(t *testing.T) {
+	tests := []struct {
+		value       string
+		wantApplied bool
+		wantErr     string
+	}{
+		{value: "", wantApplied: true},
+		{value: "true", wantApplied: true},
+		{value: "0", wantApplied: false},
+		{value: "false", wantApplied: false},
+		{value: "off", wantErr: "invalid AUTO_MIGRATE value 'off'"},
+	}
+
+	for _, tt := range tests {
+		t.Run("AUTO_MIGRATE="+tt.value, func(t *testing.T) {
+			t.Setenv("AUTO_MIGRATE", tt.value)
+			database, err := db.OpenDB(filepath.Join(t.TempDir(), "weights.db"), time.Local)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("OpenDB() error = %v, want one containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(unexpectedErrorString(err))
+			}
+			defer database.Close()
+
+			statuses, err := db.MigrationStatus(context.Background(), database)
+			if err != nil {
+				t.Fatal(unexpectedErrorString(err))
+			}
+			applied := false
+			for _, status := range statuses {
+				applied = applied || status.State == goose.StateApplied
+			}
+			if applied != tt.wantApplied {
+				t.Errorf("migrations applied = %v, want %v", applied, tt.wantApplied)
+			}
+		})
+	}
+}
+
+func TestMigrate_ConvertsLegacyDatesToUTC(t *testing.T) {
+	// TestMain runs in UTC, where legacy dates happen to equal UTC ones
+	newYork, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skipf("time zone data unavailable: %v", err)
+	}
+	local := time.Local
+	time.Local = newYork
+	defer func() { time.Local = local }()
+
+	testDB := setupTestDB(t)
+	defer testDB.Close()
+	ctx := context.Background()
+
+	// Rows written before UTC storage, in local time
+	if _, err := db.Rollback(ctx, testDB, time.Local); err != nil {
+		t.Fatal(unexpectedErrorString(err))
+	}
+	for _, statement := range []string{
+		`INSERT INTO weights (weight, date, unit) VALUES (70, '2025-01-01 00:00:00', 'kg')`,
+		`INSERT INTO weights (weight, date, unit) VALUES (71, '2024-12-31 23:30:00', 'kg')`,
+		`INSERT INTO weights (weight, date, unit) VALUES (72, '2025-01-02 07:15:00Z', 'kg')`,
+		`INSERT INTO goals (user_id, target_weight, unit, target_date, start_weight) VALUES ('', 65, 'kg', '2025-06-01 00:00:00', 70)`,
+	} {
+		if _, err := testDB.ExecContext(ctx, statement); err != nil {
+			t.Fatal(unexpectedErrorString(err))
+		}
+	}
+
+	storedDates := func() []string {
+		t.Helper()
+		rows, err := testDB.QueryContext(ctx, `SELECT date FROM weights UNION ALL SELECT target_date FROM goals`)
+		if err != nil {
+			t.Fatal(unexpectedErrorString(err))
+		}
+		defer rows.Close()
+		var dates []string
+		for rows.Next() {
+			var date string
+			if err := rows.Scan(&date); err != nil {
+				t.Fatal(unexpectedErrorString(err))
+			}
+			dates = append(dates, date)
+		}
+		return dates
+	}
+
+	if _, err := db.Migrate(ctx, testDB, time.Local); err != nil {
+		t.Fatal(unexpectedErrorString(err))
+	}
+	want := "[2025-01-01 05:00:00Z 2025-01-01 04:30:00Z 2025-01-02 07:15:00Z 2025-06-01 04:00:00Z]"
+	if got := fmt.Sprint(storedDates()); got != want {
+		t.Errorf("dates after migration = %s, want %s", got, want)
+	}
+
+	// Date filters compare the stored text, so they only see legacy rows once converted
+	from := time.Date(2025, 1, 1, 0, 0, 0, 0, newYork)
+	entries, err := NewDBStoreWithDB(testDB, newYork).ListWeights(ctx, ListOptions{FromDate: &from, SortBy: "date"})
+	if err != nil {
+		t.Fatal(unexpectedErrorString(err))
+	}
+	if len(entries) != 2 || entries[0].Weight != 70 || !entries[0].Date.Equal(from) {
+		t.Errorf("entries from %v = %+v, want the legacy entry at midnight and the UTC one", from, entries)
+	}
+
+	// Rolling back writes the dates in local time again
+	if _, err := db.Rollback(ctx, testDB, time.Local); err != nil {
+		t.Fatal(unexpectedErrorString(err))
+	}
+	want = "[2025-01-01 00:00:00 2024-12-31 23:30:00 2025-01-02 02:15:00 2025-06-01 00:00:00]"
+	if got := fmt.Sprint(storedDates()); got != want {
+		t.Errorf("dates after rollback = %s, want %s", got, want)
+	}
+}
@@ -0,0 +1,167 @@
+package tracker
+
+// output.go - Pluggable rendering of command results
+// Related files: root.go (global --output flag), helpers.go (table formatting of entries)
+// Commands hand their results to a Renderer instead of printing directly, so the
+// same command can produce human-readable tables or JSON/YAML for scripts.
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+// OutputFormat represents how command results are rendered
+type OutputFormat string
+
+const (
+	OutputFormatTable OutputFormat = "table"
+	OutputFormatJSON  OutputFormat = "json"
+	OutputFormatYAML  OutputFormat = "yaml"
+)
+
+var outputFormatFlag string
+
+// Renderer renders command results. Commands hand their result to Render: JSON and
+// YAML encode it as is, tables print results implementing tableResult, so that a
+// new command only has to define its result type.
+type Renderer interface {
+	// Render renders the result of a command
+	Render(result any) error
+
+	// Error renders a command failure
+	Error(err error) error
+
+	// Structured reports whether the output is meant for machines; interactive
+	// prompts and progress messages are kept off stdout when it is
+	Structured() bool
+}
+
+// tableResult is a command result that prints itself as human-readable text, with
+// dates formatted as configured in config
+type tableResult interface {
+	writeTable(w io.Writer, config AppConfig) error
+}
+
+// parseOutputFormat validates an output format name
+func parseOutputFormat(format string) (OutputFormat, error) {
+	switch OutputFormat(format) {
+	case OutputFormatTable, OutputFormatJSON, OutputFormatYAML:
+		return OutputFormat(format), nil
+	default:
+		return "", fmt.Errorf("unsupported output format '%s': must be 'table', 'json' or 'yaml'", format)
+	}
+}
+
+// NewRenderer creates a renderer writing results to out and errors to errOut; the
+// table renderer formats dates as configured in config
+func NewRenderer(format OutputFormat, config AppConfig, out, errOut io.Writer) (Renderer, error) {
+	switch format {
+	case OutputFormatTable:
+		return &tableRenderer{out: out, errOut: errOut, config: config}, nil
+	case OutputFormatJSON:
+		return &structuredRenderer{out: out, errOut: errOut, encode: encodeJSON}, nil
+	case OutputFormatYAML:
+		return &structuredRenderer{out: out, errOut: errOut, encode: encodeYAML}, nil
+	default:
+		return nil, fmt.Errorf("unsupported output format '%s'", format)
+	}
+}
+
+// rendererFor returns the renderer selected by the --output flag of cmd (table when
+// the flag is not defined, e.g. in tests), formatting dates as configured in the App
+func rendererFor(cmd *cobra.Command) (Renderer, error) {
+	format := OutputFormatTable
+	if flag := cmd.Flags().Lookup("output"); flag != nil {
+		parsed, err := parseOutputFormat(flag.Value.String())
+		if err != nil {
+			return nil, err
+		}
+		format = parsed
+	}
+	return NewRenderer(format, GetAppConfigFromEnv(appFor(cmd).Getenv), cmd.OutOrStdout(), cmd.ErrOrStderr())
+}
+
+// exitWithError renders err using the selected output format and exits through the App
+func exitWithError(cmd *cobra.Command, err error) {
+	renderer, rendererErr := rendererFor(cmd)
+	if rendererErr != nil {
+		// The output format itself is invalid - fall back to plain text
+		renderer, _ = NewRenderer(OutputFormatTable, GetAppConfigFromEnv(appFor(cmd).Getenv), cmd.OutOrStdout(), cmd.ErrOrStderr())
+	}
+	renderer.Error(err)
+	appFor(cmd).Exit(1)
+}
+
+// tableRenderer renders human-readable text
+type tableRenderer struct {
+	out    io.Writer
+	errOut io.Writer
+	config AppConfig
+}
+
+func (r *tableRenderer) Render(result any) error {
+	table, ok := result.(tableResult)
+	if !ok {
+		return fmt.Errorf("cannot render %T as a table", result)
+	}
+	return table.writeTable(r.out, r.config)
+}
+
+func (r *tableRenderer) Error(err error) error {
+	_, writeErr := fmt.Fprintf(r.errOut, "Error: %v\n", err)
+	return writeErr
+}
+
+func (r *tableRenderer) Structured() bool {
+	return false
+}
+
+// structuredRenderer renders JSON or YAML documents, one per command result
+type structuredRenderer struct {
+	out    io.Writer
+	errOut io.Writer
+	encode func(w io.Writer, v any) error
+}
+
+// errorResult is the structured form of a command failure
+type errorResult struct {
+	Error string `json:"error" yaml:"error"`
+}
+
+func (r *structuredRenderer) Render(result any) error {
+	// Always render a list, never null, so consumers can iterate unconditionally
+	if value := reflect.ValueOf(result); value.Kind() == reflect.Slice && value.IsNil() {
+		result = reflect.MakeSlice(value.Type(), 0, 0).Interface()
+	}
+	return r.encode(r.out, result)
+}
+
+func (r *structuredRenderer) Error(err error) error {
+	return r.encode(r.errOut, errorResult{Error: err.Error()})
+}
+
+func (r *structuredRenderer) Structured() bool {
+	return true
+}
+
+// encodeJSON writes v as indented JSON
+func encodeJSON(w io.Writer, v any) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(v)
+}
+
+// encodeYAML writes v as a YAML document
+func encodeYAML(w io.Writer, v any) error {
+	encoder := yaml.NewEncoder(w)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(v); err != nil {
+		return err
+	}
+	return encoder.Close()
+}
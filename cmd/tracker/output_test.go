@@ -0,0 +1,228 @@
+package tracker
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+// output_test.go - Output renderer tests
+// * purpose: tests table, JSON and YAML rendering of command results.
+// * focus: parseable structured output, errors on stderr and --output/--graph interplay.
+
+func outputTestEntry() WeightEntry {
+	return WeightEntry{
+		ID:     7,
+		Weight: 75.5,
+		Date:   time.Date(2025, 3, 1, 7, 30, 0, 0, time.UTC),
+		Unit:   "kg",
+		Note:   "morning",
+		UserID: "alice",
+	}
+}
+
+func newTestRenderer(t *testing.T, format OutputFormat) (Renderer, *bytes.Buffer, *bytes.Buffer) {
+	t.Helper()
+	var out, errOut bytes.Buffer
+	renderer, err := NewRenderer(format, GetAppConfig(), &out, &errOut)
+	if err != nil {
+		t.Fatal(unexpectedErrorString(err))
+	}
+	return renderer, &out, &errOut
+}
+
+func TestParseOutputFormat(t *testing.T) {
+	for _, format := range []string{"table", "json", "yaml"} {
+		if _, err := parseOutputFormat(format); err != nil {
+			t.Errorf("parseOutputFormat(%q) unexpected error: %v", format, err)
+		}
+	}
+	for _, format := range []string{"", "xml", "html"} {
+		if _, err := parseOutputFormat(format); err == nil {
+			t.Errorf("parseOutputFormat(%q) expected error but got none", format)
+		}
+	}
+}
+
+func TestTableRenderer(t *testing.T) {
+	renderer, out, errOut := newTestRenderer(t, OutputFormatTable)
+
+	if err := renderer.Render(outputTestEntry()); err != nil {
+		t.Fatal(unexpectedErrorString(err))
+	}
+	if !strings.Contains(out.String(), "* Weight: 75.50 kg") {
+		t.Errorf("table output missing weight line:\n%s", out.String())
+	}
+
+	out.Reset()
+	if err := renderer.Render(entryList(nil)); err != nil {
+		t.Fatal(unexpectedErrorString(err))
+	}
+	if !strings.Contains(out.String(), "No weight entries found.") {
+		t.Errorf("table output for no entries = %q", out.String())
+	}
+
+	// Results without a table form are reported instead of printed
+	if err := renderer.Render(struct{ Weight float64 }{75.5}); err == nil {
+		t.Errorf("Render() expected error for a result without a table form but got none")
+	}
+
+	renderer.Error(errors.New("boom"))
+	if errOut.String() != "Error: boom\n" {
+		t.Errorf("table error output = %q, want %q", errOut.String(), "Error: boom\n")
+	}
+	if renderer.Structured() {
+		t.Errorf("table renderer must not be structured")
+	}
+}
+
+func TestStructuredRenderer_JSON(t *testing.T) {
+	renderer, out, errOut := newTestRenderer(t, OutputFormatJSON)
+	entry := outputTestEntry()
+
+	if err := renderer.Render(entryList{entry}); err != nil {
+		t.Fatal(unexpectedErrorString(err))
+	}
+	var entries []WeightEntry
+	if err := json.Unmarshal(out.Bytes(), &entries); err != nil {
+		t.Fatalf("output is not valid JSON: %v\n%s", err, out.String())
+	}
+	if len(entries) != 1 || entries[0].Weight != 75.5 || !entries[0].Date.Equal(entry.Date) {
+		t.Errorf("decoded entries = %+v, want [%+v]", entries, entry)
+	}
+
+	// An empty result is an empty array, not null
+	out.Reset()
+	if err := renderer.Render(entryList(nil)); err != nil {
+		t.Fatal(unexpectedErrorString(err))
+	}
+	if strings.TrimSpace(out.String()) != "[]" {
+		t.Errorf("empty entries = %q, want []", out.String())
+	}
+
+	out.Reset()
+	stats := calculateStatistics([]WeightEntry{entry, {ID: 8, Weight: 74.0, Date: entry.Date.AddDate(0, 0, 14), Unit: "kg"}})
+	if err := renderer.Render(statisticsReport{stats: stats}); err != nil {
+		t.Fatal(unexpectedErrorString(err))
+	}
+	var decoded map[string]any
+	if err := json.Unmarshal(out.Bytes(), &decoded); err != nil {
+		t.Fatalf("statistics are not valid JSON: %v\n%s", err, out.String())
+	}
+	if decoded["total_entries"] != 2.0 || decoded["time_span_days"] != 14.0 || decoded["unit"] != "kg" {
+		t.Errorf("decoded statistics = %v", decoded)
+	}
+
+	out.Reset()
+	if err := renderer.Render(deleteResult{Deleted: true, Entry: entry}); err != nil {
+		t.Fatal(unexpectedErrorString(err))
+	}
+	var deleted deleteResult
+	if err := json.Unmarshal(out.Bytes(), &deleted); err != nil {
+		t.Fatalf("delete result is not valid JSON: %v", err)
+	}
+	if !deleted.Deleted || deleted.Entry.ID != entry.ID {
+		t.Errorf("delete result = %+v", deleted)
+	}
+
+	renderer.Error(errors.New("boom"))
+	var result errorResult
+	if err := json.Unmarshal(errOut.Bytes(), &result); err != nil {
+		t.Fatalf("error is not valid JSON: %v\n%s", err, errOut.String())
+	}
+	if result.Error != "boom" {
+		t.Errorf("error = %q, want boom", result.Error)
+	}
+}
+
+func TestStructuredRenderer_YAML(t *testing.T) {
+	renderer, out, _ := newTestRenderer(t, OutputFormatYAML)
+	entry := outputTestEntry()
+
+	if err := renderer.Render(entry); err != nil {
+		t.Fatal(unexpectedErrorString(err))
+	}
+	var decoded WeightEntry
+	if err := yaml.Unmarshal(out.Bytes(), &decoded); err != nil {
+		t.Fatalf("output is not valid YAML: %v\n%s", err, out.String())
+	}
+	if decoded.ID != entry.ID || decoded.UserID != "alice" || decoded.Note != "morning" {
+		t.Errorf("decoded entry = %+v, want %+v", decoded, entry)
+	}
+
+	out.Reset()
+	if err := renderer.Render(userList{{ID: "alice", CreatedAt: entry.Date}}); err != nil {
+		t.Fatal(unexpectedErrorString(err))
+	}
+	if !strings.Contains(out.String(), "id: alice") {
+		t.Errorf("users output = %q", out.String())
+	}
+
+	// Statistics are encoded without the table options they are rendered with
+	out.Reset()
+	stats := calculateStatistics([]WeightEntry{entry})
+	if err := renderer.Render(statisticsReport{stats: stats, verbose: true}); err != nil {
+		t.Fatal(unexpectedErrorString(err))
+	}
+	if !strings.Contains(out.String(), "total_entries: 1") || strings.Contains(out.String(), "verbose") {
+		t.Errorf("statistics output = %q", out.String())
+	}
+}
+
+func TestResolveGraphOutput(t *testing.T) {
+	tests := []struct {
+		name        string
+		flags       map[string]string
+		expected    GraphOutputType
+		deprecated  bool // --output used to select the chart
+		expectError bool
+	}{
+		{name: "default", expected: OutputTerminal},
+		{name: "graph-output html", flags: map[string]string{"graph-output": "html"}, expected: OutputHTML},
+		{name: "legacy --output png", flags: map[string]string{"output": "png"}, expected: OutputPNG, deprecated: true},
+		{name: "table output keeps terminal chart", flags: map[string]string{"output": "table"}, expected: OutputTerminal},
+		{name: "graph-output svg", flags: map[string]string{"graph-output": "svg"}, expected: OutputSVG},
+		{name: "graph-output wins over output", flags: map[string]string{"output": "html", "graph-output": "png"}, expected: OutputPNG},
+		{name: "json with graph", flags: map[string]string{"output": "json"}, expectError: true},
+		{name: "invalid graph output", flags: map[string]string{"graph-output": "svgz"}, expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Create a fresh test command for each test case to avoid flag contamination
+			cmd := &cobra.Command{Use: "list"}
+			cmd.Flags().StringP("output", "o", "table", "Result format")
+			cmd.Flags().String("graph-output", "terminal", "Graph output type")
+			var stderr bytes.Buffer
+			cmd.SetErr(&stderr)
+			for flag, value := range tt.flags {
+				if err := cmd.Flags().Set(flag, value); err != nil {
+					t.Fatalf("failed to set flag %s: %v", flag, err)
+				}
+			}
+
+			result, err := resolveGraphOutput(cmd)
+			if tt.expectError {
+				if err == nil {
+					t.Errorf("resolveGraphOutput() expected error but got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(unexpectedErrorString(err))
+			}
+			if result != tt.expected {
+				t.Errorf("resolveGraphOutput() = %s, want %s", result, tt.expected)
+			}
+			if deprecated := strings.Contains(stderr.String(), "has been deprecated, use --graph-output"); deprecated != tt.deprecated {
+				t.Errorf("deprecation notice printed = %v, want %v (stderr %q)", deprecated, tt.deprecated, stderr.String())
+			}
+		})
+	}
+}
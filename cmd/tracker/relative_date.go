@@ -0,0 +1,216 @@
+package tracker
+
+// relative_date.go - Relative and natural-language date input
+// Related files: app_config.go (absolute date formats), add.go, update.go, goal.go (--date, --by),
+// list.go (--from/--to), relative_date_test.go (tests)
+// Besides dates in the configured format, date flags accept expressions such as
+// "yesterday", "-3d", "last monday", "2 weeks ago", "this month" and ISO weeks
+// ("2025-W10"). Expressions are resolved against the App clock. Some of them name a
+// period rather than a day: --from uses its first day and --to its last.
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	// -3d, +2w, -1m, -1y
+	offsetPattern = regexp.MustCompile(`^([+-])(\d+)([dwmy])$`)
+	// 2 weeks ago, 1 day ago
+	agoPattern = regexp.MustCompile(`^(\d+) (day|week|month|year)s? ago$`)
+	// last monday, next fri, this sunday, monday
+	weekdayPattern = regexp.MustCompile(`^(?:(last|next|this) )?([a-z]+)$`)
+	// this week, last month, next year
+	periodPattern = regexp.MustCompile(`^(last|this|next) (week|month|year)$`)
+	// 2025-W10, 2025-w10-3
+	isoWeekPattern = regexp.MustCompile(`^(\d{4})-w(\d{2})(?:-([1-7]))?$`)
+)
+
+// weekdayNames maps full and abbreviated weekday names to time.Weekday
+var weekdayNames = map[string]time.Weekday{
+	"sunday": time.Sunday, "sun": time.Sunday,
+	"monday": time.Monday, "mon": time.Monday,
+	"tuesday": time.Tuesday, "tue": time.Tuesday, "tues": time.Tuesday,
+	"wednesday": time.Wednesday, "wed": time.Wednesday,
+	"thursday": time.Thursday, "thu": time.Thursday, "thurs": time.Thursday,
+	"friday": time.Friday, "fri": time.Friday,
+	"saturday": time.Saturday, "sat": time.Saturday,
+}
+
+// ParseDateAt parses a date in the configured input format or a relative expression
+// resolved against now. Expressions naming a period return its first day.
+func ParseDateAt(dateStr string, now time.Time, getEnv func(string) string) (time.Time, error) {
+	start, _, err := ParseDateSpanAt(dateStr, now, getEnv)
+	return start, err
+}
+
+// ParseDateSpanAt parses a date like ParseDateAt and returns the first and last instant
+// of the days it covers: a single day for dates, the whole period for expressions
+// such as "this month" or "2025-W10"
+func ParseDateSpanAt(dateStr string, now time.Time, getEnv func(string) string) (time.Time, time.Time, error) {
+	start, end, ok, err := parseRelativeDate(dateStr, now, GetLocationFromEnv(getEnv))
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+	if ok {
+		return start, EndOfDay(end), nil
+	}
+
+	date, err := ParseDateFromEnv(dateStr, getEnv)
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+	return date, EndOfDay(date), nil
+}
+
+// dateInputHint describes the accepted date input for error messages
+func dateInputHint(getEnv func(string) string) string {
+	return fmt.Sprintf("use %s format or a relative date such as today, -3d, last monday or 2025-W10", GetInputFormatDescriptionFromEnv(getEnv))
+}
+
+// parseRelativeDate resolves a relative expression against now and returns the first
+// and last day it covers, at midnight in location like dates parsed with ParseDate.
+// ok is false when dateStr is not a relative expression.
+func parseRelativeDate(dateStr string, now time.Time, location *time.Location) (start, end time.Time, ok bool, err error) {
+	expr := strings.Join(strings.Fields(strings.ToLower(dateStr)), " ")
+	now = now.In(location)
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, location)
+	day := func(t time.Time) (time.Time, time.Time, bool, error) { return t, t, true, nil }
+
+	switch expr {
+	case "today":
+		return day(today)
+	case "yesterday":
+		return day(today.AddDate(0, 0, -1))
+	case "tomorrow":
+		return day(today.AddDate(0, 0, 1))
+	}
+
+	if match := offsetPattern.FindStringSubmatch(expr); match != nil {
+		count, err := strconv.Atoi(match[2])
+		if err != nil {
+			return time.Time{}, time.Time{}, false, fmt.Errorf("invalid relative date '%s': %w", dateStr, err)
+		}
+		if match[1] == "-" {
+			count = -count
+		}
+		return day(shiftDate(today, count, match[3]))
+	}
+
+	if match := agoPattern.FindStringSubmatch(expr); match != nil {
+		count, err := strconv.Atoi(match[1])
+		if err != nil {
+			return time.Time{}, time.Time{}, false, fmt.Errorf("invalid relative date '%s': %w", dateStr, err)
+		}
+		return day(shiftDate(today, -count, match[2][:1]))
+	}
+
+	if match := periodPattern.FindStringSubmatch(expr); match != nil {
+		start, end := periodAround(today, match[2], match[1])
+		return start, end, true, nil
+	}
+
+	if match := isoWeekPattern.FindStringSubmatch(expr); match != nil {
+		year, _ := strconv.Atoi(match[1])
+		week, _ := strconv.Atoi(match[2])
+		monday, err := isoWeekStart(year, week, location)
+		if err != nil {
+			return time.Time{}, time.Time{}, false, fmt.Errorf("invalid ISO week '%s': %w", dateStr, err)
+		}
+		if match[3] != "" {
+			weekday, _ := strconv.Atoi(match[3])
+			return day(monday.AddDate(0, 0, weekday-1))
+		}
+		return monday, monday.AddDate(0, 0, 6), true, nil
+	}
+
+	if match := weekdayPattern.FindStringSubmatch(expr); match != nil {
+		if weekday, exists := weekdayNames[match[2]]; exists {
+			return day(relativeWeekday(today, weekday, match[1]))
+		}
+	}
+
+	return time.Time{}, time.Time{}, false, nil
+}
+
+// shiftDate moves date by count days, weeks, months or years (unit d, w, m or y).
+// Month and year shifts stay within the target month, so one month before
+// 31 March is 28 or 29 February rather than early March.
+func shiftDate(date time.Time, count int, unit string) time.Time {
+	switch unit {
+	case "d":
+		return date.AddDate(0, 0, count)
+	case "w":
+		return date.AddDate(0, 0, 7*count)
+	case "y":
+		count *= 12
+	}
+
+	firstOfMonth := time.Date(date.Year(), date.Month(), 1, 0, 0, 0, 0, date.Location()).AddDate(0, count, 0)
+	lastDay := firstOfMonth.AddDate(0, 1, -1).Day()
+	return firstOfMonth.AddDate(0, 0, min(date.Day(), lastDay)-1)
+}
+
+// periodAround returns the first and last day of the week (Monday to Sunday), month or
+// year containing today, or the one before (last) or after (next) it
+func periodAround(today time.Time, period, which string) (time.Time, time.Time) {
+	offset := map[string]int{"last": -1, "this": 0, "next": 1}[which]
+
+	switch period {
+	case "week":
+		monday := today.AddDate(0, 0, -daysSinceMonday(today)+7*offset)
+		return monday, monday.AddDate(0, 0, 6)
+	case "month":
+		first := time.Date(today.Year(), today.Month()+time.Month(offset), 1, 0, 0, 0, 0, today.Location())
+		return first, first.AddDate(0, 1, -1)
+	default:
+		first := time.Date(today.Year()+offset, time.January, 1, 0, 0, 0, 0, today.Location())
+		return first, first.AddDate(1, 0, -1)
+	}
+}
+
+// relativeWeekday returns the given weekday relative to today: "last" is the latest one
+// before today, "next" the first one after today, "this" the one in the current week
+// (Monday to Sunday) and no qualifier the latest one on or before today
+func relativeWeekday(today time.Time, weekday time.Weekday, which string) time.Time {
+	daysBack := (int(today.Weekday()) - int(weekday) + 7) % 7
+
+	switch which {
+	case "last":
+		if daysBack == 0 {
+			daysBack = 7
+		}
+		return today.AddDate(0, 0, -daysBack)
+	case "next":
+		daysAhead := (int(weekday) - int(today.Weekday()) + 7) % 7
+		if daysAhead == 0 {
+			daysAhead = 7
+		}
+		return today.AddDate(0, 0, daysAhead)
+	case "this":
+		monday := today.AddDate(0, 0, -daysSinceMonday(today))
+		return monday.AddDate(0, 0, (int(weekday)+6)%7)
+	default:
+		return today.AddDate(0, 0, -daysBack)
+	}
+}
+
+// daysSinceMonday returns how many days date is after the Monday of its ISO week
+func daysSinceMonday(date time.Time) int {
+	return (int(date.Weekday()) + 6) % 7
+}
+
+// isoWeekStart returns the Monday of ISO week number week of year, at midnight in location
+func isoWeekStart(year, week int, location *time.Location) (time.Time, error) {
+	// 4 January is always in week 1
+	jan4 := time.Date(year, time.January, 4, 0, 0, 0, 0, location)
+	monday := jan4.AddDate(0, 0, -daysSinceMonday(jan4)+7*(week-1))
+
+	if _, isoWeek := monday.ISOWeek(); week < 1 || isoWeek != week {
+		return time.Time{}, fmt.Errorf("year %d has no week %d", year, week)
+	}
+	return monday, nil
+}
@@ -0,0 +1,137 @@
+package tracker
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+// relative_date_test.go - Relative date input tests
+// * purpose: tests resolving relative expressions and ISO weeks against a fixed clock.
+// * tests: unit (parseRelativeDate, ParseDateSpanAt) and CLI through ExecuteWithApp with MockStore
+// * focus: day and period expressions, month-end clamping and --from/--to spans.
+
+func TestParseDateSpanAt(t *testing.T) {
+	// Friday 14 March 2025
+	now := time.Date(2025, 3, 14, 7, 30, 0, 0, time.UTC)
+	day := func(year int, month time.Month, d int) time.Time {
+		return time.Date(year, month, d, 0, 0, 0, 0, time.UTC)
+	}
+
+	tests := []struct {
+		expr      string
+		wantStart time.Time
+		wantEnd   time.Time // Start of the last day; zero means the same day as wantStart
+		wantErr   bool
+	}{
+		{expr: "today", wantStart: day(2025, 3, 14)},
+		{expr: "Yesterday", wantStart: day(2025, 3, 13)},
+		{expr: "tomorrow", wantStart: day(2025, 3, 15)},
+		{expr: "-3d", wantStart: day(2025, 3, 11)},
+		{expr: "+1w", wantStart: day(2025, 3, 21)},
+		{expr: "-1m", wantStart: day(2025, 2, 14)},
+		{expr: "-1y", wantStart: day(2024, 3, 14)},
+		{expr: "2 weeks ago", wantStart: day(2025, 2, 28)},
+		{expr: "1 day ago", wantStart: day(2025, 3, 13)},
+		{expr: "3 months ago", wantStart: day(2024, 12, 14)},
+		{expr: "last monday", wantStart: day(2025, 3, 10)},
+		{expr: "last friday", wantStart: day(2025, 3, 7)},
+		{expr: "next friday", wantStart: day(2025, 3, 21)},
+		{expr: "this sunday", wantStart: day(2025, 3, 16)},
+		{expr: "wed", wantStart: day(2025, 3, 12)},
+		{expr: "friday", wantStart: day(2025, 3, 14)},
+		{expr: "this week", wantStart: day(2025, 3, 10), wantEnd: day(2025, 3, 16)},
+		{expr: "last week", wantStart: day(2025, 3, 3), wantEnd: day(2025, 3, 9)},
+		{expr: "this month", wantStart: day(2025, 3, 1), wantEnd: day(2025, 3, 31)},
+		{expr: "last month", wantStart: day(2025, 2, 1), wantEnd: day(2025, 2, 28)},
+		{expr: "next year", wantStart: day(2026, 1, 1), wantEnd: day(2026, 12, 31)},
+		{expr: "2025-W10", wantStart: day(2025, 3, 3), wantEnd: day(2025, 3, 9)},
+		{expr: "2025-W01", wantStart: day(2024, 12, 30), wantEnd: day(2025, 1, 5)},
+		{expr: "2020-W53-7", wantStart: day(2021, 1, 3)},
+		{expr: "2025-W53", wantErr: true},
+		{expr: "15-09-2024", wantStart: day(2024, 9, 15)},
+		{expr: "someday", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			start, end, err := ParseDateSpanAt(tt.expr, now, func(string) string { return "" })
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("ParseDateSpanAt(%q) = %v, want an error", tt.expr, start)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(unexpectedErrorString(err))
+			}
+			wantEnd := tt.wantEnd
+			if wantEnd.IsZero() {
+				wantEnd = tt.wantStart
+			}
+			if !start.Equal(tt.wantStart) || !end.Equal(EndOfDay(wantEnd)) {
+				t.Errorf("ParseDateSpanAt(%q) = %v - %v, want %v - %v", tt.expr, start, end, tt.wantStart, EndOfDay(wantEnd))
+			}
+		})
+	}
+}
+
+func TestShiftDate_ClampsToMonthEnd(t *testing.T) {
+	tests := []struct {
+		date  time.Time
+		count int
+		unit  string
+		want  time.Time
+	}{
+		{date: time.Date(2025, 3, 31, 0, 0, 0, 0, time.UTC), count: -1, unit: "m", want: time.Date(2025, 2, 28, 0, 0, 0, 0, time.UTC)},
+		{date: time.Date(2024, 2, 29, 0, 0, 0, 0, time.UTC), count: 1, unit: "y", want: time.Date(2025, 2, 28, 0, 0, 0, 0, time.UTC)},
+		{date: time.Date(2025, 1, 31, 0, 0, 0, 0, time.UTC), count: 1, unit: "m", want: time.Date(2025, 2, 28, 0, 0, 0, 0, time.UTC)},
+	}
+
+	for _, tt := range tests {
+		if got := shiftDate(tt.date, tt.count, tt.unit); !got.Equal(tt.want) {
+			t.Errorf("shiftDate(%v, %d, %s) = %v, want %v", tt.date, tt.count, tt.unit, got, tt.want)
+		}
+	}
+}
+
+func TestRelativeDateFlags(t *testing.T) {
+	store := NewMockStore()
+	app, stdout, stderr, exitCode := newTestApp(store, nil)
+
+	// The App clock is Friday 14 March 2025
+	for _, args := range [][]string{
+		{"add", "70", "--date", "yesterday"},
+		{"add", "71", "--date", "last monday"},
+		{"add", "72", "--date", "2 weeks ago"},
+		{"add", "73", "--date", "last month"},
+	} {
+		if result := runCommand(t, app, stdout, stderr, exitCode, args...); result.exitCode != 0 {
+			t.Fatalf("%v exit code = %d, stderr %s", args, result.exitCode, result.stderr)
+		}
+	}
+
+	tests := []struct {
+		name string
+		args []string
+		want int
+	}{
+		{name: "last 7 days", args: []string{"--from", "-7d"}, want: 2},
+		{name: "this week", args: []string{"--from", "this week", "--to", "this week"}, want: 2},
+		{name: "ISO week", args: []string{"--from", "2025-W09", "--to", "2025-W09"}, want: 1},
+		{name: "last month", args: []string{"--from", "last month", "--to", "last month"}, want: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := runCommand(t, app, stdout, stderr, exitCode, append([]string{"list", "-o", "json"}, tt.args...)...)
+			var entries []WeightEntry
+			if err := json.Unmarshal([]byte(result.stdout), &entries); err != nil {
+				t.Fatalf("list output is not JSON: %v (%s %s)", err, result.stdout, result.stderr)
+			}
+			if len(entries) != tt.want {
+				t.Errorf("list %v = %d entries, want %d", tt.args, len(entries), tt.want)
+			}
+		})
+	}
+}
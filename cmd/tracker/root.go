@@ -1,6 +1,11 @@
 package tracker
 
 import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/BlochLior/weight-tracker/internal/db"
 	"github.com/spf13/cobra"
 )
 
@@ -10,16 +15,42 @@ var rootCmd = &cobra.Command{
 	Long: `weight-tracker is a tool to track your weight.
 It can be used to track your weight over time and to see how much you have lost or gained.
 The tool can also output graphs of your weight over time.`,
+	PersistentPreRunE: setupApp,
 }
 
+// Execute runs the root command with the default App, after loading the .env
+// file of the working directory (if any) into the environment. The config file is
+// layered under the environment by setupApp, which also reports an unreadable file.
 func Execute() error {
-	return rootCmd.Execute()
+	if err := db.LoadEnvFile(); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return err
+	}
+	return ExecuteWithApp(DefaultApp())
+}
+
+// ExecuteWithApp runs the root command with the dependencies of app, e.g. a custom
+// Store when embedding the commands in other tools
+func ExecuteWithApp(app *App) error {
+	return rootCmd.ExecuteContext(WithApp(context.Background(), app))
 }
 
 func init() {
 	rootCmd.AddCommand(addCmd)
 	rootCmd.AddCommand(listCmd)
+	rootCmd.AddCommand(searchCmd)
 	rootCmd.AddCommand(deleteCmd)
 	rootCmd.AddCommand(updateCmd)
 	rootCmd.AddCommand(statsCmd)
+	rootCmd.AddCommand(usersCmd)
+	rootCmd.AddCommand(importCmd)
+	rootCmd.AddCommand(exportCmd)
+	rootCmd.AddCommand(goalCmd)
+	rootCmd.AddCommand(serveCmd)
+	rootCmd.AddCommand(dbCmd)
+	rootCmd.AddCommand(configCmd)
+
+	rootCmd.PersistentFlags().StringVar(&currentUserFlag, "user", "", "User whose entries to work with (default from WEIGHT_TRACKER_USER, empty = all users)")
+	rootCmd.PersistentFlags().StringVar(&databasePathFlag, "db", "", "Path to the SQLite database (default from DATABASE_PATH, .env or ~/.local/share/weight-tracker/weights.db)")
+	rootCmd.PersistentFlags().StringVarP(&outputFormatFlag, "output", "o", string(OutputFormatTable), "Result format (table, json, yaml)")
 }
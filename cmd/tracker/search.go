@@ -0,0 +1,152 @@
+package tracker
+
+// search.go - Search command for full-text search over entry notes
+// Related files: list_query.go (builds the MATCH query), store.go (SearchWeights),
+// migrations/20261016120000_create_weights_fts.sql (full-text index), search_test.go (tests)
+// Notes explain outliers ("sick", "after workout", "vacation"); search finds those
+// entries again using SQLite's full-text query syntax and shows where each note matched.
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+var searchCmd = &cobra.Command{
+	Use:   "search <query>",
+	Short: "Search entry notes",
+	Long: `Search the notes of weight entries with a full-text query and show the matching
+part of each note.
+
+Words match whole words regardless of case. Queries support:
+  vacation holiday        both words (AND is implied)
+  vacation OR holiday     either word
+  sick NOT flu            the first word without the second
+  "after workout"         the exact phrase
+  travel*                 words starting with "travel"
+  (sick OR flu) travel    grouping with parentheses
+
+The filters of list (--from, --to, --unit, --limit, --sort, --desc) narrow the results.
+
+Examples:
+  weight-tracker search vacation
+  weight-tracker search "vacation OR holiday"
+  weight-tracker search '"after workout"' --from -30d
+  weight-tracker search "sick*" -o json`,
+	Args: cobra.MinimumNArgs(1),
+	Run:  runSearch,
+}
+
+func init() {
+	searchCmd.Flags().StringVarP(&searchFromDate, "from", "f", "", "Start date for filtering (DATE_INPUT_FORMAT or relative: today, -7d, last monday, this month, 2025-W10)")
+	searchCmd.Flags().StringVarP(&searchToDate, "to", "t", "", "End date for filtering (DATE_INPUT_FORMAT or relative: today, -7d, last monday, this month, 2025-W10)")
+	searchCmd.Flags().IntVarP(&searchLimit, "limit", "l", 0, "Maximum number of entries to show (0 = no limit)")
+	searchCmd.Flags().StringVarP(&searchSortField, "sort", "s", "date", sortFlagUsage)
+	searchCmd.Flags().BoolVarP(&searchDesc, "desc", "d", true, "Sort in descending order (with a single sort field)")
+	searchCmd.Flags().StringVarP(&searchUnitFilter, "unit", "u", "", "Filter by unit (kg, lbs)")
+}
+
+var searchFromDate string
+var searchToDate string
+var searchLimit int
+var searchSortField string
+var searchDesc bool
+var searchUnitFilter string
+
+// runSearchInternal contains the core logic and returns errors instead of terminating
+func runSearchInternal(cmd *cobra.Command, args []string) error {
+	renderer, err := rendererFor(cmd)
+	if err != nil {
+		return err
+	}
+
+	// Unquoted words are searched together, as if they were quoted as one query
+	noteQuery := strings.TrimSpace(strings.Join(args, " "))
+	if noteQuery == "" {
+		return fmt.Errorf("search query must not be empty")
+	}
+
+	options, err := buildListOptions(cmd)
+	if err != nil {
+		return err
+	}
+	options.NoteQuery = noteQuery
+
+	app := appFor(cmd)
+	store, err := app.NewStore()
+	if err != nil {
+		return fmt.Errorf("could not create store: %w", err)
+	}
+	defer store.Close()
+
+	results, err := store.SearchWeights(context.Background(), options)
+	if err != nil {
+		return err
+	}
+	return renderer.Render(searchResultList(results))
+}
+
+// searchResultList is the result of search: the matching entries with their snippets
+type searchResultList []SearchResult
+
+func (results searchResultList) writeTable(w io.Writer, config AppConfig) error {
+	if len(results) == 0 {
+		fmt.Fprintln(w, "No matching entries found.")
+		return nil
+	}
+
+	terminal := isTerminal(w)
+	fmt.Fprintf(w, "Found %d matching entries:\n\n", len(results))
+	for _, result := range results {
+		entry := result.WeightEntry
+		entry.Note = result.Snippet
+		if terminal {
+			entry.Note = terminalSnippet(result.Snippet)
+		}
+		printWeightEntry(w, entry, config)
+	}
+	return nil
+}
+
+// runSearch is the wrapper that handles errors for the CLI
+func runSearch(cmd *cobra.Command, args []string) {
+	if err := runSearchInternal(cmd, args); err != nil {
+		exitWithError(cmd, err)
+	}
+}
+
+// isTerminal reports whether w writes to an interactive terminal
+func isTerminal(w io.Writer) bool {
+	file, ok := w.(*os.File)
+	if !ok {
+		return false
+	}
+	info, err := file.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+// terminalSnippet replaces the match markers of a snippet (snippetMatchStart, which is
+// also snippetMatchEnd) with escape codes turning bold text on and off
+func terminalSnippet(snippet string) string {
+	parts := strings.Split(snippet, snippetMatchStart)
+	var highlighted strings.Builder
+	for i, part := range parts {
+		if i > 0 {
+			if i%2 == 1 {
+				highlighted.WriteString("\x1b[1m")
+			} else {
+				highlighted.WriteString("\x1b[0m")
+			}
+		}
+		highlighted.WriteString(part)
+	}
+	if len(parts)%2 == 0 {
+		// Unbalanced markers: do not leave the rest of the terminal bold
+		highlighted.WriteString("\x1b[0m")
+	}
+	return highlighted.String()
+}
@@ -0,0 +1,273 @@
+package tracker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/BlochLior/weight-tracker/internal/db"
+)
+
+// search_test.go - Full-text search tests
+// * purpose: tests note search against the FTS index and the MockStore emulation.
+// * tests: integration (real DB, migration and triggers), MockStore and the search command
+// * focus: query syntax, snippets, combination with list filters and index maintenance.
+
+// seedSearchEntries adds entries with notes to store
+func seedSearchEntries(t *testing.T, store Store) {
+	t.Helper()
+	baseDate := time.Date(2025, 6, 1, 8, 0, 0, 0, time.UTC)
+	notes := []struct {
+		unit string
+		note string
+	}{
+		{unit: "kg", note: "Back from vacation"},
+		{unit: "kg", note: "Holiday dinner"},
+		{unit: "lbs", note: "after workout"},
+		{unit: "kg", note: "sick with flu"},
+		{unit: "kg", note: ""},
+		{unit: "lbs", note: "Travelling for work, skipped workout"},
+	}
+	for i, n := range notes {
+		entry := WeightEntry{Weight: 70 + float64(i), Date: baseDate.AddDate(0, 0, i), Unit: n.unit, Note: n.note}
+		if _, err := store.AddWeight(context.Background(), entry); err != nil {
+			t.Fatal(failedTestEntryAdditionString(err))
+		}
+	}
+}
+
+func TestSearchWeights(t *testing.T) {
+	tests := []struct {
+		name         string
+		options      ListOptions
+		wantIDs      []int64
+		wantSnippets []string
+		wantErr      error
+	}{
+		{
+			name:         "single word, any case",
+			options:      ListOptions{NoteQuery: "VACATION"},
+			wantIDs:      []int64{1},
+			wantSnippets: []string{"Back from **vacation**"},
+		},
+		{
+			name:         "either word",
+			options:      ListOptions{NoteQuery: "vacation OR holiday", SortBy: "date"},
+			wantIDs:      []int64{1, 2},
+			wantSnippets: []string{"Back from **vacation**", "**Holiday** dinner"},
+		},
+		{
+			name:         "phrase",
+			options:      ListOptions{NoteQuery: `"after workout"`},
+			wantIDs:      []int64{3},
+			wantSnippets: []string{"**after** **workout**"},
+		},
+		{
+			name:    "word without another",
+			options: ListOptions{NoteQuery: "workout NOT travelling"},
+			wantIDs: []int64{3},
+		},
+		{
+			name:         "prefix",
+			options:      ListOptions{NoteQuery: "travel*"},
+			wantIDs:      []int64{6},
+			wantSnippets: []string{"**Travelling** for work, skipped workout"},
+		},
+		{
+			name:    "whole words only",
+			options: ListOptions{NoteQuery: "work"},
+			wantIDs: []int64{6},
+		},
+		{
+			name:    "combined with list filters and order",
+			options: ListOptions{NoteQuery: "workout OR vacation OR sick", Unit: "kg", SortBy: "date", SortDesc: true},
+			wantIDs: []int64{4, 1},
+		},
+		{
+			name:    "no match",
+			options: ListOptions{NoteQuery: "marathon"},
+			wantIDs: []int64{},
+		},
+		{
+			name:    "unbalanced quote",
+			options: ListOptions{NoteQuery: `"after workout`},
+			wantErr: ErrInvalidNoteQuery,
+		},
+	}
+
+	stores := map[string]func(t *testing.T) Store{
+		"DBStore": func(t *testing.T) Store {
+			testDB := setupTestDB(t)
+			t.Cleanup(func() { testDB.Close() })
+			return NewDBStoreWithDB(testDB, time.UTC)
+		},
+		"MockStore": func(t *testing.T) Store { return NewMockStore() },
+	}
+
+	for storeName, newStore := range stores {
+		t.Run(storeName, func(t *testing.T) {
+			store := newStore(t)
+			seedSearchEntries(t, store)
+
+			for _, tt := range tests {
+				t.Run(tt.name, func(t *testing.T) {
+					results, err := store.SearchWeights(context.Background(), tt.options)
+					if tt.wantErr != nil {
+						if !errors.Is(err, tt.wantErr) {
+							t.Errorf("SearchWeights() error = %v, want %v", err, tt.wantErr)
+						}
+						return
+					}
+					if err != nil {
+						t.Fatal(unexpectedErrorString(err))
+					}
+
+					ids := make([]int64, len(results))
+					for i, result := range results {
+						ids[i] = result.ID
+					}
+					if fmt.Sprint(ids) != fmt.Sprint(tt.wantIDs) {
+						t.Fatalf("SearchWeights() IDs = %v, want %v", ids, tt.wantIDs)
+					}
+					for i, snippet := range tt.wantSnippets {
+						if results[i].Snippet != snippet {
+							t.Errorf("snippet %d = %q, want %q", i, results[i].Snippet, snippet)
+						}
+					}
+
+					// ListWeights applies the same query without snippets
+					entries, err := store.ListWeights(context.Background(), tt.options)
+					if err != nil {
+						t.Fatal(unexpectedErrorString(err))
+					}
+					if len(entries) != len(results) {
+						t.Errorf("ListWeights() got %d entries, want %d", len(entries), len(results))
+					}
+				})
+			}
+		})
+	}
+}
+
+// TestSearchWeights_IndexMaintenance tests that the triggers keep the index in sync
+// and that the migration indexes notes written before it
+func TestSearchWeights_IndexMaintenance(t *testing.T) {
+	testDB := setupTestDB(t)
+	defer testDB.Close()
+	store := NewDBStoreWithDB(testDB, time.UTC)
+	ctx := context.Background()
+
+	search := func(query string) []int64 {
+		t.Helper()
+		results, err := store.SearchWeights(ctx, ListOptions{NoteQuery: query, SortBy: "date"})
+		if err != nil {
+			t.Fatal(unexpectedErrorString(err))
+		}
+		ids := make([]int64, len(results))
+		for i, result := range results {
+			ids[i] = result.ID
+		}
+		return ids
+	}
+
+	// Entries added before the index existed (rolling back the date conversion too,
+	// which came after it)
+	for range 2 {
+		if _, err := db.Rollback(ctx, testDB, time.Local); err != nil {
+			t.Fatal(unexpectedErrorString(err))
+		}
+	}
+	seedSearchEntries(t, store)
+	if _, err := db.Migrate(ctx, testDB, time.Local); err != nil {
+		t.Fatal(unexpectedErrorString(err))
+	}
+	if got := fmt.Sprint(search("vacation OR holiday")); got != "[1 2]" {
+		t.Errorf("after migration: got %s, want [1 2]", got)
+	}
+
+	// Update replaces the indexed note
+	if _, err := store.UpdateWeight(ctx, WeightEntry{ID: 1, Note: "gym"}); err != nil {
+		t.Fatal(unexpectedErrorString(err))
+	}
+	if got := fmt.Sprint(search("vacation")); got != "[]" {
+		t.Errorf("after update: vacation found in %s, want []", got)
+	}
+	if got := fmt.Sprint(search("gym")); got != "[1]" {
+		t.Errorf("after update: gym found in %s, want [1]", got)
+	}
+
+	// Delete removes the note from the index
+	if err := store.DeleteWeight(ctx, 2); err != nil {
+		t.Fatal(unexpectedErrorString(err))
+	}
+	if got := fmt.Sprint(search("holiday")); got != "[]" {
+		t.Errorf("after delete: holiday found in %s, want []", got)
+	}
+
+	// New entries are indexed
+	entry, err := store.AddWeight(ctx, WeightEntry{Weight: 71, Date: time.Now(), Unit: "kg", Note: "holiday again"})
+	if err != nil {
+		t.Fatal(failedTestEntryAdditionString(err))
+	}
+	if got, want := fmt.Sprint(search("holiday")), fmt.Sprint([]int64{entry.ID}); got != want {
+		t.Errorf("after add: holiday found in %s, want %s", got, want)
+	}
+}
+
+func TestSearchCommand(t *testing.T) {
+	store := NewMockStore()
+	seedSearchEntries(t, store)
+	app, stdout, stderr, exitCode := newTestApp(store, nil)
+
+	result := runCommand(t, app, stdout, stderr, exitCode, "search", "vacation", "OR", "holiday")
+	if result.exitCode != 0 {
+		t.Fatalf("exit code %d, stderr: %s", result.exitCode, result.stderr)
+	}
+	for _, want := range []string{"Found 2 matching entries", "* Note: **Holiday** dinner", "* Note: Back from **vacation**"} {
+		if !strings.Contains(result.stdout, want) {
+			t.Errorf("output is missing %q:\n%s", want, result.stdout)
+		}
+	}
+	// Newest first by default
+	if strings.Index(result.stdout, "Holiday") > strings.Index(result.stdout, "vacation") {
+		t.Errorf("results are not sorted newest first:\n%s", result.stdout)
+	}
+
+	result = runCommand(t, app, stdout, stderr, exitCode, "search", "workout", "--unit", "lbs", "--limit", "1", "-o", "json")
+	if result.exitCode != 0 {
+		t.Fatalf("exit code %d, stderr: %s", result.exitCode, result.stderr)
+	}
+	if !strings.Contains(result.stdout, `"snippet": "Travelling for work, skipped **workout**"`) {
+		t.Errorf("JSON output is missing the snippet:\n%s", result.stdout)
+	}
+
+	result = runCommand(t, app, stdout, stderr, exitCode, "search", "marathon")
+	if !strings.Contains(result.stdout, "No matching entries found.") {
+		t.Errorf("output = %q, want no matches", result.stdout)
+	}
+
+	result = runCommand(t, app, stdout, stderr, exitCode, "search", `"unbalanced`)
+	if result.exitCode != 1 || !strings.Contains(result.stderr, "invalid search query") {
+		t.Errorf("exit code %d, stderr %q, want an invalid query error", result.exitCode, result.stderr)
+	}
+}
+
+func TestTerminalSnippet(t *testing.T) {
+	tests := []struct {
+		snippet string
+		want    string
+	}{
+		{snippet: "no match", want: "no match"},
+		{snippet: "Back from **vacation**", want: "Back from \x1b[1mvacation\x1b[0m"},
+		{snippet: "**after** **workout**", want: "\x1b[1mafter\x1b[0m \x1b[1mworkout\x1b[0m"},
+		{snippet: "unbalanced **marker", want: "unbalanced \x1b[1mmarker\x1b[0m"},
+	}
+	for _, tt := range tests {
+		if got := terminalSnippet(tt.snippet); got != tt.want {
+			t.Errorf("terminalSnippet(%q) = %q, want %q", tt.snippet, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,141 @@
+package tracker
+
+// serve.go - Serve command running the local REST API
+// Related files: api.go (routes and handlers), dashboard.go (--ui), api_test.go (tests)
+// The server listens on localhost by default and shuts down gracefully on Ctrl+C.
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// serveShutdownTimeout bounds how long in-flight requests may take on shutdown
+const serveShutdownTimeout = 5 * time.Second
+
+var serveAddr string
+var serveUI bool
+
+var serveCmd = &cobra.Command{
+	Use:   "serve",
+	Short: "Serve weight entries over a local JSON REST API",
+	Long: `Serve weight entries over a local JSON REST API so other tools can read and
+write them without shelling out to the CLI.
+
+Routes:
+  POST   /entries        Add an entry (JSON body: weight, date, unit, note, user_id)
+  GET    /entries        List entries (query: from, to, limit, sort, desc, unit, user, display_unit)
+  GET    /entries/{id}   Get an entry
+  PUT    /entries/{id}   Replace an entry (weight and date required)
+  PATCH  /entries/{id}   Update some fields of an entry
+  DELETE /entries/{id}   Delete an entry
+  GET    /stats          Statistics (query: from, to, user, display_unit, trend, trend_window)
+
+With --ui the server also serves a web dashboard at / from files embedded in the
+binary: a live chart with trend and goal overlays, a filterable entry table and a
+form to add entries. The dashboard refreshes itself, so entries added from the CLI
+show up without regenerating chart files. Charts load the ECharts library from the
+go-echarts CDN, so the browser needs internet access to draw them.
+
+Dates are accepted as RFC 3339, yyyy-mm-dd, yyyy-mm-dd hh:mm:ss or the configured
+DATE_INPUT_FORMAT. Errors are returned as {"error": "..."} with a matching status code.
+
+Examples:
+  weight-tracker serve                       # Listen on 127.0.0.1:8080
+  weight-tracker serve --addr :9000          # Listen on all interfaces, port 9000
+  weight-tracker serve --ui                  # Also serve the dashboard at http://127.0.0.1:8080/
+  curl -X POST localhost:8080/entries -d '{"weight": 75.5, "unit": "kg"}'
+  curl 'localhost:8080/entries?from=2025-01-01&limit=10'`,
+	Run: runServe,
+}
+
+func init() {
+	serveCmd.Flags().StringVar(&serveAddr, "addr", "127.0.0.1:8080", "Address to listen on")
+	serveCmd.Flags().BoolVar(&serveUI, "ui", false, "Also serve the web dashboard at /")
+}
+
+// runServeInternal contains the core logic and returns errors instead of terminating
+func runServeInternal(cmd *cobra.Command, args []string) error {
+	_ = args
+
+	app := appFor(cmd)
+	store, err := app.NewStore()
+	if err != nil {
+		return fmt.Errorf("could not create store: %w", err)
+	}
+	defer store.Close()
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	handler := NewAPIHandler(app, store)
+	if serveUI {
+		handler = NewDashboardHandler(app, store)
+	}
+
+	logger := log.New(cmd.ErrOrStderr(), "", log.LstdFlags)
+	server := &http.Server{
+		Addr:              serveAddr,
+		Handler:           logRequests(logger, handler),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- server.ListenAndServe()
+	}()
+	logger.Printf("Serving weight-tracker API on http://%s (Ctrl+C to stop)", serveAddr)
+	if serveUI {
+		logger.Printf("Dashboard available at http://%s/", serveAddr)
+	}
+
+	select {
+	case err := <-errCh:
+		return fmt.Errorf("server failed: %w", err)
+	case <-ctx.Done():
+	}
+
+	logger.Printf("Shutting down")
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), serveShutdownTimeout)
+	defer cancel()
+	if err := server.Shutdown(shutdownCtx); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return fmt.Errorf("failed to shut down server: %w", err)
+	}
+	return nil
+}
+
+// runServe is the wrapper that handles errors for the CLI
+func runServe(cmd *cobra.Command, args []string) {
+	if err := runServeInternal(cmd, args); err != nil {
+		exitWithError(cmd, err)
+	}
+}
+
+// statusRecorder captures the status code written by a handler
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (r *statusRecorder) WriteHeader(status int) {
+	r.status = status
+	r.ResponseWriter.WriteHeader(status)
+}
+
+// logRequests logs the method, path, status and duration of every request
+func logRequests(logger *log.Logger, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		recorder := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+		next.ServeHTTP(recorder, r)
+		logger.Printf("%s %s %d %s", r.Method, r.URL.RequestURI(), recorder.status, time.Since(start).Round(time.Millisecond))
+	})
+}
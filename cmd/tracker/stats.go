@@ -2,13 +2,18 @@ package tracker
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"io"
 	"time"
 
 	"github.com/spf13/cobra"
 )
 
 var verboseStats bool
+var statsDisplayUnit string
+var statsFromDate string
+var statsToDate string
 
 // statsCmd represents the stats command
 var statsCmd = &cobra.Command{
@@ -20,64 +25,147 @@ var statsCmd = &cobra.Command{
 - Total number of entries
 - Time span from first to last entry
 - Weight range (max - min)
+- Progress towards the goal weight and projected completion date (see 'goal')
+
+Use --trend to smooth out day-to-day fluctuations: the trend weight is computed
+with an exponentially weighted moving average (--trend-method ema, the Hacker's
+Diet trend), a simple moving average over --trend-window days (sma) or a linear
+regression (linear), and the rate of change per week is reported for all methods.
+Use --from and --to to limit statistics and trend to a date window.
+
+Use --output json or --output yaml for machine-readable statistics.
 
 Use --verbose to show full entry details instead of just entry IDs.
+Entries recorded in different units are converted to a single display unit
+(--display-unit, falling back to DEFAULT_UNIT) before any statistic is computed.
 
 Examples:
   weight-tracker stats                    # Show basic statistics
-  weight-tracker stats --verbose          # Show detailed statistics with full entry info`,
+  weight-tracker stats --verbose          # Show detailed statistics with full entry info
+  weight-tracker stats --display-unit lbs # Show statistics in pounds
+  weight-tracker stats --trend            # Add the EMA trend and weekly rate of change
+  weight-tracker stats --trend --trend-method sma --trend-window 14
+  weight-tracker stats --trend --from 01-01-2025 --to 31-03-2025`,
 	Run: runStats,
 }
 
 func init() {
 	rootCmd.AddCommand(statsCmd)
 	statsCmd.Flags().BoolVarP(&verboseStats, "verbose", "v", false, "Show full entry details instead of just IDs")
+	statsCmd.Flags().StringVarP(&statsDisplayUnit, "display-unit", "", "", "Unit to compute and display statistics in (kg, lbs) - default configurable via DEFAULT_UNIT")
+	statsCmd.Flags().StringVarP(&statsFromDate, "from", "f", "", "Start date of the statistics window (DATE_INPUT_FORMAT or relative: today, -7d, last monday, this month, 2025-W10)")
+	statsCmd.Flags().StringVarP(&statsToDate, "to", "t", "", "End date of the statistics window (DATE_INPUT_FORMAT or relative: today, -7d, last monday, this month, 2025-W10)")
+	addTrendFlags(statsCmd, "Show the smoothed trend weight and rate of change")
 }
 
-func runStats(cmd *cobra.Command, args []string) {
+// runStatsInternal contains the core logic and returns errors instead of terminating
+func runStatsInternal(cmd *cobra.Command, args []string) error {
 	// Note: args are not used for stats command as all options are handled via flags
 	_ = args
 
+	renderer, err := rendererFor(cmd)
+	if err != nil {
+		return err
+	}
+	app := appFor(cmd)
+
+	// Resolve the unit statistics are computed in
+	displayUnitValue, _ := cmd.Flags().GetString("display-unit")
+	targetUnit, err := resolveDisplayUnitFromEnv(displayUnitValue, app.Getenv)
+	if err != nil {
+		return err
+	}
+
+	trendOptions, err := trendOptionsFromFlags(cmd)
+	if err != nil {
+		return err
+	}
+
+	fromDate, toDate, err := parseDateRangeFlags(cmd)
+	if err != nil {
+		return err
+	}
+
 	// Create store
-	store, err := NewDBStore()
+	store, err := app.NewStore()
 	if err != nil {
-		fmt.Printf("Error creating store: %v\n", err)
-		return
+		return fmt.Errorf("could not create store: %w", err)
 	}
 	defer store.Close()
 
-	// Get all weight entries
-	entries, err := store.ListWeights(context.Background(), ListOptions{})
+	// Get all weight entries in the selected window
+	options := ListOptions{
+		FromDate: fromDate,
+		ToDate:   toDate,
+		UserID:   resolveUser(cmd),
+	}
+	stats, err := buildStatistics(context.Background(), store, options, targetUnit, trendOptions)
 	if err != nil {
-		fmt.Printf("Error retrieving weight entries: %v\n", err)
-		return
+		return err
 	}
 
-	if len(entries) == 0 {
-		fmt.Println("No weight entries found.")
-		return
+	verbose, _ := cmd.Flags().GetBool("verbose")
+	return renderer.Render(statisticsReport{stats: stats, verbose: verbose})
+}
+
+// buildStatistics lists the entries selected by options and computes their statistics
+// in unit, with the trend (when trendOptions has a method) and the user's goal progress.
+// An empty history yields zero entries rather than an error.
+func buildStatistics(ctx context.Context, store Store, options ListOptions, unit string, trendOptions TrendOptions) (WeightStatistics, error) {
+	entries, err := store.ListWeights(ctx, options)
+	if err != nil {
+		return WeightStatistics{}, fmt.Errorf("failed to retrieve weight entries: %w", err)
+	}
+
+	// Normalize mixed kg/lbs histories before aggregating
+	entries, err = NormalizeEntries(entries, unit)
+	if err != nil {
+		return WeightStatistics{}, fmt.Errorf("failed to convert weights to %s: %w", unit, err)
 	}
 
-	// Calculate statistics
 	stats := calculateStatistics(entries)
+	if trendOptions.Method != "" {
+		stats.Trend, err = calculateTrend(entries, trendOptions)
+		if err != nil {
+			return WeightStatistics{}, fmt.Errorf("failed to calculate trend: %w", err)
+		}
+	}
+
+	// Report progress towards the user's goal, if one is set
+	stats.Goal, err = loadGoalProgress(ctx, store, options.UserID, unit, entries)
+	if err != nil {
+		return WeightStatistics{}, fmt.Errorf("failed to calculate goal progress: %w", err)
+	}
 
-	// Display statistics
-	displayStatistics(stats, verboseStats)
+	return stats, nil
+}
+
+// runStats is the cobra command wrapper that handles errors appropriately for CLI usage
+func runStats(cmd *cobra.Command, args []string) {
+	if err := runStatsInternal(cmd, args); err != nil {
+		exitWithError(cmd, err)
+	}
 }
 
 type WeightStatistics struct {
-	MinWeight      float64
-	MinWeightEntry WeightEntry
-	MaxWeight      float64
-	MaxWeightEntry WeightEntry
-	AverageWeight  float64
-	TotalEntries   int
-	TimeSpan       time.Duration
-	WeightRange    float64
-	FirstEntry     WeightEntry
-	LastEntry      WeightEntry
+	MinWeight      float64       `json:"min_weight" yaml:"min_weight"`
+	MinWeightEntry WeightEntry   `json:"min_weight_entry" yaml:"min_weight_entry"`
+	MaxWeight      float64       `json:"max_weight" yaml:"max_weight"`
+	MaxWeightEntry WeightEntry   `json:"max_weight_entry" yaml:"max_weight_entry"`
+	AverageWeight  float64       `json:"average_weight" yaml:"average_weight"`
+	TotalEntries   int           `json:"total_entries" yaml:"total_entries"`
+	TimeSpan       time.Duration `json:"-" yaml:"-"`
+	TimeSpanDays   int           `json:"time_span_days" yaml:"time_span_days"` // TimeSpan in whole days
+	WeightRange    float64       `json:"weight_range" yaml:"weight_range"`
+	FirstEntry     WeightEntry   `json:"first_entry" yaml:"first_entry"`
+	LastEntry      WeightEntry   `json:"last_entry" yaml:"last_entry"`
+	Unit           string        `json:"unit" yaml:"unit"`                       // Unit all weights are expressed in
+	Trend          *TrendSummary `json:"trend,omitempty" yaml:"trend,omitempty"` // Set with --trend
+	Goal           *GoalProgress `json:"goal,omitempty" yaml:"goal,omitempty"`   // Set when a goal exists
 }
 
+// calculateStatistics aggregates entries that are expected to share a single unit
+// (see NormalizeEntries); the unit of the first entry is reported as the statistics unit
 func calculateStatistics(entries []WeightEntry) WeightStatistics {
 	if len(entries) == 0 {
 		return WeightStatistics{}
@@ -135,63 +223,127 @@ func calculateStatistics(entries []WeightEntry) WeightStatistics {
 		AverageWeight:  averageWeight,
 		TotalEntries:   len(entries),
 		TimeSpan:       timeSpan,
+		TimeSpanDays:   int(timeSpan.Hours() / 24),
 		WeightRange:    weightRange,
 		FirstEntry:     firstEntry,
 		LastEntry:      lastEntry,
+		Unit:           entries[0].Unit,
+	}
+}
+
+// statisticsReport is the result of stats. Tables show the details of the minimum,
+// maximum, first and last entries when verbose; JSON and YAML always carry them.
+type statisticsReport struct {
+	stats   WeightStatistics
+	verbose bool
+}
+
+func (report statisticsReport) writeTable(w io.Writer, config AppConfig) error {
+	if report.stats.TotalEntries == 0 {
+		fmt.Fprintln(w, "No weight entries found.")
+		return nil
 	}
+	writeStatistics(w, report.stats, report.verbose, config)
+	return nil
+}
+
+// MarshalJSON encodes the statistics themselves
+func (report statisticsReport) MarshalJSON() ([]byte, error) {
+	return json.Marshal(report.stats)
 }
 
-func displayStatistics(stats WeightStatistics, verbose bool) {
-	fmt.Println("Weight Tracking Statistics")
-	fmt.Println("=========================")
+// MarshalYAML encodes the statistics themselves
+func (report statisticsReport) MarshalYAML() (any, error) {
+	return report.stats, nil
+}
+
+// writeStatistics writes the human-readable statistics report to w, with the dates
+// of the goal section formatted as configured in config
+func writeStatistics(w io.Writer, stats WeightStatistics, verbose bool, config AppConfig) {
+	unit := stats.Unit
+	if unit == "" {
+		unit = UnitKg
+	}
+
+	fmt.Fprintln(w, "Weight Tracking Statistics")
+	fmt.Fprintln(w, "=========================")
 
 	// Total entries
-	fmt.Printf("Total Entries: %d\n", stats.TotalEntries)
+	fmt.Fprintf(w, "Total Entries: %d\n", stats.TotalEntries)
 
 	// Average weight
-	fmt.Printf("Average Weight: %.2f kg\n", stats.AverageWeight)
+	fmt.Fprintf(w, "Average Weight: %.2f %s\n", stats.AverageWeight, unit)
 
 	// Weight range
-	fmt.Printf("Weight Range: %.2f kg (%.2f - %.2f)\n",
-		stats.WeightRange, stats.MinWeight, stats.MaxWeight)
+	fmt.Fprintf(w, "Weight Range: %.2f %s (%.2f - %.2f)\n",
+		stats.WeightRange, unit, stats.MinWeight, stats.MaxWeight)
 
 	// Min weight
-	fmt.Printf("\nMinimum Weight: %.2f kg", stats.MinWeight)
+	fmt.Fprintf(w, "\nMinimum Weight: %.2f %s", stats.MinWeight, unit)
 	if verbose {
-		fmt.Printf("\n  Entry: ID=%d, Date=%s, Weight=%.2f kg, Note=%s\n",
+		fmt.Fprintf(w, "\n  Entry: ID=%d, Date=%s, Weight=%.2f %s, Note=%s\n",
 			stats.MinWeightEntry.ID,
 			stats.MinWeightEntry.Date.Format("2006-01-02"),
 			stats.MinWeightEntry.Weight,
+			unit,
 			stats.MinWeightEntry.Note)
 	} else {
-		fmt.Printf(" (Entry ID: %d)\n", stats.MinWeightEntry.ID)
+		fmt.Fprintf(w, " (Entry ID: %d)\n", stats.MinWeightEntry.ID)
 	}
 
 	// Max weight
-	fmt.Printf("Maximum Weight: %.2f kg", stats.MaxWeight)
+	fmt.Fprintf(w, "Maximum Weight: %.2f %s", stats.MaxWeight, unit)
 	if verbose {
-		fmt.Printf("\n  Entry: ID=%d, Date=%s, Weight=%.2f kg, Note=%s\n",
+		fmt.Fprintf(w, "\n  Entry: ID=%d, Date=%s, Weight=%.2f %s, Note=%s\n",
 			stats.MaxWeightEntry.ID,
 			stats.MaxWeightEntry.Date.Format("2006-01-02"),
 			stats.MaxWeightEntry.Weight,
+			unit,
 			stats.MaxWeightEntry.Note)
 	} else {
-		fmt.Printf(" (Entry ID: %d)\n", stats.MaxWeightEntry.ID)
+		fmt.Fprintf(w, " (Entry ID: %d)\n", stats.MaxWeightEntry.ID)
 	}
 
 	// Time span
 	if stats.TimeSpan > 0 {
 		days := int(stats.TimeSpan.Hours() / 24)
-		fmt.Printf("\nTime Span: %d days", days)
+		fmt.Fprintf(w, "\nTime Span: %d days", days)
 		if verbose {
-			fmt.Printf("\n  From: %s (Entry ID: %d)\n  To: %s (Entry ID: %d)\n",
+			fmt.Fprintf(w, "\n  From: %s (Entry ID: %d)\n  To: %s (Entry ID: %d)\n",
 				stats.FirstEntry.Date.Format("2006-01-02"), stats.FirstEntry.ID,
 				stats.LastEntry.Date.Format("2006-01-02"), stats.LastEntry.ID)
 		} else {
-			fmt.Printf(" (from Entry ID: %d to Entry ID: %d)\n",
+			fmt.Fprintf(w, " (from Entry ID: %d to Entry ID: %d)\n",
 				stats.FirstEntry.ID, stats.LastEntry.ID)
 		}
 	} else {
-		fmt.Println("\nTime Span: Unable to calculate (insufficient valid dates)")
+		fmt.Fprintln(w, "\nTime Span: Unable to calculate (insufficient valid dates)")
+	}
+
+	// Trend
+	if stats.Trend != nil {
+		writeTrend(w, *stats.Trend, unit, verbose)
+	}
+
+	// Goal
+	if stats.Goal != nil {
+		writeGoalProgress(w, *stats.Goal, config)
+	}
+}
+
+// writeTrend writes the trend section of the statistics report
+func writeTrend(w io.Writer, trend TrendSummary, unit string, verbose bool) {
+	fmt.Fprintf(w, "\n%s: %.2f %s\n",
+		trendLabel(TrendOptions{Method: trend.Method, WindowDays: trend.WindowDays}), trend.Current, unit)
+	if trend.HasRegression {
+		fmt.Fprintf(w, "Rate of Change: %+.2f %s/week (R² %.2f)\n", trend.RatePerWeek, unit, trend.RSquared)
+	} else {
+		fmt.Fprintln(w, "Rate of Change: Unable to calculate (insufficient valid dates)")
+	}
+
+	if verbose {
+		for _, point := range trend.Points {
+			fmt.Fprintf(w, "  %s: %.2f %s\n", point.Date.Format("2006-01-02 15:04"), point.Weight, unit)
+		}
 	}
 }
@@ -2,6 +2,7 @@ package tracker
 
 import (
 	"context"
+	"strings"
 	"testing"
 	"time"
 )
@@ -149,9 +150,7 @@ func TestCalculateStatistics(t *testing.T) {
 	}
 }
 
-func TestDisplayStatistics(t *testing.T) {
-	// This test mainly ensures the function doesn't panic
-	// The actual output formatting is harder to test without capturing stdout
+func TestRenderStatistics(t *testing.T) {
 	baseDate := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
 
 	stats := WeightStatistics{
@@ -166,16 +165,46 @@ func TestDisplayStatistics(t *testing.T) {
 		FirstEntry:     WeightEntry{ID: 1, Weight: 70.0, Date: baseDate, Unit: "kg", Note: "first"},
 		LastEntry:      WeightEntry{ID: 2, Weight: 80.0, Date: baseDate.AddDate(0, 0, 14), Unit: "kg", Note: "last"}, // Jan 15
 	}
+	zeroSpan := stats
+	zeroSpan.TimeSpan = 0
 
-	// Test non-verbose mode
-	displayStatistics(stats, false)
-
-	// Test verbose mode
-	displayStatistics(stats, true)
+	tests := []struct {
+		name    string
+		stats   WeightStatistics
+		verbose bool
+		want    []string
+	}{
+		{
+			name:  "summary",
+			stats: stats,
+			want:  []string{"Total Entries: 2", "Average Weight: 75.00 kg", "Minimum Weight: 70.00 kg (Entry ID: 1)", "Time Span: 14 days (from Entry ID: 1 to Entry ID: 2)"},
+		},
+		{
+			name:    "verbose",
+			stats:   stats,
+			verbose: true,
+			want:    []string{"Entry: ID=2, Date=2024-01-15, Weight=80.00 kg, Note=max", "To: 2024-01-15 (Entry ID: 2)"},
+		},
+		{
+			name:  "zero time span",
+			stats: zeroSpan,
+			want:  []string{"Time Span: Unable to calculate"},
+		},
+	}
 
-	// Test with zero time span
-	stats.TimeSpan = 0
-	displayStatistics(stats, false)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			renderer, out, _ := newTestRenderer(t, OutputFormatTable)
+			if err := renderer.Render(statisticsReport{stats: tt.stats, verbose: tt.verbose}); err != nil {
+				t.Fatal(unexpectedErrorString(err))
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(out.String(), want) {
+					t.Errorf("statistics output does not contain %q:\n%s", want, out.String())
+				}
+			}
+		})
+	}
 }
 
 func TestStatsCommandIntegration(t *testing.T) {
@@ -3,7 +3,9 @@ package tracker
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/BlochLior/weight-tracker/internal/db"
@@ -13,22 +15,74 @@ import (
 // WeightEntry represents a weight entry in our application domain
 // This is the struct that commands work with for validation and business logic
 type WeightEntry struct {
-	ID     int64     `json:"id"`
-	Weight float64   `json:"weight"`
-	Date   time.Time `json:"date"`
-	Unit   string    `json:"unit"`
-	Note   string    `json:"note"`
-	UserID string    `json:"user_id"`
+	ID     int64     `json:"id" yaml:"id"`
+	Weight float64   `json:"weight" yaml:"weight"`
+	Date   time.Time `json:"date" yaml:"date"`
+	Unit   string    `json:"unit" yaml:"unit"`
+	Note   string    `json:"note" yaml:"note"`
+	UserID string    `json:"user_id" yaml:"user_id"`
+}
+
+// SearchResult is a weight entry whose note matches a full-text query
+type SearchResult struct {
+	WeightEntry `yaml:",inline"`
+	Snippet     string `json:"snippet" yaml:"snippet"` // Excerpt of the note with the matches wrapped in **
 }
 
 // ListOptions represents filtering and sorting options for listing weight entries
 type ListOptions struct {
-	FromDate *time.Time `json:"from_date,omitempty"`
-	ToDate   *time.Time `json:"to_date,omitempty"`
-	Limit    int        `json:"limit,omitempty"`
-	SortBy   string     `json:"sort_by,omitempty"` // "date" or "weight"
-	SortDesc bool       `json:"sort_desc,omitempty"`
-	Unit     string     `json:"unit,omitempty"`
+	FromDate  *time.Time `json:"from_date,omitempty"`
+	ToDate    *time.Time `json:"to_date,omitempty"`
+	Limit     int        `json:"limit,omitempty"`
+	SortBy    string     `json:"sort_by,omitempty"` // Single sort field (see sortFields), used when Sort is empty
+	SortDesc  bool       `json:"sort_desc,omitempty"`
+	Sort      []SortKey  `json:"sort,omitempty"` // Compound order, e.g. weight descending then date
+	Unit      string     `json:"unit,omitempty"`
+	UserID    string     `json:"user_id,omitempty"`    // Empty lists entries of all users
+	Note      string     `json:"note,omitempty"`       // Case-insensitive substring of the note
+	NoteQuery string     `json:"note_query,omitempty"` // Full-text query over notes, e.g. "vacation OR holiday"
+	Offset    int        `json:"offset,omitempty"`     // Number of matching entries to skip
+	AfterID   int64      `json:"after_id,omitempty"`   // Cursor: list only entries sorting after this entry
+}
+
+// User represents a person whose weight is tracked in a shared database
+type User struct {
+	ID        string    `json:"id" yaml:"id"`
+	CreatedAt time.Time `json:"created_at" yaml:"created_at"`
+}
+
+// Goal represents a target weight, optionally to be reached by a target date.
+// Each user has at most one goal; UserID is empty for the goal used when no user is selected.
+type Goal struct {
+	ID           int64      `json:"id" yaml:"id"`
+	UserID       string     `json:"user_id" yaml:"user_id"`
+	TargetWeight float64    `json:"target_weight" yaml:"target_weight"`
+	Unit         string     `json:"unit" yaml:"unit"`
+	TargetDate   *time.Time `json:"target_date,omitempty" yaml:"target_date,omitempty"`
+	StartWeight  float64    `json:"start_weight" yaml:"start_weight"` // Weight when the goal was set, in Unit
+	CreatedAt    time.Time  `json:"created_at" yaml:"created_at"`
+}
+
+// ErrGoalNotFound is returned when no goal is set for a user
+var ErrGoalNotFound = errors.New("no goal set")
+
+// ErrInvalidNoteQuery matches (via errors.Is) the error returned for a full-text query that cannot be parsed
+var ErrInvalidNoteQuery = errors.New("invalid search query")
+
+// ErrEntryNotFound matches (via errors.Is) the error returned when no weight entry has the requested ID
+var ErrEntryNotFound = errors.New("weight entry not found")
+
+// entryNotFoundError reports a missing weight entry by ID
+type entryNotFoundError struct {
+	id int64
+}
+
+func (e entryNotFoundError) Error() string {
+	return fmt.Sprintf("weight entry with id %d not found", e.id)
+}
+
+func (e entryNotFoundError) Is(target error) bool {
+	return target == ErrEntryNotFound
 }
 
 // Store defines the contract for weight entry storage operations
@@ -37,9 +91,17 @@ type Store interface {
 	// AddWeight adds a new weight entry to the store
 	AddWeight(ctx context.Context, entry WeightEntry) (WeightEntry, error)
 
+	// AddWeights adds several weight entries at once: either all of them are added or,
+	// on error, none are
+	AddWeights(ctx context.Context, entries []WeightEntry) ([]WeightEntry, error)
+
 	// ListWeights retrieves weight entries based on the provided options
 	ListWeights(ctx context.Context, options ListOptions) ([]WeightEntry, error)
 
+	// SearchWeights retrieves the entries whose note matches options.NoteQuery, with
+	// the matching part of each note; the other options apply as in ListWeights
+	SearchWeights(ctx context.Context, options ListOptions) ([]SearchResult, error)
+
 	// DeleteWeight removes a weight entry by ID
 	DeleteWeight(ctx context.Context, id int64) error
 
@@ -48,17 +110,42 @@ type Store interface {
 
 	// UpdateWeight updates an existing weight entry
 	UpdateWeight(ctx context.Context, entry WeightEntry) (WeightEntry, error)
+
+	// ReplaceWeight overwrites every field of an existing weight entry, so that
+	// empty fields such as the note are cleared rather than kept
+	ReplaceWeight(ctx context.Context, entry WeightEntry) (WeightEntry, error)
+
+	// ListUsers retrieves all users known to the store
+	ListUsers(ctx context.Context) ([]User, error)
+
+	// SetGoal creates or replaces the goal of goal.UserID
+	SetGoal(ctx context.Context, goal Goal) (Goal, error)
+
+	// GetGoal retrieves the goal of a user (ErrGoalNotFound when none is set)
+	GetGoal(ctx context.Context, userID string) (Goal, error)
+
+	// ListGoals retrieves the goals of all users
+	ListGoals(ctx context.Context) ([]Goal, error)
+
+	// DeleteGoal removes the goal of a user (ErrGoalNotFound when none is set)
+	DeleteGoal(ctx context.Context, userID string) error
+
+	// Close releases the resources held by the store
+	Close() error
 }
 
 // DBStore is the concrete implementation of Store that uses SQLite and sqlc
 type DBStore struct {
-	db      *sql.DB
-	queries *sqlc.Queries
+	db       *sql.DB
+	queries  *sqlc.Queries
+	location *time.Location // Time zone that dates are returned in
 }
 
-// NewDBStore creates a new DBStore instance
-func NewDBStore() (*DBStore, error) {
-	db, err := db.OpenDB()
+// NewDBStore creates a new DBStore instance for the SQLite database at path,
+// creating the database and applying pending migrations as needed. Dates are
+// returned in location, which is also the zone of dates stored without one.
+func NewDBStore(path string, location *time.Location) (*DBStore, error) {
+	db, err := db.OpenDB(path, location)
 	if err != nil {
 		return nil, fmt.Errorf("failed to open database: %w", err)
 	}
@@ -66,18 +153,20 @@ func NewDBStore() (*DBStore, error) {
 	queries := sqlc.New(db)
 
 	return &DBStore{
-		db:      db,
-		queries: queries,
+		db:       db,
+		queries:  queries,
+		location: location,
 	}, nil
 }
 
-// NewDBStoreWithDB creates a new DBStore instance with an existing database connection
-// This is useful for testing with in-memory databases
-func NewDBStoreWithDB(database *sql.DB) *DBStore {
+// NewDBStoreWithDB creates a new DBStore instance with an existing database connection,
+// returning dates in location. This is useful for testing with in-memory databases
+func NewDBStoreWithDB(database *sql.DB, location *time.Location) *DBStore {
 	queries := sqlc.New(database)
 	return &DBStore{
-		db:      database,
-		queries: queries,
+		db:       database,
+		queries:  queries,
+		location: location,
 	}
 }
 
@@ -88,112 +177,156 @@ func (s *DBStore) Close() error {
 
 // AddWeight adds a new weight entry to the database
 func (s *DBStore) AddWeight(ctx context.Context, entry WeightEntry) (WeightEntry, error) {
-	// Convert WeightEntry to sqlc format
-	params := sqlc.AddWeightParams{
-		Weight: entry.Weight,
-		Date:   sql.NullString{String: FormatDateForDB(entry.Date), Valid: true},
-		Unit:   sql.NullString{String: entry.Unit, Valid: entry.Unit != ""},
-		Note:   sql.NullString{String: entry.Note, Valid: entry.Note != ""},
+	added, err := s.AddWeights(ctx, []WeightEntry{entry})
+	if err != nil {
+		return WeightEntry{}, err
 	}
+	return added[0], nil
+}
 
-	// Call sqlc method
-	sqlcEntry, err := s.queries.AddWeight(ctx, params)
+// AddWeights adds weight entries to the database in a single transaction, so that
+// either all of them are added or none are
+func (s *DBStore) AddWeights(ctx context.Context, entries []WeightEntry) ([]WeightEntry, error) {
+	// Register the users and add the entries atomically
+	tx, err := s.db.BeginTx(ctx, nil)
 	if err != nil {
-		return WeightEntry{}, fmt.Errorf("failed to add weight entry: %w", err)
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	queries := s.queries.WithTx(tx)
+	added := make([]WeightEntry, 0, len(entries))
+	for _, entry := range entries {
+		// Convert WeightEntry to sqlc format
+		params := sqlc.AddWeightParams{
+			Weight: entry.Weight,
+			Date:   sql.NullString{String: FormatDateForDB(entry.Date), Valid: true},
+			Unit:   sql.NullString{String: entry.Unit, Valid: entry.Unit != ""},
+			Note:   sql.NullString{String: entry.Note, Valid: entry.Note != ""},
+			UserID: sql.NullString{String: entry.UserID, Valid: entry.UserID != ""},
+		}
+
+		if entry.UserID != "" {
+			if err := queries.EnsureUser(ctx, entry.UserID); err != nil {
+				return nil, fmt.Errorf("failed to register user '%s': %w", entry.UserID, err)
+			}
+		}
+
+		// Call sqlc method
+		sqlcEntry, err := queries.AddWeight(ctx, params)
+		if err != nil {
+			return nil, fmt.Errorf("failed to add weight entry: %w", err)
+		}
+		// Convert back to WeightEntry
+		added = append(added, s.sqlcToWeightEntry(sqlcEntry))
 	}
 
-	// Convert back to WeightEntry
-	return s.sqlcToWeightEntry(sqlcEntry), nil
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit weight entries: %w", err)
+	}
+	return added, nil
 }
 
-// ListWeights retrieves weight entries based on the provided options
+// ListWeights retrieves weight entries based on the provided options. Every filter,
+// the sort order, the offset and the cursor are applied in SQL (see buildListQuery),
+// so Limit always counts matching entries.
 func (s *DBStore) ListWeights(ctx context.Context, options ListOptions) ([]WeightEntry, error) {
-	// Set defaults
-	if options.SortBy == "" {
-		options.SortBy = "date"
+	if options.Offset < 0 {
+		return nil, fmt.Errorf("invalid offset: %d", options.Offset)
 	}
-	if options.Limit == 0 {
-		options.Limit = -1 // SQLite treats -1 as no limit
+	if options.AfterID != 0 {
+		// The cursor entry supplies the sort keys to continue from, so it must exist
+		if _, err := s.GetWeight(ctx, options.AfterID); err != nil {
+			return nil, fmt.Errorf("invalid cursor: %w", err)
+		}
 	}
 
-	// Convert date filters to interface{} (as expected by sqlc)
-	var startDate, endDate interface{}
-	if options.FromDate != nil {
-		startDate = FormatDateForDB(*options.FromDate)
-	}
-	if options.ToDate != nil {
-		endDate = FormatDateForDB(*options.ToDate)
+	query, args, err := buildListQuery(options)
+	if err != nil {
+		return nil, err
 	}
 
-	// Call appropriate sqlc method based on sort options
-	var sqlcEntries []sqlc.Weight
-	var err error
-
-	switch {
-	case options.SortBy == "date" && options.SortDesc:
-		params := sqlc.ListWeightsDateDescParams{
-			StartDate: startDate,
-			EndDate:   endDate,
-			RowLimit:  int64(options.Limit),
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		if queryErr := noteQueryError(options.NoteQuery, err); queryErr != nil {
+			return nil, queryErr
 		}
-		sqlcEntries, err = s.queries.ListWeightsDateDesc(ctx, params)
+		return nil, fmt.Errorf("failed to list weight entries: %w", err)
+	}
+	defer rows.Close()
 
-	case options.SortBy == "date" && !options.SortDesc:
-		params := sqlc.ListWeightsDateAscParams{
-			StartDate: startDate,
-			EndDate:   endDate,
-			RowLimit:  int64(options.Limit),
+	// Scan into sqlc.Weight to share the conversion of the generated queries
+	entries := make([]WeightEntry, 0)
+	for rows.Next() {
+		var row sqlc.Weight
+		if err := rows.Scan(&row.ID, &row.Weight, &row.Date, &row.Unit, &row.Note, &row.UserID); err != nil {
+			return nil, fmt.Errorf("failed to read weight entry: %w", err)
 		}
-		sqlcEntries, err = s.queries.ListWeightsDateAsc(ctx, params)
-
-	case options.SortBy == "weight" && options.SortDesc:
-		params := sqlc.ListWeightsWeightDescParams{
-			StartDate: startDate,
-			EndDate:   endDate,
-			RowLimit:  int64(options.Limit),
+		entries = append(entries, s.sqlcToWeightEntry(row))
+	}
+	if err := rows.Err(); err != nil {
+		if queryErr := noteQueryError(options.NoteQuery, err); queryErr != nil {
+			return nil, queryErr
 		}
-		sqlcEntries, err = s.queries.ListWeightsWeightDesc(ctx, params)
+		return nil, fmt.Errorf("failed to list weight entries: %w", err)
+	}
 
-	case options.SortBy == "weight" && !options.SortDesc:
-		params := sqlc.ListWeightsWeightAscParams{
-			StartDate: startDate,
-			EndDate:   endDate,
-			RowLimit:  int64(options.Limit),
-		}
-		sqlcEntries, err = s.queries.ListWeightsWeightAsc(ctx, params)
-
-	default:
-		// Default to date desc
-		params := sqlc.ListWeightsDateDescParams{
-			StartDate: startDate,
-			EndDate:   endDate,
-			RowLimit:  int64(options.Limit),
+	return entries, nil
+}
+
+// SearchWeights retrieves the entries whose note matches options.NoteQuery using the
+// full-text index, with a highlighted snippet of each note
+func (s *DBStore) SearchWeights(ctx context.Context, options ListOptions) ([]SearchResult, error) {
+	if options.Offset < 0 {
+		return nil, fmt.Errorf("invalid offset: %d", options.Offset)
+	}
+	if options.AfterID != 0 {
+		if _, err := s.GetWeight(ctx, options.AfterID); err != nil {
+			return nil, fmt.Errorf("invalid cursor: %w", err)
 		}
-		sqlcEntries, err = s.queries.ListWeightsDateDesc(ctx, params)
 	}
 
+	query, args, err := buildSearchQuery(options)
 	if err != nil {
-		return nil, fmt.Errorf("failed to list weight entries: %w", err)
+		return nil, err
 	}
 
-	// Convert sqlc entries to WeightEntry
-	entries := make([]WeightEntry, len(sqlcEntries))
-	for i, sqlcEntry := range sqlcEntries {
-		entries[i] = s.sqlcToWeightEntry(sqlcEntry)
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		if queryErr := noteQueryError(options.NoteQuery, err); queryErr != nil {
+			return nil, queryErr
+		}
+		return nil, fmt.Errorf("failed to search notes: %w", err)
 	}
+	defer rows.Close()
 
-	// Apply unit filtering (not supported by current sqlc queries)
-	if options.Unit != "" {
-		filtered := make([]WeightEntry, 0)
-		for _, entry := range entries {
-			if entry.Unit == options.Unit {
-				filtered = append(filtered, entry)
-			}
+	results := make([]SearchResult, 0)
+	for rows.Next() {
+		var row sqlc.Weight
+		var snippet sql.NullString
+		if err := rows.Scan(&row.ID, &row.Weight, &row.Date, &row.Unit, &row.Note, &row.UserID, &snippet); err != nil {
+			return nil, fmt.Errorf("failed to read weight entry: %w", err)
 		}
-		entries = filtered
+		results = append(results, SearchResult{WeightEntry: s.sqlcToWeightEntry(row), Snippet: snippet.String})
+	}
+	if err := rows.Err(); err != nil {
+		if queryErr := noteQueryError(options.NoteQuery, err); queryErr != nil {
+			return nil, queryErr
+		}
+		return nil, fmt.Errorf("failed to search notes: %w", err)
 	}
 
-	return entries, nil
+	return results, nil
+}
+
+// noteQueryError returns a readable error when err reports a full-text query SQLite
+// cannot parse, and nil for any other error. SQLite reports it when the query runs,
+// which may be on the first rows.Next rather than in QueryContext.
+func noteQueryError(noteQuery string, err error) error {
+	if noteQuery == "" || !strings.Contains(err.Error(), "malformed MATCH expression") {
+		return nil
+	}
+	return fmt.Errorf("%w '%s': check quotes, parentheses and AND/OR/NOT operators", ErrInvalidNoteQuery, noteQuery)
 }
 
 // DeleteWeight removes a weight entry by ID
@@ -206,7 +339,7 @@ func (s *DBStore) DeleteWeight(ctx context.Context, id int64) error {
 	_, err := s.queries.GetWeight(ctx, id)
 	if err != nil {
 		if err == sql.ErrNoRows {
-			return fmt.Errorf("weight entry with id %d not found", id)
+			return entryNotFoundError{id: id}
 		}
 		return fmt.Errorf("failed to check if weight entry exists: %w", err)
 	}
@@ -224,7 +357,7 @@ func (s *DBStore) GetWeight(ctx context.Context, id int64) (WeightEntry, error)
 	sqlcEntry, err := s.queries.GetWeight(ctx, id)
 	if err != nil {
 		if err == sql.ErrNoRows {
-			return WeightEntry{}, fmt.Errorf("weight entry with id %d not found", id)
+			return WeightEntry{}, entryNotFoundError{id: id}
 		}
 		return WeightEntry{}, fmt.Errorf("failed to get weight entry: %w", err)
 	}
@@ -264,31 +397,179 @@ func (s *DBStore) UpdateWeight(ctx context.Context, entry WeightEntry) (WeightEn
 		updatedEntry.UserID = entry.UserID
 	}
 
-	// Validate the merged entry
-	if err := ValidateWeightEntry(updatedEntry); err != nil {
+	return s.ReplaceWeight(ctx, updatedEntry)
+}
+
+// ReplaceWeight overwrites every field of an existing weight entry
+func (s *DBStore) ReplaceWeight(ctx context.Context, entry WeightEntry) (WeightEntry, error) {
+	if entry.ID <= 0 {
+		return WeightEntry{}, fmt.Errorf("invalid ID: %d", entry.ID)
+	}
+
+	if err := ValidateWeightEntry(entry); err != nil {
 		return WeightEntry{}, err
 	}
 
 	// Convert WeightEntry to sqlc format
 	params := sqlc.UpdateWeightParams{
-		Weight: updatedEntry.Weight,
-		Date:   sql.NullString{String: FormatDateForDB(updatedEntry.Date), Valid: true},
-		Unit:   sql.NullString{String: updatedEntry.Unit, Valid: updatedEntry.Unit != ""},
-		Note:   sql.NullString{String: updatedEntry.Note, Valid: updatedEntry.Note != ""},
-		UserID: sql.NullString{String: updatedEntry.UserID, Valid: updatedEntry.UserID != ""},
-		ID:     updatedEntry.ID,
+		Weight: entry.Weight,
+		Date:   sql.NullString{String: FormatDateForDB(entry.Date), Valid: true},
+		Unit:   sql.NullString{String: entry.Unit, Valid: entry.Unit != ""},
+		Note:   sql.NullString{String: entry.Note, Valid: entry.Note != ""},
+		UserID: sql.NullString{String: entry.UserID, Valid: entry.UserID != ""},
+		ID:     entry.ID,
+	}
+
+	// Register a newly assigned user and update the entry atomically
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return WeightEntry{}, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	queries := s.queries.WithTx(tx)
+	if entry.UserID != "" {
+		if err := queries.EnsureUser(ctx, entry.UserID); err != nil {
+			return WeightEntry{}, fmt.Errorf("failed to register user '%s': %w", entry.UserID, err)
+		}
 	}
 
 	// Call sqlc method
-	sqlcEntry, err := s.queries.UpdateWeight(ctx, params)
+	sqlcEntry, err := queries.UpdateWeight(ctx, params)
 	if err != nil {
+		if err == sql.ErrNoRows {
+			return WeightEntry{}, entryNotFoundError{id: entry.ID}
+		}
 		return WeightEntry{}, fmt.Errorf("failed to update weight entry: %w", err)
 	}
 
+	if err := tx.Commit(); err != nil {
+		return WeightEntry{}, fmt.Errorf("failed to commit weight entry update: %w", err)
+	}
+
 	// Convert back to WeightEntry
 	return s.sqlcToWeightEntry(sqlcEntry), nil
 }
 
+// ListUsers retrieves all users registered in the database
+func (s *DBStore) ListUsers(ctx context.Context) ([]User, error) {
+	sqlcUsers, err := s.queries.ListUsers(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list users: %w", err)
+	}
+
+	users := make([]User, len(sqlcUsers))
+	for i, sqlcUser := range sqlcUsers {
+		users[i] = User{ID: sqlcUser.ID}
+		if createdAt, err := ParseTimestampFromDB(sqlcUser.CreatedAt, s.location); err == nil {
+			users[i].CreatedAt = createdAt
+		}
+	}
+	return users, nil
+}
+
+// SetGoal creates or replaces the goal of goal.UserID
+func (s *DBStore) SetGoal(ctx context.Context, goal Goal) (Goal, error) {
+	if err := ValidateGoal(goal); err != nil {
+		return Goal{}, err
+	}
+
+	params := sqlc.SetGoalParams{
+		UserID:       goal.UserID,
+		TargetWeight: goal.TargetWeight,
+		Unit:         goal.Unit,
+		StartWeight:  goal.StartWeight,
+	}
+	if goal.TargetDate != nil {
+		params.TargetDate = sql.NullString{String: FormatDateForDB(*goal.TargetDate), Valid: true}
+	}
+
+	// Register the user and set the goal atomically
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return Goal{}, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	queries := s.queries.WithTx(tx)
+	if goal.UserID != "" {
+		if err := queries.EnsureUser(ctx, goal.UserID); err != nil {
+			return Goal{}, fmt.Errorf("failed to register user '%s': %w", goal.UserID, err)
+		}
+	}
+
+	sqlcGoal, err := queries.SetGoal(ctx, params)
+	if err != nil {
+		return Goal{}, fmt.Errorf("failed to set goal: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return Goal{}, fmt.Errorf("failed to commit goal: %w", err)
+	}
+
+	return s.sqlcToGoal(sqlcGoal), nil
+}
+
+// GetGoal retrieves the goal of a user
+func (s *DBStore) GetGoal(ctx context.Context, userID string) (Goal, error) {
+	sqlcGoal, err := s.queries.GetGoal(ctx, userID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return Goal{}, ErrGoalNotFound
+		}
+		return Goal{}, fmt.Errorf("failed to get goal: %w", err)
+	}
+	return s.sqlcToGoal(sqlcGoal), nil
+}
+
+// ListGoals retrieves the goals of all users, ordered by user
+func (s *DBStore) ListGoals(ctx context.Context) ([]Goal, error) {
+	sqlcGoals, err := s.queries.ListGoals(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list goals: %w", err)
+	}
+
+	goals := make([]Goal, len(sqlcGoals))
+	for i, sqlcGoal := range sqlcGoals {
+		goals[i] = s.sqlcToGoal(sqlcGoal)
+	}
+	return goals, nil
+}
+
+// DeleteGoal removes the goal of a user
+func (s *DBStore) DeleteGoal(ctx context.Context, userID string) error {
+	rows, err := s.queries.DeleteGoal(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("failed to delete goal: %w", err)
+	}
+	if rows == 0 {
+		return ErrGoalNotFound
+	}
+	return nil
+}
+
+// sqlcToGoal converts a sqlc.Goal to Goal
+func (s *DBStore) sqlcToGoal(sqlcGoal sqlc.Goal) Goal {
+	goal := Goal{
+		ID:           sqlcGoal.ID,
+		UserID:       sqlcGoal.UserID,
+		TargetWeight: sqlcGoal.TargetWeight,
+		Unit:         sqlcGoal.Unit,
+		StartWeight:  sqlcGoal.StartWeight,
+	}
+
+	if sqlcGoal.TargetDate.Valid {
+		if targetDate, err := ParseDateFromDB(sqlcGoal.TargetDate.String, s.location); err == nil {
+			goal.TargetDate = &targetDate
+		}
+	}
+	if createdAt, err := ParseTimestampFromDB(sqlcGoal.CreatedAt, s.location); err == nil {
+		goal.CreatedAt = createdAt
+	}
+
+	return goal
+}
+
 // sqlcToWeightEntry converts a sqlc.Weight to WeightEntry
 func (s *DBStore) sqlcToWeightEntry(sqlcEntry sqlc.Weight) WeightEntry {
 	entry := WeightEntry{
@@ -298,7 +579,7 @@ func (s *DBStore) sqlcToWeightEntry(sqlcEntry sqlc.Weight) WeightEntry {
 
 	// Parse date (always stored in ISO format in database)
 	if sqlcEntry.Date.Valid && sqlcEntry.Date.String != "" {
-		if date, err := time.Parse(DBFormat, sqlcEntry.Date.String); err == nil {
+		if date, err := ParseDateFromDB(sqlcEntry.Date.String, s.location); err == nil {
 			entry.Date = date
 		} else {
 			// Fallback to zero time if parsing fails
@@ -330,9 +611,26 @@ func ValidateWeightEntry(entry WeightEntry) error {
 		return fmt.Errorf("weight must be greater than 0")
 	}
 
-	if entry.Unit != "" && entry.Unit != "kg" && entry.Unit != "lbs" {
+	if entry.Unit != "" && !IsValidUnit(entry.Unit) {
 		return fmt.Errorf("unit must be 'kg' or 'lbs', got: %s", entry.Unit)
 	}
 
 	return nil
 }
+
+// ValidateGoal validates a Goal struct
+func ValidateGoal(goal Goal) error {
+	if goal.TargetWeight <= 0 {
+		return fmt.Errorf("target weight must be greater than 0")
+	}
+
+	if goal.StartWeight <= 0 {
+		return fmt.Errorf("start weight must be greater than 0")
+	}
+
+	if !IsValidUnit(goal.Unit) {
+		return fmt.Errorf("unit must be 'kg' or 'lbs', got: %s", goal.Unit)
+	}
+
+	return nil
+}
@@ -1,8 +1,13 @@
 package tracker
 
 import (
+	"cmp"
 	"context"
 	"fmt"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
 )
 
 // store_mock.go - MockStore implementation
@@ -13,6 +18,8 @@ import (
 // MockStore is an in-memory implementation of the Store interface for testing
 type MockStore struct {
 	entries []WeightEntry
+	users   []User
+	goals   []Goal
 	nextID  int64
 }
 
@@ -29,9 +36,36 @@ func (m *MockStore) AddWeight(ctx context.Context, entry WeightEntry) (WeightEnt
 	entry.ID = m.nextID
 	m.nextID++
 	m.entries = append(m.entries, entry)
+	m.ensureUser(entry.UserID)
 	return entry, nil
 }
 
+// AddWeights adds weight entries to the mock store
+func (m *MockStore) AddWeights(ctx context.Context, entries []WeightEntry) ([]WeightEntry, error) {
+	added := make([]WeightEntry, 0, len(entries))
+	for _, entry := range entries {
+		entry, err := m.AddWeight(ctx, entry)
+		if err != nil {
+			return nil, err
+		}
+		added = append(added, entry)
+	}
+	return added, nil
+}
+
+// ensureUser registers a user the first time an entry references it
+func (m *MockStore) ensureUser(userID string) {
+	if userID == "" {
+		return
+	}
+	for _, user := range m.users {
+		if user.ID == userID {
+			return
+		}
+	}
+	m.users = append(m.users, User{ID: userID, CreatedAt: time.Now()})
+}
+
 // ListWeights retrieves weight entries from the mock store
 func (m *MockStore) ListWeights(ctx context.Context, options ListOptions) ([]WeightEntry, error) {
 	// Start with all entries
@@ -69,50 +103,77 @@ func (m *MockStore) ListWeights(ctx context.Context, options ListOptions) ([]Wei
 		result = filtered
 	}
 
-	// Apply sorting
-	if options.SortBy != "" {
-		switch options.SortBy {
-		case "date":
-			if options.SortDesc {
-				// Sort by date descending
-				for i := 0; i < len(result)-1; i++ {
-					for j := i + 1; j < len(result); j++ {
-						if result[i].Date.Before(result[j].Date) {
-							result[i], result[j] = result[j], result[i]
-						}
-					}
-				}
-			} else {
-				// Sort by date ascending
-				for i := 0; i < len(result)-1; i++ {
-					for j := i + 1; j < len(result); j++ {
-						if result[i].Date.After(result[j].Date) {
-							result[i], result[j] = result[j], result[i]
-						}
-					}
-				}
+	// Apply user filtering
+	if options.UserID != "" {
+		filtered := make([]WeightEntry, 0)
+		for _, entry := range result {
+			if entry.UserID == options.UserID {
+				filtered = append(filtered, entry)
 			}
-		case "weight":
-			if options.SortDesc {
-				// Sort by weight descending
-				for i := 0; i < len(result)-1; i++ {
-					for j := i + 1; j < len(result); j++ {
-						if result[i].Weight < result[j].Weight {
-							result[i], result[j] = result[j], result[i]
-						}
-					}
-				}
-			} else {
-				// Sort by weight ascending
-				for i := 0; i < len(result)-1; i++ {
-					for j := i + 1; j < len(result); j++ {
-						if result[i].Weight > result[j].Weight {
-							result[i], result[j] = result[j], result[i]
-						}
-					}
-				}
+		}
+		result = filtered
+	}
+
+	// Apply note filtering (case-insensitive substring, like the SQL query)
+	if options.Note != "" {
+		filtered := make([]WeightEntry, 0)
+		for _, entry := range result {
+			if strings.Contains(strings.ToLower(entry.Note), strings.ToLower(options.Note)) {
+				filtered = append(filtered, entry)
 			}
 		}
+		result = filtered
+	}
+
+	// Apply the full-text query
+	if options.NoteQuery != "" {
+		query, err := parseMockNoteQuery(options.NoteQuery)
+		if err != nil {
+			return nil, err
+		}
+		filtered := make([]WeightEntry, 0)
+		for _, entry := range result {
+			if query.matches(entry.Note) {
+				filtered = append(filtered, entry)
+			}
+		}
+		result = filtered
+	}
+
+	// Resolve the sort order the way the SQL query builder does
+	keys, err := sortKeysFor(options)
+	if err != nil {
+		return nil, err
+	}
+
+	// Apply the cursor: keep the entries sorting after the cursor entry
+	if options.AfterID != 0 {
+		cursor, err := m.GetWeight(ctx, options.AfterID)
+		if err != nil {
+			return nil, fmt.Errorf("invalid cursor: %w", err)
+		}
+		filtered := make([]WeightEntry, 0)
+		for _, entry := range result {
+			if mockEntryBefore(cursor, entry, keys) {
+				filtered = append(filtered, entry)
+			}
+		}
+		result = filtered
+	}
+
+	// Apply sorting
+	sort.SliceStable(result, func(i, j int) bool {
+		return mockEntryBefore(result[i], result[j], keys)
+	})
+
+	// Apply offset
+	if options.Offset < 0 {
+		return nil, fmt.Errorf("invalid offset: %d", options.Offset)
+	}
+	if options.Offset >= len(result) {
+		result = result[:0]
+	} else {
+		result = result[options.Offset:]
 	}
 
 	// Apply limit
@@ -123,6 +184,211 @@ func (m *MockStore) ListWeights(ctx context.Context, options ListOptions) ([]Wei
 	return result, nil
 }
 
+// SearchWeights retrieves the entries whose note matches options.NoteQuery from the mock
+// store; snippets hold the whole note with the matching words highlighted
+func (m *MockStore) SearchWeights(ctx context.Context, options ListOptions) ([]SearchResult, error) {
+	if options.NoteQuery == "" {
+		return nil, fmt.Errorf("a search query is required")
+	}
+	query, err := parseMockNoteQuery(options.NoteQuery)
+	if err != nil {
+		return nil, err
+	}
+
+	entries, err := m.ListWeights(ctx, options)
+	if err != nil {
+		return nil, err
+	}
+	results := make([]SearchResult, len(entries))
+	for i, entry := range entries {
+		results[i] = SearchResult{WeightEntry: entry, Snippet: query.highlight(entry.Note)}
+	}
+	return results, nil
+}
+
+// mockNoteQuery is a full-text query read like SQLite's enhanced query syntax, without
+// parentheses: OR separates alternatives whose terms must all match (NOT binds
+// tighter than AND, which binds tighter than OR)
+type mockNoteQuery [][]mockQueryTerm
+
+// mockQueryTerm is a word or "quoted phrase" of a mockNoteQuery
+type mockQueryTerm struct {
+	words  []string
+	prefix bool // The last word ends in * and matches as a prefix
+	negate bool // The term is preceded by NOT
+}
+
+// parseMockNoteQuery parses query into alternatives of terms
+func parseMockNoteQuery(query string) (mockNoteQuery, error) {
+	if strings.Count(query, `"`)%2 != 0 || strings.ContainsAny(query, "()") {
+		return nil, fmt.Errorf("%w '%s': check quotes (the mock store does not support parentheses)", ErrInvalidNoteQuery, query)
+	}
+
+	var tokens []string
+	for i, part := range strings.Split(query, `"`) {
+		if i%2 == 1 {
+			tokens = append(tokens, `"`+part)
+			continue
+		}
+		tokens = append(tokens, strings.Fields(part)...)
+	}
+
+	parsed := mockNoteQuery{nil}
+	negate := false
+	for _, token := range tokens {
+		switch token {
+		case "OR":
+			parsed = append(parsed, nil)
+			continue
+		case "AND":
+			continue
+		case "NOT":
+			negate = true
+			continue
+		}
+
+		term := mockQueryTerm{negate: negate}
+		negate = false
+		if strings.HasSuffix(token, "*") {
+			term.prefix = true
+		}
+		term.words = mockNoteWords(token)
+		if len(term.words) == 0 {
+			continue
+		}
+		last := len(parsed) - 1
+		parsed[last] = append(parsed[last], term)
+	}
+	return parsed, nil
+}
+
+// mockNoteWords splits text into lower-case words like the unicode61 tokenizer
+func mockNoteWords(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// matches reports whether note satisfies one of the alternatives of the query
+func (q mockNoteQuery) matches(note string) bool {
+	words := mockNoteWords(note)
+	for _, alternative := range q {
+		if len(alternative) == 0 {
+			continue
+		}
+		matched := true
+		for _, term := range alternative {
+			if term.occursIn(words) == term.negate {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			return true
+		}
+	}
+	return false
+}
+
+// occursIn reports whether the term's words appear consecutively in words
+func (t mockQueryTerm) occursIn(words []string) bool {
+	for start := 0; start+len(t.words) <= len(words); start++ {
+		found := true
+		for i := range t.words {
+			if !t.wordMatches(i, words[start+i]) {
+				found = false
+				break
+			}
+		}
+		if found {
+			return true
+		}
+	}
+	return false
+}
+
+// wordMatches reports whether word matches the i-th word of the term
+func (t mockQueryTerm) wordMatches(i int, word string) bool {
+	if t.prefix && i == len(t.words)-1 {
+		return strings.HasPrefix(word, t.words[i])
+	}
+	return word == t.words[i]
+}
+
+// highlight wraps the words of note matching a term of the query in snippet markers
+func (q mockNoteQuery) highlight(note string) string {
+	isMatch := func(word string) bool {
+		for _, alternative := range q {
+			for _, term := range alternative {
+				for i := range term.words {
+					if !term.negate && term.wordMatches(i, word) {
+						return true
+					}
+				}
+			}
+		}
+		return false
+	}
+
+	var highlighted, word strings.Builder
+	flush := func() {
+		if word.Len() == 0 {
+			return
+		}
+		if isMatch(strings.ToLower(word.String())) {
+			highlighted.WriteString(snippetMatchStart + word.String() + snippetMatchEnd)
+		} else {
+			highlighted.WriteString(word.String())
+		}
+		word.Reset()
+	}
+	for _, r := range note {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			word.WriteRune(r)
+			continue
+		}
+		flush()
+		highlighted.WriteRune(r)
+	}
+	flush()
+	return highlighted.String()
+}
+
+// mockEntryBefore reports whether a comes before b in the order given by keys
+func mockEntryBefore(a, b WeightEntry, keys []SortKey) bool {
+	for _, key := range keys {
+		comparison := compareEntryField(a, b, key.Field)
+		if comparison == 0 {
+			continue
+		}
+		if key.Desc {
+			return comparison > 0
+		}
+		return comparison < 0
+	}
+	return false
+}
+
+// compareEntryField compares a field of two entries like SQLite compares the column
+func compareEntryField(a, b WeightEntry, field string) int {
+	switch field {
+	case "date":
+		return a.Date.Compare(b.Date)
+	case "weight":
+		return cmp.Compare(a.Weight, b.Weight)
+	case "id":
+		return cmp.Compare(a.ID, b.ID)
+	case "unit":
+		return strings.Compare(a.Unit, b.Unit)
+	case "note":
+		return strings.Compare(a.Note, b.Note)
+	case "user":
+		return strings.Compare(a.UserID, b.UserID)
+	default:
+		return 0
+	}
+}
+
 // DeleteWeight removes a weight entry by ID from the mock store
 func (m *MockStore) DeleteWeight(ctx context.Context, id int64) error {
 	if id <= 0 {
@@ -135,7 +401,7 @@ func (m *MockStore) DeleteWeight(ctx context.Context, id int64) error {
 			return nil
 		}
 	}
-	return fmt.Errorf("weight entry with id %d not found", id)
+	return entryNotFoundError{id: id}
 }
 
 // GetWeight retrieves a single weight entry by ID from the mock store
@@ -145,7 +411,7 @@ func (m *MockStore) GetWeight(ctx context.Context, id int64) (WeightEntry, error
 			return entry, nil
 		}
 	}
-	return WeightEntry{}, fmt.Errorf("weight entry with id %d not found", id)
+	return WeightEntry{}, entryNotFoundError{id: id}
 }
 
 // UpdateWeight updates an existing weight entry in the mock store
@@ -182,10 +448,93 @@ func (m *MockStore) UpdateWeight(ctx context.Context, entry WeightEntry) (Weight
 			}
 
 			m.entries[i] = updatedEntry
+			m.ensureUser(updatedEntry.UserID)
 			return updatedEntry, nil
 		}
 	}
-	return WeightEntry{}, fmt.Errorf("weight entry with id %d not found", entry.ID)
+	return WeightEntry{}, entryNotFoundError{id: entry.ID}
+}
+
+// ReplaceWeight overwrites every field of an existing weight entry in the mock store
+func (m *MockStore) ReplaceWeight(ctx context.Context, entry WeightEntry) (WeightEntry, error) {
+	if entry.ID <= 0 {
+		return WeightEntry{}, fmt.Errorf("invalid ID: %d", entry.ID)
+	}
+
+	if err := ValidateWeightEntry(entry); err != nil {
+		return WeightEntry{}, err
+	}
+
+	for i, existingEntry := range m.entries {
+		if existingEntry.ID == entry.ID {
+			m.entries[i] = entry
+			m.ensureUser(entry.UserID)
+			return entry, nil
+		}
+	}
+	return WeightEntry{}, entryNotFoundError{id: entry.ID}
+}
+
+// ListUsers retrieves all users from the mock store, ordered by ID
+func (m *MockStore) ListUsers(ctx context.Context) ([]User, error) {
+	users := make([]User, len(m.users))
+	copy(users, m.users)
+	sort.Slice(users, func(i, j int) bool {
+		return users[i].ID < users[j].ID
+	})
+	return users, nil
+}
+
+// SetGoal creates or replaces the goal of goal.UserID in the mock store
+func (m *MockStore) SetGoal(ctx context.Context, goal Goal) (Goal, error) {
+	if err := ValidateGoal(goal); err != nil {
+		return Goal{}, err
+	}
+
+	goal.CreatedAt = time.Now()
+	for i, existing := range m.goals {
+		if existing.UserID == goal.UserID {
+			goal.ID = existing.ID
+			m.goals[i] = goal
+			return goal, nil
+		}
+	}
+
+	goal.ID = int64(len(m.goals) + 1)
+	m.goals = append(m.goals, goal)
+	m.ensureUser(goal.UserID)
+	return goal, nil
+}
+
+// GetGoal retrieves the goal of a user from the mock store
+func (m *MockStore) GetGoal(ctx context.Context, userID string) (Goal, error) {
+	for _, goal := range m.goals {
+		if goal.UserID == userID {
+			return goal, nil
+		}
+	}
+	return Goal{}, ErrGoalNotFound
+}
+
+// ListGoals retrieves all goals from the mock store, ordered by user
+func (m *MockStore) ListGoals(ctx context.Context) ([]Goal, error) {
+	goals := make([]Goal, len(m.goals))
+	copy(goals, m.goals)
+	sort.Slice(goals, func(i, j int) bool {
+		return goals[i].UserID < goals[j].UserID
+	})
+	return goals, nil
+}
+
+// DeleteGoal removes the goal of a user from the mock store
+func (m *MockStore) DeleteGoal(ctx context.Context, userID string) error {
+	for i, goal := range m.goals {
+		if goal.UserID == userID {
+			m.goals = append(m.goals[:i], m.goals[i+1:]...)
+			return nil
+		}
+	}
+	return ErrGoalNotFound
 }
 
 // Close is a no-op for the mock store
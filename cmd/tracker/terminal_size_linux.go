@@ -0,0 +1,28 @@
+package tracker
+
+// terminal_size_linux.go - Terminal size detection on Linux
+// Related files: terminal_size_other.go (other platforms), graph_terminal.go (sizes charts)
+
+import (
+	"io"
+	"os"
+	"syscall"
+	"unsafe"
+)
+
+// windowSize returns the size in characters of the terminal w writes to, if any
+func windowSize(w io.Writer) (columns, rows int, ok bool) {
+	file, isFile := w.(*os.File)
+	if !isFile {
+		return 0, 0, false
+	}
+
+	var size struct {
+		Rows, Columns, XPixels, YPixels uint16
+	}
+	_, _, errno := syscall.Syscall(syscall.SYS_IOCTL, file.Fd(), uintptr(syscall.TIOCGWINSZ), uintptr(unsafe.Pointer(&size)))
+	if errno != 0 || size.Columns == 0 || size.Rows == 0 {
+		return 0, 0, false
+	}
+	return int(size.Columns), int(size.Rows), true
+}
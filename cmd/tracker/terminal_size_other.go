@@ -0,0 +1,13 @@
+//go:build !linux
+
+package tracker
+
+// terminal_size_other.go - Terminal size detection on platforms other than Linux
+// Related files: terminal_size_linux.go (Linux), graph_terminal.go (sizes charts)
+
+import "io"
+
+// windowSize reports no terminal size: charts fall back to COLUMNS and LINES
+func windowSize(w io.Writer) (columns, rows int, ok bool) {
+	return 0, 0, false
+}
@@ -0,0 +1,307 @@
+package tracker
+
+// trend.go - Trend engine for smoothing noisy weight data
+// Related files: stats.go (stats --trend), graph.go (trend overlay series), trend_test.go (tests)
+// Daily weight swings by a kilogram or more from water and food alone. The trend
+// engine separates real progress from that noise with a simple moving average,
+// an exponentially weighted moving average ("Hacker's Diet" trend) and a linear
+// regression over the selected entries.
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// TrendMethod identifies a smoothing algorithm
+type TrendMethod string
+
+const (
+	TrendSMA    TrendMethod = "sma"    // Simple moving average over a trailing window of days
+	TrendEMA    TrendMethod = "ema"    // Exponentially weighted moving average
+	TrendLinear TrendMethod = "linear" // Least-squares linear regression
+)
+
+const (
+	// DefaultTrendWindowDays is the default trailing window of the simple moving average
+	DefaultTrendWindowDays = 7
+	// HackersDietAlpha is the smoothing factor of the Hacker's Diet trend: each new
+	// weigh-in moves the trend 10% of the way towards it
+	HackersDietAlpha = 0.1
+)
+
+// TrendOptions selects the trend to compute; an empty Method means no trend
+type TrendOptions struct {
+	Method     TrendMethod
+	WindowDays int // Window of the simple moving average in days
+}
+
+// TrendPoint is the smoothed weight at the date of an entry
+type TrendPoint struct {
+	Date   time.Time `json:"date" yaml:"date"`
+	Weight float64   `json:"weight" yaml:"weight"`
+}
+
+// LinearRegression is a least-squares fit of weight against time
+type LinearRegression struct {
+	Start       time.Time // Date at which the fit equals Intercept
+	Intercept   float64   // Fitted weight at Start
+	SlopePerDay float64   // Fitted change in weight per day
+	RSquared    float64   // Goodness of fit (1 = perfect line)
+}
+
+// TrendSummary is the trend section of the statistics
+type TrendSummary struct {
+	Method        TrendMethod  `json:"method" yaml:"method"`
+	WindowDays    int          `json:"window_days,omitempty" yaml:"window_days,omitempty"`
+	Alpha         float64      `json:"alpha,omitempty" yaml:"alpha,omitempty"`
+	Current       float64      `json:"current" yaml:"current"`               // Trend weight at the latest entry
+	RatePerWeek   float64      `json:"rate_per_week" yaml:"rate_per_week"`   // Regression slope per week
+	RSquared      float64      `json:"r_squared" yaml:"r_squared"`           // Goodness of fit of the regression
+	HasRegression bool         `json:"has_regression" yaml:"has_regression"` // False when fewer than two distinct dates
+	Points        []TrendPoint `json:"points" yaml:"points"`
+}
+
+// parseTrendMethod validates a trend method name
+func parseTrendMethod(method string) (TrendMethod, error) {
+	switch TrendMethod(strings.ToLower(method)) {
+	case TrendSMA:
+		return TrendSMA, nil
+	case TrendEMA:
+		return TrendEMA, nil
+	case TrendLinear:
+		return TrendLinear, nil
+	default:
+		return "", fmt.Errorf("invalid trend method '%s': must be 'sma', 'ema' or 'linear'", method)
+	}
+}
+
+// addTrendFlags registers the trend flags shared by list and stats
+func addTrendFlags(cmd *cobra.Command, usage string) {
+	cmd.Flags().Bool("trend", false, usage)
+	cmd.Flags().String("trend-method", string(TrendEMA), "Trend method (sma, ema, linear)")
+	cmd.Flags().Int("trend-window", DefaultTrendWindowDays, "Window of the simple moving average in days")
+}
+
+// trendOptionsFromFlags builds TrendOptions from the shared trend flags
+// (a zero value when --trend is not set)
+func trendOptionsFromFlags(cmd *cobra.Command) (TrendOptions, error) {
+	enabled, _ := cmd.Flags().GetBool("trend")
+	if !enabled {
+		return TrendOptions{}, nil
+	}
+
+	methodValue, _ := cmd.Flags().GetString("trend-method")
+	method, err := parseTrendMethod(methodValue)
+	if err != nil {
+		return TrendOptions{}, err
+	}
+
+	window, _ := cmd.Flags().GetInt("trend-window")
+	if window <= 0 {
+		return TrendOptions{}, fmt.Errorf("trend window must be a positive number of days, got: %d", window)
+	}
+
+	return TrendOptions{Method: method, WindowDays: window}, nil
+}
+
+// sortedByDate returns a copy of entries in chronological order
+func sortedByDate(entries []WeightEntry) []WeightEntry {
+	sorted := make([]WeightEntry, len(entries))
+	copy(sorted, entries)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Date.Before(sorted[j].Date)
+	})
+	return sorted
+}
+
+// SimpleMovingAverage returns, for every entry, the mean of all entries logged
+// in the trailing window of days ending at it. Using a window of days rather
+// than of entries keeps irregular logging from skewing the average.
+func SimpleMovingAverage(entries []WeightEntry, windowDays int) ([]TrendPoint, error) {
+	if windowDays <= 0 {
+		return nil, fmt.Errorf("moving average window must be positive, got: %d", windowDays)
+	}
+
+	sorted := sortedByDate(entries)
+	window := time.Duration(windowDays) * 24 * time.Hour
+	points := make([]TrendPoint, 0, len(sorted))
+
+	start, sum := 0, 0.0
+	for i, entry := range sorted {
+		sum += entry.Weight
+		// Drop entries that fell out of the window
+		for entry.Date.Sub(sorted[start].Date) >= window {
+			sum -= sorted[start].Weight
+			start++
+		}
+		points = append(points, TrendPoint{Date: entry.Date, Weight: sum / float64(i-start+1)})
+	}
+
+	return points, nil
+}
+
+// ExponentialMovingAverage returns the exponentially weighted moving average of
+// entries: the trend starts at the first weight and each later entry moves it
+// by alpha times the difference between the entry and the current trend
+func ExponentialMovingAverage(entries []WeightEntry, alpha float64) ([]TrendPoint, error) {
+	if alpha <= 0 || alpha > 1 {
+		return nil, fmt.Errorf("smoothing factor must be in (0, 1], got: %v", alpha)
+	}
+
+	sorted := sortedByDate(entries)
+	points := make([]TrendPoint, 0, len(sorted))
+
+	var trend float64
+	for i, entry := range sorted {
+		if i == 0 {
+			trend = entry.Weight
+		} else {
+			trend += alpha * (entry.Weight - trend)
+		}
+		points = append(points, TrendPoint{Date: entry.Date, Weight: trend})
+	}
+
+	return points, nil
+}
+
+// FitLinearRegression fits weight against time by least squares. At least two
+// entries on distinct dates are needed.
+func FitLinearRegression(entries []WeightEntry) (LinearRegression, error) {
+	if len(entries) < 2 {
+		return LinearRegression{}, fmt.Errorf("at least 2 entries are needed for a regression, got %d", len(entries))
+	}
+
+	sorted := sortedByDate(entries)
+	start := sorted[0].Date
+	n := float64(len(sorted))
+
+	// x is days since the first entry
+	var sumX, sumY float64
+	for _, entry := range sorted {
+		sumX += entry.Date.Sub(start).Hours() / 24
+		sumY += entry.Weight
+	}
+	meanX, meanY := sumX/n, sumY/n
+
+	var sxx, sxy, syy float64
+	for _, entry := range sorted {
+		dx := entry.Date.Sub(start).Hours()/24 - meanX
+		dy := entry.Weight - meanY
+		sxx += dx * dx
+		sxy += dx * dy
+		syy += dy * dy
+	}
+
+	if sxx == 0 {
+		return LinearRegression{}, fmt.Errorf("entries must span more than one point in time for a regression")
+	}
+
+	slope := sxy / sxx
+	rSquared := 1.0
+	if syy > 0 {
+		rSquared = (sxy * sxy) / (sxx * syy)
+	}
+
+	return LinearRegression{
+		Start:       start,
+		Intercept:   meanY - slope*meanX,
+		SlopePerDay: slope,
+		RSquared:    rSquared,
+	}, nil
+}
+
+// At returns the fitted weight at t
+func (r LinearRegression) At(t time.Time) float64 {
+	return r.Intercept + r.SlopePerDay*t.Sub(r.Start).Hours()/24
+}
+
+// SlopePerWeek returns the fitted change in weight per week
+func (r LinearRegression) SlopePerWeek() float64 {
+	return r.SlopePerDay * 7
+}
+
+// ComputeTrend returns the trend value at every entry for the selected method
+func ComputeTrend(entries []WeightEntry, options TrendOptions) ([]TrendPoint, error) {
+	switch options.Method {
+	case TrendSMA:
+		return SimpleMovingAverage(entries, options.WindowDays)
+	case TrendEMA:
+		return ExponentialMovingAverage(entries, HackersDietAlpha)
+	case TrendLinear:
+		regression, err := FitLinearRegression(entries)
+		if err != nil {
+			return nil, err
+		}
+		sorted := sortedByDate(entries)
+		points := make([]TrendPoint, 0, len(sorted))
+		for _, entry := range sorted {
+			points = append(points, TrendPoint{Date: entry.Date, Weight: regression.At(entry.Date)})
+		}
+		return points, nil
+	default:
+		return nil, fmt.Errorf("unsupported trend method: %s", options.Method)
+	}
+}
+
+// calculateTrend summarizes the trend of entries, which are expected to share a
+// single unit (see NormalizeEntries)
+func calculateTrend(entries []WeightEntry, options TrendOptions) (*TrendSummary, error) {
+	if len(entries) == 0 {
+		return nil, nil
+	}
+
+	points, err := ComputeTrend(entries, options)
+	if err != nil {
+		if options.Method != TrendLinear {
+			return nil, err
+		}
+		// Too few dates for a regression: without smoothing (alpha 1) the trend
+		// is simply the recorded weight
+		points, _ = ExponentialMovingAverage(entries, 1)
+	}
+
+	summary := &TrendSummary{
+		Method:  options.Method,
+		Current: points[len(points)-1].Weight,
+		Points:  points,
+	}
+	switch options.Method {
+	case TrendSMA:
+		summary.WindowDays = options.WindowDays
+	case TrendEMA:
+		summary.Alpha = HackersDietAlpha
+	}
+
+	// The regression rate is reported with every method; a single weigh-in has no rate
+	if regression, err := FitLinearRegression(entries); err == nil {
+		summary.HasRegression = true
+		summary.RatePerWeek = regression.SlopePerWeek()
+		summary.RSquared = regression.RSquared
+	}
+
+	return summary, nil
+}
+
+// trendLabel returns a short human-readable description of a trend
+func trendLabel(options TrendOptions) string {
+	switch options.Method {
+	case TrendSMA:
+		return fmt.Sprintf("%d-day moving average", options.WindowDays)
+	case TrendEMA:
+		return fmt.Sprintf("EMA trend (alpha %.2f)", HackersDietAlpha)
+	case TrendLinear:
+		return "Linear trend"
+	default:
+		return "Trend"
+	}
+}
+
+// roundTrend rounds a trend weight for display alongside recorded weights
+func roundTrend(weight float64) float64 {
+	return math.Round(weight*100) / 100
+}
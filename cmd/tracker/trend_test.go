@@ -0,0 +1,231 @@
+package tracker
+
+import (
+	"math"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// trend_test.go - Trend engine tests
+// * purpose: tests moving averages, regression and trend overlays.
+// * focus: numeric accuracy, irregular logging and flag parsing.
+
+func trendTestEntries() []WeightEntry {
+	baseDate := time.Date(2025, 1, 1, 7, 0, 0, 0, time.UTC)
+	return []WeightEntry{
+		{ID: 1, Weight: 80.0, Date: baseDate, Unit: "kg"},
+		{ID: 2, Weight: 81.0, Date: baseDate.AddDate(0, 0, 1), Unit: "kg"},
+		{ID: 3, Weight: 79.0, Date: baseDate.AddDate(0, 0, 2), Unit: "kg"},
+		// Gap of a week
+		{ID: 4, Weight: 78.0, Date: baseDate.AddDate(0, 0, 9), Unit: "kg"},
+	}
+}
+
+func TestSimpleMovingAverage(t *testing.T) {
+	points, err := SimpleMovingAverage(trendTestEntries(), 3)
+	if err != nil {
+		t.Fatal(unexpectedErrorString(err))
+	}
+
+	// The last entry is alone in its 3-day window after the gap
+	expected := []float64{80.0, 80.5, 80.0, 78.0}
+	for i, want := range expected {
+		if math.Abs(points[i].Weight-want) > 1e-9 {
+			t.Errorf("point %d = %v, want %v", i, points[i].Weight, want)
+		}
+	}
+
+	if _, err := SimpleMovingAverage(trendTestEntries(), 0); err == nil {
+		t.Errorf("SimpleMovingAverage() expected error for zero window but got none")
+	}
+}
+
+func TestExponentialMovingAverage(t *testing.T) {
+	// Entries given out of order are smoothed in date order
+	entries := trendTestEntries()
+	entries[0], entries[3] = entries[3], entries[0]
+
+	points, err := ExponentialMovingAverage(entries, HackersDietAlpha)
+	if err != nil {
+		t.Fatal(unexpectedErrorString(err))
+	}
+
+	// 80 -> 80.1 -> 79.99 -> 79.791
+	expected := []float64{80.0, 80.1, 79.99, 79.791}
+	for i, want := range expected {
+		if math.Abs(points[i].Weight-want) > 1e-9 {
+			t.Errorf("point %d = %v, want %v", i, points[i].Weight, want)
+		}
+	}
+	if !points[0].Date.Before(points[3].Date) {
+		t.Errorf("points are not in date order: %v", points)
+	}
+
+	if _, err := ExponentialMovingAverage(entries, 1.5); err == nil {
+		t.Errorf("ExponentialMovingAverage() expected error for alpha > 1 but got none")
+	}
+}
+
+func TestFitLinearRegression(t *testing.T) {
+	baseDate := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name         string
+		entries      []WeightEntry
+		wantPerWeek  float64
+		wantRSquared float64
+		wantErr      bool
+	}{
+		{
+			name: "perfect line losing 0.1 kg per day",
+			entries: []WeightEntry{
+				{Weight: 80.0, Date: baseDate},
+				{Weight: 79.0, Date: baseDate.AddDate(0, 0, 10)},
+				{Weight: 78.0, Date: baseDate.AddDate(0, 0, 20)},
+			},
+			wantPerWeek:  -0.7,
+			wantRSquared: 1,
+		},
+		{
+			name: "flat weight",
+			entries: []WeightEntry{
+				{Weight: 75.0, Date: baseDate},
+				{Weight: 75.0, Date: baseDate.AddDate(0, 0, 3)},
+			},
+			wantPerWeek:  0,
+			wantRSquared: 1,
+		},
+		{
+			name:    "single entry",
+			entries: []WeightEntry{{Weight: 75.0, Date: baseDate}},
+			wantErr: true,
+		},
+		{
+			name: "same timestamp",
+			entries: []WeightEntry{
+				{Weight: 75.0, Date: baseDate},
+				{Weight: 76.0, Date: baseDate},
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			regression, err := FitLinearRegression(tt.entries)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("FitLinearRegression() expected error but got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(unexpectedErrorString(err))
+			}
+
+			if math.Abs(regression.SlopePerWeek()-tt.wantPerWeek) > 1e-9 {
+				t.Errorf("SlopePerWeek() = %v, want %v", regression.SlopePerWeek(), tt.wantPerWeek)
+			}
+			if math.Abs(regression.RSquared-tt.wantRSquared) > 1e-9 {
+				t.Errorf("RSquared = %v, want %v", regression.RSquared, tt.wantRSquared)
+			}
+			if math.Abs(regression.At(tt.entries[0].Date)-tt.entries[0].Weight) > 1e-9 {
+				t.Errorf("At(first date) = %v, want %v", regression.At(tt.entries[0].Date), tt.entries[0].Weight)
+			}
+		})
+	}
+}
+
+func TestCalculateTrend(t *testing.T) {
+	summary, err := calculateTrend(trendTestEntries(), TrendOptions{Method: TrendEMA, WindowDays: 7})
+	if err != nil {
+		t.Fatal(unexpectedErrorString(err))
+	}
+	if summary.Alpha != HackersDietAlpha || summary.WindowDays != 0 {
+		t.Errorf("EMA summary parameters = alpha %v, window %d", summary.Alpha, summary.WindowDays)
+	}
+	if len(summary.Points) != 4 || !summary.HasRegression || summary.RatePerWeek >= 0 {
+		t.Errorf("summary = %+v, want 4 points and a falling rate", summary)
+	}
+
+	// A single weigh-in has a trend but no rate of change, for every method
+	single := trendTestEntries()[:1]
+	for _, method := range []TrendMethod{TrendSMA, TrendEMA, TrendLinear} {
+		summary, err := calculateTrend(single, TrendOptions{Method: method, WindowDays: 7})
+		if err != nil {
+			t.Fatalf("%s: %v", method, err)
+		}
+		if summary.Current != 80.0 || summary.HasRegression {
+			t.Errorf("%s: single entry summary = %+v", method, summary)
+		}
+	}
+}
+
+func TestTrendOptionsFromFlags(t *testing.T) {
+	tests := []struct {
+		name        string
+		flags       map[string]string
+		expected    TrendOptions
+		expectError bool
+	}{
+		{name: "trend disabled", flags: map[string]string{"trend-method": "sma"}, expected: TrendOptions{}},
+		{name: "default method", flags: map[string]string{"trend": "true"}, expected: TrendOptions{Method: TrendEMA, WindowDays: DefaultTrendWindowDays}},
+		{name: "sma with window", flags: map[string]string{"trend": "true", "trend-method": "SMA", "trend-window": "14"}, expected: TrendOptions{Method: TrendSMA, WindowDays: 14}},
+		{name: "invalid method", flags: map[string]string{"trend": "true", "trend-method": "median"}, expectError: true},
+		{name: "invalid window", flags: map[string]string{"trend": "true", "trend-window": "0"}, expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Create a fresh test command for each test case to avoid flag contamination
+			cmd := &cobra.Command{Use: "stats"}
+			addTrendFlags(cmd, "Show trend")
+			for flag, value := range tt.flags {
+				if err := cmd.Flags().Set(flag, value); err != nil {
+					t.Fatalf("failed to set flag %s: %v", flag, err)
+				}
+			}
+
+			result, err := trendOptionsFromFlags(cmd)
+			if tt.expectError {
+				if err == nil {
+					t.Errorf("trendOptionsFromFlags() expected error but got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(unexpectedErrorString(err))
+			}
+			if result != tt.expected {
+				t.Errorf("trendOptionsFromFlags() = %+v, want %+v", result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestGenerateHTMLChart_TrendOverlay(t *testing.T) {
+	options := GraphOptions{
+		OutputType:    OutputHTML,
+		OutputFile:    "trend.html",
+		Title:         "Trend Chart",
+		Trend:         TrendOptions{Method: TrendSMA, WindowDays: 3},
+		TestOutputDir: t.TempDir(),
+	}
+
+	outputPath, err := GenerateWeightChart(trendTestEntries(), options)
+	if err != nil {
+		t.Fatal(unexpectedErrorString(err))
+	}
+
+	content, err := os.ReadFile(outputPath)
+	if err != nil {
+		t.Fatalf("failed to read chart: %v", err)
+	}
+	if !strings.Contains(string(content), "3-day moving average") {
+		t.Errorf("chart does not contain the trend series")
+	}
+}
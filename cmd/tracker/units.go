@@ -0,0 +1,83 @@
+package tracker
+
+// units.go - Weight unit conversion
+// Related files: list.go, stats.go, graph.go (all display weights in a single unit)
+// Entries may be stored in either kg or lbs; everything that aggregates or
+// plots weights normalizes them to one display unit first.
+
+import (
+	"fmt"
+)
+
+// Supported weight units
+const (
+	UnitKg  = "kg"
+	UnitLbs = "lbs"
+)
+
+// KgPerLb is the exact number of kilograms in one international avoirdupois pound
+const KgPerLb = 0.45359237
+
+// IsValidUnit reports whether unit is one of the supported weight units
+func IsValidUnit(unit string) bool {
+	return unit == UnitKg || unit == UnitLbs
+}
+
+// ConvertWeight converts a weight value from one unit to another.
+// An empty source unit is treated as kg, matching the database default.
+func ConvertWeight(value float64, from, to string) (float64, error) {
+	if from == "" {
+		from = UnitKg
+	}
+	if !IsValidUnit(from) {
+		return 0, fmt.Errorf("unsupported unit '%s': must be 'kg' or 'lbs'", from)
+	}
+	if !IsValidUnit(to) {
+		return 0, fmt.Errorf("unsupported unit '%s': must be 'kg' or 'lbs'", to)
+	}
+
+	if from == to {
+		return value, nil
+	}
+	if from == UnitLbs {
+		return value * KgPerLb, nil
+	}
+	return value / KgPerLb, nil
+}
+
+// ConvertEntry returns a copy of entry with its weight expressed in the given unit
+func ConvertEntry(entry WeightEntry, to string) (WeightEntry, error) {
+	converted, err := ConvertWeight(entry.Weight, entry.Unit, to)
+	if err != nil {
+		return WeightEntry{}, fmt.Errorf("entry %d: %w", entry.ID, err)
+	}
+	entry.Weight = converted
+	entry.Unit = to
+	return entry, nil
+}
+
+// NormalizeEntries returns copies of entries with all weights expressed in the given unit.
+// The input slice is left untouched.
+func NormalizeEntries(entries []WeightEntry, to string) ([]WeightEntry, error) {
+	normalized := make([]WeightEntry, len(entries))
+	for i, entry := range entries {
+		converted, err := ConvertEntry(entry, to)
+		if err != nil {
+			return nil, err
+		}
+		normalized[i] = converted
+	}
+	return normalized, nil
+}
+
+// resolveDisplayUnitFromEnv returns the requested display unit, falling back to the
+// DEFAULT_UNIT configured in getEnv when none was given
+func resolveDisplayUnitFromEnv(requested string, getEnv func(string) string) (string, error) {
+	if requested == "" {
+		return GetDefaultUnitFromEnv(getEnv), nil
+	}
+	if !IsValidUnit(requested) {
+		return "", fmt.Errorf("invalid display unit '%s': must be 'kg' or 'lbs'", requested)
+	}
+	return requested, nil
+}
@@ -0,0 +1,184 @@
+package tracker
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+// units_test.go - Unit conversion tests
+// * purpose: test kg/lbs conversion and normalization of mixed-unit histories
+// * focus: conversion accuracy and statistics over mixed units
+
+func TestConvertWeight(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    float64
+		from     string
+		to       string
+		expected float64
+		wantErr  bool
+	}{
+		{
+			name:     "kg to kg",
+			value:    75.5,
+			from:     "kg",
+			to:       "kg",
+			expected: 75.5,
+		},
+		{
+			name:     "lbs to kg",
+			value:    165.0,
+			from:     "lbs",
+			to:       "kg",
+			expected: 74.8427,
+		},
+		{
+			name:     "kg to lbs",
+			value:    75.0,
+			from:     "kg",
+			to:       "lbs",
+			expected: 165.3467,
+		},
+		{
+			name:     "empty source unit treated as kg",
+			value:    80.0,
+			from:     "",
+			to:       "lbs",
+			expected: 176.3698,
+		},
+		{
+			name:    "invalid source unit",
+			value:   80.0,
+			from:    "stone",
+			to:      "kg",
+			wantErr: true,
+		},
+		{
+			name:    "invalid target unit",
+			value:   80.0,
+			from:    "kg",
+			to:      "",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := ConvertWeight(tt.value, tt.from, tt.to)
+
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("ConvertWeight() expected error but got none")
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatal(unexpectedErrorString(err))
+			}
+
+			if math.Abs(result-tt.expected) > 0.0001 {
+				t.Errorf("ConvertWeight() = %v, want %v", result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestConvertWeight_RoundTrip(t *testing.T) {
+	lbs, err := ConvertWeight(72.3, "kg", "lbs")
+	if err != nil {
+		t.Fatal(unexpectedErrorString(err))
+	}
+	kg, err := ConvertWeight(lbs, "lbs", "kg")
+	if err != nil {
+		t.Fatal(unexpectedErrorString(err))
+	}
+	if math.Abs(kg-72.3) > 1e-9 {
+		t.Errorf("round trip = %v, want 72.3", kg)
+	}
+}
+
+func TestNormalizeEntries(t *testing.T) {
+	baseDate := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	entries := []WeightEntry{
+		{ID: 1, Weight: 75.0, Date: baseDate, Unit: "kg"},
+		{ID: 2, Weight: 165.0, Date: baseDate.AddDate(0, 0, 1), Unit: "lbs"},
+	}
+
+	normalized, err := NormalizeEntries(entries, "kg")
+	if err != nil {
+		t.Fatal(unexpectedErrorString(err))
+	}
+
+	for _, entry := range normalized {
+		if entry.Unit != "kg" {
+			t.Errorf("entry %d unit = %s, want kg", entry.ID, entry.Unit)
+		}
+	}
+	if math.Abs(normalized[1].Weight-74.8427) > 0.0001 {
+		t.Errorf("converted weight = %v, want 74.8427", normalized[1].Weight)
+	}
+
+	// The input slice must not be modified
+	if entries[1].Weight != 165.0 || entries[1].Unit != "lbs" {
+		t.Errorf("NormalizeEntries() modified its input: %+v", entries[1])
+	}
+
+	// Invalid stored units are reported instead of silently mixed in
+	_, err = NormalizeEntries([]WeightEntry{{ID: 3, Weight: 10, Unit: "stone"}}, "kg")
+	if err == nil {
+		t.Errorf("NormalizeEntries() expected error for invalid unit but got none")
+	}
+}
+
+func TestCalculateStatistics_MixedUnits(t *testing.T) {
+	baseDate := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	entries := []WeightEntry{
+		{ID: 1, Weight: 75.0, Date: baseDate, Unit: "kg"},
+		{ID: 2, Weight: 165.0, Date: baseDate.AddDate(0, 0, 7), Unit: "lbs"},
+	}
+
+	normalized, err := NormalizeEntries(entries, "kg")
+	if err != nil {
+		t.Fatal(unexpectedErrorString(err))
+	}
+
+	stats := calculateStatistics(normalized)
+
+	if stats.Unit != "kg" {
+		t.Errorf("Unit = %s, want kg", stats.Unit)
+	}
+	// (75.0 + 74.8427) / 2
+	if math.Abs(stats.AverageWeight-74.92135) > 0.0001 {
+		t.Errorf("AverageWeight = %v, want 74.92135", stats.AverageWeight)
+	}
+	if stats.MaxWeightEntry.ID != 1 {
+		t.Errorf("MaxWeightEntry.ID = %d, want 1", stats.MaxWeightEntry.ID)
+	}
+}
+
+func TestResolveDisplayUnit(t *testing.T) {
+	env := map[string]string{"DEFAULT_UNIT": "lbs"}
+	getEnv := func(key string) string { return env[key] }
+
+	unit, err := resolveDisplayUnitFromEnv("kg", getEnv)
+	if err != nil {
+		t.Fatal(unexpectedErrorString(err))
+	}
+	if unit != "kg" {
+		t.Errorf("resolveDisplayUnitFromEnv(kg) = %s, want kg", unit)
+	}
+
+	unit, err = resolveDisplayUnitFromEnv("", getEnv)
+	if err != nil {
+		t.Fatal(unexpectedErrorString(err))
+	}
+	if unit != "lbs" {
+		t.Errorf("resolveDisplayUnitFromEnv(\"\") = %s, want the configured default unit lbs", unit)
+	}
+
+	if _, err := resolveDisplayUnitFromEnv("stone", getEnv); err == nil {
+		t.Errorf("resolveDisplayUnitFromEnv(stone) expected error but got none")
+	}
+}
@@ -3,7 +3,6 @@ package tracker
 import (
 	"context"
 	"fmt"
-	"os"
 	"strconv"
 
 	"github.com/spf13/cobra"
@@ -21,7 +20,9 @@ Examples:
   weight-tracker update 1 --weight 75.5                    # Update weight only
   weight-tracker update 2 --weight 80.0 --unit lbs         # Update weight and unit
   weight-tracker update 3 --date 01-01-2025 --note "morning weight"  # Update date and note
+  weight-tracker update 3 --time 07:45                     # Update time of day only
   weight-tracker update 4 --weight 70.0 --date 15-06-2025 --unit kg --note "after workout"
+  weight-tracker update 5 --weight 71.2 --yes --output json  # Skip confirmation, print JSON
 `,
 	Args: cobra.ExactArgs(1),
 	Run:  runUpdate,
@@ -31,18 +32,39 @@ var updateWeight float64
 var updateDate string
 var updateUnit string
 var updateNote string
+var updateTime string
+var updateYes bool
 
 func init() {
 	updateCmd.Flags().Float64VarP(&updateWeight, "weight", "w", 0, "New weight value")
-	updateCmd.Flags().StringVarP(&updateDate, "date", "d", "", "New date (format configurable via DATE_INPUT_FORMAT)")
+	updateCmd.Flags().StringVarP(&updateDate, "date", "d", "", "New date (DATE_INPUT_FORMAT or relative: today, -7d, last monday, this month, 2025-W10)")
 	updateCmd.Flags().StringVarP(&updateUnit, "unit", "u", "", "New unit (kg, lbs)")
 	updateCmd.Flags().StringVarP(&updateNote, "note", "n", "", "New note")
+	updateCmd.Flags().StringVarP(&updateTime, "time", "t", "", "New time of day (HH:MM or HH:MM:SS)")
+	updateCmd.Flags().BoolVarP(&updateYes, "yes", "y", false, "Skip confirmation prompt")
 }
 
 // runUpdateInternal contains the core logic and returns errors instead of terminating
 func runUpdateInternal(cmd *cobra.Command, args []string) error {
+	renderer, err := rendererFor(cmd)
+	if err != nil {
+		return err
+	}
+
+	// Keep the preview and prompt off stdout when it carries machine-readable output
+	promptOut := cmd.OutOrStdout()
+	if renderer.Structured() {
+		promptOut = cmd.ErrOrStderr()
+	}
+	skipConfirm, _ := cmd.Flags().GetBool("yes")
+
+	app := appFor(cmd)
+	// Times of day are set in the configured time zone
+	config := GetAppConfigFromEnv(app.Getenv)
+	location := config.Location
+
 	// Create store instance
-	store, err := NewDBStore()
+	store, err := app.NewStore()
 	if err != nil {
 		return fmt.Errorf("could not create store: %w", err)
 	}
@@ -65,9 +87,16 @@ func runUpdateInternal(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to find weight entry with ID %d: %w", id, err)
 	}
 
+	// Entries of other users can't be modified while a user is selected
+	if err := checkEntryOwner(existingEntry, resolveUser(cmd)); err != nil {
+		return err
+	}
+
 	// Show current entry
-	fmt.Printf("Current weight entry:\n")
-	printWeightEntry(existingEntry)
+	if !skipConfirm {
+		fmt.Fprintf(promptOut, "Current weight entry:\n")
+		printWeightEntry(promptOut, existingEntry, config)
+	}
 
 	// Create updated entry starting with existing values
 	updatedEntry := existingEntry
@@ -87,11 +116,24 @@ func runUpdateInternal(cmd *cobra.Command, args []string) error {
 	if cmd.Flags().Changed("date") {
 		dateStr, _ := cmd.Flags().GetString("date")
 		if dateStr != "" {
-			parsedDate, err := ParseDate(dateStr)
+			parsedDate, err := ParseDateAt(dateStr, app.Now(), app.Getenv)
 			if err != nil {
-				return fmt.Errorf("invalid date format '%s': use %s format", dateStr, GetInputFormatDescription())
+				return fmt.Errorf("invalid date format '%s': %s", dateStr, dateInputHint(app.Getenv))
 			}
-			updatedEntry.Date = parsedDate
+			// Keep the existing time of day unless --time is also given
+			updatedEntry.Date = WithTimeOfDay(parsedDate, TimeOfDay(updatedEntry.Date.In(location)))
+			fieldsUpdated = true
+		}
+	}
+
+	if cmd.Flags().Changed("time") {
+		timeStr, _ := cmd.Flags().GetString("time")
+		if timeStr != "" {
+			timeOfDay, err := ParseTimeOfDay(timeStr)
+			if err != nil {
+				return err
+			}
+			updatedEntry.Date = WithTimeOfDay(updatedEntry.Date.In(location), timeOfDay)
 			fieldsUpdated = true
 		}
 	}
@@ -113,7 +155,7 @@ func runUpdateInternal(cmd *cobra.Command, args []string) error {
 
 	// Check if any fields were actually updated
 	if !fieldsUpdated {
-		return fmt.Errorf("no fields to update. Use --weight, --date, --unit, or --note flags")
+		return fmt.Errorf("no fields to update. Use --weight, --date, --time, --unit, or --note flags")
 	}
 
 	// Validate the updated entry
@@ -121,18 +163,15 @@ func runUpdateInternal(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("validation failed: %w", err)
 	}
 
-	// Show what will be updated
-	fmt.Printf("\nUpdated weight entry:\n")
-	printWeightEntry(updatedEntry)
+	// Show what will be updated and confirm unless --yes is used
+	if !skipConfirm {
+		fmt.Fprintf(promptOut, "\nUpdated weight entry:\n")
+		printWeightEntry(promptOut, updatedEntry, config)
 
-	// Confirm the update
-	fmt.Print("Are you sure you want to update this entry? (y/N): ")
-	var response string
-	fmt.Scanln(&response)
-
-	if response != "y" && response != "Y" && response != "yes" && response != "Yes" {
-		fmt.Println("Update cancelled.")
-		return nil
+		if !confirm(cmd.InOrStdin(), promptOut, "Are you sure you want to update this entry?") {
+			fmt.Fprintln(promptOut, "Update cancelled.")
+			return nil
+		}
 	}
 
 	// Update the entry
@@ -141,17 +180,16 @@ func runUpdateInternal(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to update weight entry: %w", err)
 	}
 
-	fmt.Printf("\nSuccessfully updated weight entry with ID %d.\n", id)
-	fmt.Printf("Final entry:\n")
-	printWeightEntry(finalEntry)
-
-	return nil
+	if !renderer.Structured() {
+		fmt.Fprintf(cmd.OutOrStdout(), "\nSuccessfully updated weight entry with ID %d.\n", id)
+		fmt.Fprintf(cmd.OutOrStdout(), "Final entry:\n")
+	}
+	return renderer.Render(finalEntry)
 }
 
 // runUpdate is the cobra command wrapper that handles errors appropriately for CLI usage
 func runUpdate(cmd *cobra.Command, args []string) {
 	if err := runUpdateInternal(cmd, args); err != nil {
-		fmt.Fprintf(cmd.ErrOrStderr(), "Error: %v\n", err)
-		os.Exit(1)
+		exitWithError(cmd, err)
 	}
 }
@@ -14,7 +14,7 @@ func TestUpdateCommand_Integration(t *testing.T) {
 	db := setupTestDB(t)
 	defer db.Close()
 
-	store := NewDBStoreWithDB(db)
+	store := NewDBStoreWithDB(db, time.UTC)
 	ctx := context.Background()
 
 	tests := []struct {
@@ -442,7 +442,7 @@ func TestUpdateCommand_CLI_Integration(t *testing.T) {
 			defer testDB.Close()
 
 			// Create store with test database
-			store := NewDBStoreWithDB(testDB)
+			store := NewDBStoreWithDB(testDB, time.UTC)
 			ctx := context.Background()
 
 			// Add a test entry to update (only if we have a valid ID)
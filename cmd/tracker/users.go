@@ -0,0 +1,108 @@
+package tracker
+
+// users.go - Multi-user support
+// Related files: root.go (global --user flag), store.go (users table, UserID filtering)
+// Every command is scoped to the user selected with --user or WEIGHT_TRACKER_USER.
+// When neither is set, commands operate on all entries as before.
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/spf13/cobra"
+)
+
+// UserEnvVar is the environment variable used when --user is not given
+const UserEnvVar = "WEIGHT_TRACKER_USER"
+
+var currentUserFlag string
+
+var usersCmd = &cobra.Command{
+	Use:   "users",
+	Short: "List users sharing the database",
+	Long: `List all users that have weight entries in the database.
+
+Users are created automatically the first time an entry is added with --user
+(or with the WEIGHT_TRACKER_USER environment variable set).
+
+Examples:
+  weight-tracker users                         # List all users
+  weight-tracker --user alice add 62.4         # Add an entry for alice
+  weight-tracker --user alice list             # List alice's entries only
+  WEIGHT_TRACKER_USER=bob weight-tracker stats # Statistics for bob`,
+	Args: cobra.NoArgs,
+	Run:  runUsers,
+}
+
+// resolveUser returns the user selected for this invocation: the --user flag
+// takes precedence over the WEIGHT_TRACKER_USER variable of the command's App
+func resolveUser(cmd *cobra.Command) string {
+	return resolveUserFromEnv(cmd, appFor(cmd).Getenv)
+}
+
+// resolveUserFromEnv resolves the user using a custom environment function
+func resolveUserFromEnv(cmd *cobra.Command, getEnv func(string) string) string {
+	if flag := cmd.Flags().Lookup("user"); flag != nil && flag.Changed {
+		return flag.Value.String()
+	}
+	return getEnv(UserEnvVar)
+}
+
+// checkEntryOwner returns an error when a user is selected and the entry belongs to someone else
+func checkEntryOwner(entry WeightEntry, user string) error {
+	if user != "" && entry.UserID != user {
+		return fmt.Errorf("weight entry with ID %d does not belong to user '%s'", entry.ID, user)
+	}
+	return nil
+}
+
+// runUsersInternal contains the core logic and returns errors instead of terminating
+func runUsersInternal(cmd *cobra.Command, args []string) error {
+	// Note: args are not used for users command
+	_ = args
+
+	renderer, err := rendererFor(cmd)
+	if err != nil {
+		return err
+	}
+
+	app := appFor(cmd)
+
+	// Create store instance
+	store, err := app.NewStore()
+	if err != nil {
+		return fmt.Errorf("could not create store: %w", err)
+	}
+	defer store.Close()
+
+	users, err := store.ListUsers(context.Background())
+	if err != nil {
+		return fmt.Errorf("failed to list users: %w", err)
+	}
+
+	return renderer.Render(userList(users))
+}
+
+// userList is the result of the users command
+type userList []User
+
+func (users userList) writeTable(w io.Writer, config AppConfig) error {
+	if len(users) == 0 {
+		fmt.Fprintln(w, "No users found.")
+		return nil
+	}
+
+	fmt.Fprintf(w, "Found %d users:\n\n", len(users))
+	for _, user := range users {
+		fmt.Fprintf(w, "* %s (since %s)\n", user.ID, config.FormatDate(user.CreatedAt))
+	}
+	return nil
+}
+
+// runUsers is the cobra command wrapper that handles errors appropriately for CLI usage
+func runUsers(cmd *cobra.Command, args []string) {
+	if err := runUsersInternal(cmd, args); err != nil {
+		exitWithError(cmd, err)
+	}
+}
@@ -0,0 +1,165 @@
+package tracker
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// users_test.go - Multi-user tests
+// * purpose: tests per-user scoping with both real db and mock.
+// * tests: integration (real DB) and MockStore (mock)
+// * focus: user registration, UserID filtering and --user/env resolution.
+
+// seedUserEntries adds entries for two users and one entry without a user
+func seedUserEntries(t *testing.T, store Store) {
+	t.Helper()
+	ctx := context.Background()
+	baseDate := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	testEntries := []WeightEntry{
+		{Weight: 62.0, Date: baseDate, Unit: "kg", UserID: "alice"},
+		{Weight: 61.5, Date: baseDate.AddDate(0, 0, 1), Unit: "kg", UserID: "alice"},
+		{Weight: 88.0, Date: baseDate, Unit: "kg", UserID: "bob"},
+		{Weight: 70.0, Date: baseDate, Unit: "kg"},
+	}
+	for _, entry := range testEntries {
+		if _, err := store.AddWeight(ctx, entry); err != nil {
+			t.Fatal(failedTestEntryAdditionString(err))
+		}
+	}
+}
+
+func TestUsers_Integration(t *testing.T) {
+	testDB := setupTestDB(t)
+	defer testDB.Close()
+
+	store := NewDBStoreWithDB(testDB, time.UTC)
+	ctx := context.Background()
+	seedUserEntries(t, store)
+
+	tests := []struct {
+		name     string
+		options  ListOptions
+		expected int
+	}{
+		{name: "all users", options: ListOptions{}, expected: 4},
+		{name: "alice only", options: ListOptions{UserID: "alice"}, expected: 2},
+		{name: "bob only", options: ListOptions{UserID: "bob"}, expected: 1},
+		{name: "unknown user", options: ListOptions{UserID: "carol"}, expected: 0},
+		{name: "user combined with limit", options: ListOptions{UserID: "alice", Limit: 1}, expected: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := store.ListWeights(ctx, tt.options)
+			if err != nil {
+				t.Fatal(unexpectedErrorString(err))
+			}
+			if len(result) != tt.expected {
+				t.Errorf("ListWeights() got %d entries, want %d", len(result), tt.expected)
+			}
+			for _, entry := range result {
+				if tt.options.UserID != "" && entry.UserID != tt.options.UserID {
+					t.Errorf("entry %d belongs to %s, want %s", entry.ID, entry.UserID, tt.options.UserID)
+				}
+			}
+		})
+	}
+
+	users, err := store.ListUsers(ctx)
+	if err != nil {
+		t.Fatal(unexpectedErrorString(err))
+	}
+	if len(users) != 2 || users[0].ID != "alice" || users[1].ID != "bob" {
+		t.Errorf("ListUsers() = %+v, want alice and bob", users)
+	}
+
+	// Reassigning an entry registers the new user
+	if _, err := store.UpdateWeight(ctx, WeightEntry{ID: 4, UserID: "carol"}); err != nil {
+		t.Fatal(unexpectedErrorString(err))
+	}
+	users, err = store.ListUsers(ctx)
+	if err != nil {
+		t.Fatal(unexpectedErrorString(err))
+	}
+	if len(users) != 3 {
+		t.Errorf("ListUsers() got %d users after reassignment, want 3", len(users))
+	}
+}
+
+func TestUsers_MockStore(t *testing.T) {
+	store := NewMockStore()
+	ctx := context.Background()
+	seedUserEntries(t, store)
+
+	result, err := store.ListWeights(ctx, ListOptions{UserID: "alice"})
+	if err != nil {
+		t.Fatal(unexpectedErrorString(err))
+	}
+	if len(result) != 2 {
+		t.Errorf("ListWeights() got %d entries, want 2", len(result))
+	}
+
+	users, err := store.ListUsers(ctx)
+	if err != nil {
+		t.Fatal(unexpectedErrorString(err))
+	}
+	if len(users) != 2 || users[0].ID != "alice" || users[1].ID != "bob" {
+		t.Errorf("ListUsers() = %+v, want alice and bob", users)
+	}
+}
+
+func TestResolveUserFromEnv(t *testing.T) {
+	tests := []struct {
+		name     string
+		flag     string
+		env      string
+		expected string
+	}{
+		{name: "no flag, no env", expected: ""},
+		{name: "env only", env: "bob", expected: "bob"},
+		{name: "flag only", flag: "alice", expected: "alice"},
+		{name: "flag overrides env", flag: "alice", env: "bob", expected: "alice"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Create a fresh test command for each test case to avoid flag contamination
+			cmd := &cobra.Command{Use: "list"}
+			cmd.Flags().String("user", "", "User")
+			if tt.flag != "" {
+				if err := cmd.Flags().Set("user", tt.flag); err != nil {
+					t.Fatalf("failed to set flag user: %v", err)
+				}
+			}
+
+			getEnv := func(key string) string {
+				if key == UserEnvVar {
+					return tt.env
+				}
+				return ""
+			}
+
+			if result := resolveUserFromEnv(cmd, getEnv); result != tt.expected {
+				t.Errorf("resolveUserFromEnv() = %q, want %q", result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestCheckEntryOwner(t *testing.T) {
+	entry := WeightEntry{ID: 1, Weight: 62.0, UserID: "alice"}
+
+	if err := checkEntryOwner(entry, ""); err != nil {
+		t.Errorf("no selected user should allow any entry: %v", err)
+	}
+	if err := checkEntryOwner(entry, "alice"); err != nil {
+		t.Errorf("owner should be allowed: %v", err)
+	}
+	if err := checkEntryOwner(entry, "bob"); err == nil {
+		t.Errorf("checkEntryOwner() expected error for a different user but got none")
+	}
+}
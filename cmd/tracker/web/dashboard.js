@@ -0,0 +1,208 @@
+// dashboard.js - Weight Tracker dashboard served by 'weight-tracker serve --ui'
+// Talks to the REST API (/entries, /stats) and embeds the chart page (/chart).
+"use strict";
+
+const REFRESH_INTERVAL_MS = 30000;
+
+const filtersForm = document.getElementById("filters");
+const entryForm = document.getElementById("entry-form");
+const formMessage = document.getElementById("form-message");
+const entriesBody = document.getElementById("entries");
+const summary = document.getElementById("summary");
+const chartFrame = document.getElementById("chart");
+const updated = document.getElementById("updated");
+
+// filterParams returns the non-empty filter fields as query parameters
+function filterParams() {
+  const params = new URLSearchParams();
+  for (const [name, value] of new FormData(filtersForm)) {
+    if (value !== "") {
+      params.set(name, value);
+    }
+  }
+  if (!params.has("trend")) {
+    params.delete("trend_window");
+  }
+  return params;
+}
+
+// api calls the REST API and returns the decoded JSON body, throwing the API error message
+async function api(method, path, body) {
+  const options = { method, headers: {} };
+  if (body !== undefined) {
+    options.headers["Content-Type"] = "application/json";
+    options.body = JSON.stringify(body);
+  }
+
+  const response = await fetch(path, options);
+  if (response.status === 204) {
+    return null;
+  }
+  const data = await response.json();
+  if (!response.ok) {
+    throw new Error(data.error || response.statusText);
+  }
+  return data;
+}
+
+// formatDate shows a date as stored, without converting it to the browser time zone
+function formatDate(value) {
+  return value.slice(0, 16).replace("T", " ");
+}
+
+function formatWeight(value) {
+  return Number(value).toFixed(1);
+}
+
+function cell(text, className) {
+  const td = document.createElement("td");
+  td.textContent = text;
+  if (className) {
+    td.className = className;
+  }
+  return td;
+}
+
+function renderEntries(entries) {
+  entriesBody.replaceChildren();
+  for (const entry of entries) {
+    const row = document.createElement("tr");
+    row.append(
+      cell(entry.id, "number"),
+      cell(formatDate(entry.date)),
+      cell(formatWeight(entry.weight), "number"),
+      cell(entry.unit),
+      cell(entry.user_id),
+      cell(entry.note),
+    );
+
+    const remove = document.createElement("button");
+    remove.type = "button";
+    remove.textContent = "Delete";
+    remove.addEventListener("click", () => deleteEntry(entry));
+    const actions = document.createElement("td");
+    actions.append(remove);
+    row.append(actions);
+
+    entriesBody.append(row);
+  }
+
+  if (entries.length === 0) {
+    const row = document.createElement("tr");
+    const empty = cell("No entries match the current filters.", "muted");
+    empty.colSpan = 7;
+    row.append(empty);
+    entriesBody.append(row);
+  }
+}
+
+function stat(label, value) {
+  const div = document.createElement("div");
+  div.className = "stat";
+  const valueSpan = document.createElement("div");
+  valueSpan.className = "value";
+  valueSpan.textContent = value;
+  const labelSpan = document.createElement("div");
+  labelSpan.className = "label";
+  labelSpan.textContent = label;
+  div.append(valueSpan, labelSpan);
+  return div;
+}
+
+function renderSummary(stats) {
+  summary.replaceChildren();
+  if (stats.total_entries === 0) {
+    summary.append(stat("Entries", "0"));
+    return;
+  }
+
+  const unit = stats.unit;
+  summary.append(
+    stat("Entries", stats.total_entries),
+    stat("Latest", `${formatWeight(stats.last_entry.weight)} ${unit}`),
+    stat("Average", `${formatWeight(stats.average_weight)} ${unit}`),
+    stat("Range", `${formatWeight(stats.min_weight)} - ${formatWeight(stats.max_weight)} ${unit}`),
+    stat("Time span", `${stats.time_span_days} days`),
+  );
+
+  if (stats.trend) {
+    const rate = stats.trend.rate_per_week;
+    summary.append(
+      stat("Trend", `${formatWeight(stats.trend.current)} ${unit}`),
+      stat("Rate", `${rate > 0 ? "+" : ""}${rate.toFixed(2)} ${unit}/week`),
+    );
+  }
+
+  if (stats.goal) {
+    const goal = stats.goal;
+    summary.append(stat("Goal", `${formatWeight(goal.goal.target_weight)} ${goal.goal.unit}`));
+    summary.append(stat("Progress", goal.reached ? "reached" : `${goal.progress_percent.toFixed(0)}%`));
+    if (goal.projected_date) {
+      summary.append(stat("Projected", goal.projected_date.slice(0, 10)));
+    }
+  }
+}
+
+// refresh reloads the table, the summary and the chart for the current filters
+async function refresh() {
+  const params = filterParams();
+  const listParams = new URLSearchParams(params);
+  listParams.delete("trend");
+  listParams.delete("trend_window");
+
+  try {
+    const [entries, stats] = await Promise.all([
+      api("GET", `/entries?${listParams}`),
+      api("GET", `/stats?${params}`),
+    ]);
+    renderEntries(entries);
+    renderSummary(stats);
+    chartFrame.src = `/chart?${params}`;
+    updated.textContent = `Updated ${new Date().toLocaleTimeString()}`;
+  } catch (error) {
+    updated.textContent = `Error: ${error.message}`;
+  }
+}
+
+async function deleteEntry(entry) {
+  if (!confirm(`Delete entry ${entry.id} (${formatWeight(entry.weight)} ${entry.unit} on ${formatDate(entry.date)})?`)) {
+    return;
+  }
+  try {
+    await api("DELETE", `/entries/${entry.id}`);
+    await refresh();
+  } catch (error) {
+    updated.textContent = `Error: ${error.message}`;
+  }
+}
+
+filtersForm.addEventListener("submit", (event) => {
+  event.preventDefault();
+  refresh();
+});
+
+entryForm.addEventListener("submit", async (event) => {
+  event.preventDefault();
+  const data = new FormData(entryForm);
+  const body = { weight: Number(data.get("weight")), unit: data.get("unit") };
+  for (const name of ["date", "note", "user_id"]) {
+    if (data.get(name) !== "") {
+      body[name] = data.get(name);
+    }
+  }
+
+  try {
+    const entry = await api("POST", "/entries", body);
+    formMessage.className = "message";
+    formMessage.textContent = `Added entry ${entry.id}`;
+    entryForm.reset();
+    await refresh();
+  } catch (error) {
+    formMessage.className = "message error";
+    formMessage.textContent = error.message;
+  }
+});
+
+// Keep the dashboard current with entries added from the CLI
+refresh();
+setInterval(refresh, REFRESH_INTERVAL_MS);
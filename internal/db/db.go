@@ -1,28 +1,122 @@
 package db
 
 import (
+	"context"
 	"database/sql"
+	"errors"
 	"fmt"
+	"io/fs"
 	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/joho/godotenv"
 )
 
-// OpenDB loads the database path from .env and returns a database connection
-func OpenDB() (*sql.DB, error) {
-	if err := godotenv.Load(); err != nil {
-		return nil, fmt.Errorf("error loading .env file: %w", err)
+const (
+	appDataDirName      = "weight-tracker"
+	defaultDatabaseFile = "weights.db"
+)
+
+// OpenDB opens the database at path with all pending migrations applied, unless
+// AUTO_MIGRATE is set to false; dates stored without a zone are read in location
+func OpenDB(path string, location *time.Location) (*sql.DB, error) {
+	db, err := Connect(path)
+	if err != nil {
+		return nil, err
+	}
+
+	enabled, err := autoMigrateEnabled()
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+	if enabled {
+		if _, err := Migrate(context.Background(), db, location); err != nil {
+			db.Close()
+			return nil, err
+		}
 	}
 
-	dbPath := os.Getenv("DATABASE_PATH")
-	if dbPath == "" {
-		return nil, fmt.Errorf("DATABASE_PATH not set in .env file")
+	return db, nil
+}
+
+// Connect opens the database at path without touching the schema, creating its
+// directory if needed
+func Connect(path string) (*sql.DB, error) {
+	if path == "" {
+		return nil, fmt.Errorf("database path is empty")
 	}
 
-	db, err := sql.Open("sqlite3", dbPath)
+	// In-memory and URI databases have no directory to create
+	if path != ":memory:" && !strings.HasPrefix(path, "file:") {
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			return nil, fmt.Errorf("failed to create database directory: %w", err)
+		}
+	}
+
+	db, err := sql.Open("sqlite3", path)
 	if err != nil {
 		return nil, fmt.Errorf("error opening database: %w", err)
 	}
 
 	return db, nil
 }
+
+// LoadEnvFile loads variables from a .env file in the working directory, if there
+// is one. Variables already set in the environment take precedence.
+func LoadEnvFile() error {
+	if err := godotenv.Load(); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("error loading .env file: %w", err)
+	}
+	return nil
+}
+
+// ResolvePath returns the database path: flagPath when set, otherwise DATABASE_PATH
+// (from the environment or a loaded .env file), otherwise DefaultPath
+func ResolvePath(flagPath string, getEnv func(string) string) (string, error) {
+	if flagPath != "" {
+		return flagPath, nil
+	}
+	if envPath := getEnv("DATABASE_PATH"); envPath != "" {
+		return envPath, nil
+	}
+	return DefaultPath(getEnv)
+}
+
+// DefaultPath returns the per-user database location following the XDG base
+// directory spec: $XDG_DATA_HOME/weight-tracker/weights.db, falling back to
+// ~/.local/share/weight-tracker/weights.db
+func DefaultPath(getEnv func(string) string) (string, error) {
+	dataHome := getEnv("XDG_DATA_HOME")
+	// The spec requires relative paths to be ignored
+	if dataHome == "" || !filepath.IsAbs(dataHome) {
+		home := getEnv("HOME")
+		if home == "" {
+			var err error
+			home, err = os.UserHomeDir()
+			if err != nil {
+				return "", fmt.Errorf("cannot determine the default database location: %w", err)
+			}
+		}
+		dataHome = filepath.Join(home, ".local", "share")
+	}
+	return filepath.Join(dataHome, appDataDirName, defaultDatabaseFile), nil
+}
+
+// autoMigrateEnabled reports whether OpenDB applies pending migrations
+// (AUTO_MIGRATE, default true). Values that are not a boolean are an error rather
+// than a guess, so that a typo cannot migrate a database meant to be left alone.
+func autoMigrateEnabled() (bool, error) {
+	value := os.Getenv("AUTO_MIGRATE")
+	if value == "" {
+		return true, nil
+	}
+	enabled, err := strconv.ParseBool(value)
+	if err != nil {
+		return false, fmt.Errorf("invalid AUTO_MIGRATE value '%s': must be true or false", value)
+	}
+	return enabled, nil
+}
@@ -0,0 +1,74 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/BlochLior/weight-tracker/migrations"
+	"github.com/pressly/goose/v3"
+)
+
+// ErrNoMigrationToRollback is returned by Rollback when no migration is applied
+var ErrNoMigrationToRollback = errors.New("no applied migration to roll back")
+
+// newMigrationProvider returns a goose provider running the embedded migrations against
+// db. location is the time zone of dates stored without one (see migrations.ConvertDatesToUTC).
+func newMigrationProvider(db *sql.DB, location *time.Location) (*goose.Provider, error) {
+	provider, err := goose.NewProvider(goose.DialectSQLite3, db, migrations.FS,
+		goose.WithGoMigrations(migrations.ConvertDatesToUTC(location)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to load migrations: %w", err)
+	}
+	return provider, nil
+}
+
+// Migrate applies all pending embedded migrations and returns the ones applied; dates
+// stored without a zone are read in location
+func Migrate(ctx context.Context, db *sql.DB, location *time.Location) ([]*goose.MigrationResult, error) {
+	provider, err := newMigrationProvider(db, location)
+	if err != nil {
+		return nil, err
+	}
+
+	results, err := provider.Up(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to apply migrations: %w", err)
+	}
+	return results, nil
+}
+
+// Rollback reverts the most recently applied migration; dates that lose their zone
+// are written in location
+func Rollback(ctx context.Context, db *sql.DB, location *time.Location) (*goose.MigrationResult, error) {
+	provider, err := newMigrationProvider(db, location)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := provider.Down(ctx)
+	if errors.Is(err, goose.ErrNoNextVersion) {
+		return nil, ErrNoMigrationToRollback
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to roll back migration: %w", err)
+	}
+	return result, nil
+}
+
+// MigrationStatus reports every embedded migration with whether it is applied
+func MigrationStatus(ctx context.Context, db *sql.DB) ([]*goose.MigrationStatus, error) {
+	// Reading the status runs no migration, so the time zone does not matter
+	provider, err := newMigrationProvider(db, time.UTC)
+	if err != nil {
+		return nil, err
+	}
+
+	statuses, err := provider.Status(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read migration status: %w", err)
+	}
+	return statuses, nil
+}
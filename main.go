@@ -1,11 +1,15 @@
 package main
 
 import (
+	"os"
+
 	"github.com/BlochLior/weight-tracker/cmd/tracker"
 	_ "github.com/mattn/go-sqlite3"
 )
 
 func main() {
 	// Application entry point for Cobra apps
-	tracker.Execute()
+	if err := tracker.Execute(); err != nil {
+		os.Exit(1)
+	}
 }
@@ -0,0 +1,13 @@
+-- +goose Up
+-- Dates were previously stored as YYYY-MM-DD only.
+-- Convert them to full timestamps (midnight) so they sort correctly
+-- alongside entries that carry a time of day.
+UPDATE weights
+SET date = date || ' 00:00:00'
+WHERE length(date) = 10;
+
+-- +goose Down
+-- Drop the time of day, returning to date-only values
+UPDATE weights
+SET date = substr(date, 1, 10)
+WHERE length(date) > 10;
@@ -0,0 +1,17 @@
+-- +goose Up
+-- Users sharing the database; weights.user_id holds users.id
+CREATE TABLE users (
+    id TEXT PRIMARY KEY NOT NULL,
+    created_at TEXT NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Register any users already referenced by existing entries
+INSERT INTO users (id)
+SELECT DISTINCT user_id FROM weights
+WHERE user_id IS NOT NULL AND user_id != '';
+
+CREATE INDEX idx_weights_user_id ON weights (user_id);
+
+-- +goose Down
+DROP INDEX idx_weights_user_id;
+DROP TABLE users;
@@ -0,0 +1,14 @@
+-- +goose Up
+-- One goal per user; user_id '' holds the goal used when no user is selected
+CREATE TABLE goals (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    user_id TEXT NOT NULL DEFAULT '' UNIQUE,
+    target_weight REAL NOT NULL,
+    unit TEXT NOT NULL,
+    target_date TEXT,
+    start_weight REAL NOT NULL,
+    created_at TEXT NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- +goose Down
+DROP TABLE goals;
@@ -0,0 +1,39 @@
+-- +goose Up
+-- Full-text index over entry notes. FTS4 rather than FTS5: go-sqlite3 only compiles
+-- FTS5 in with the sqlite_fts5 build tag, while FTS4 is always available. The index
+-- stores no copy of the notes (content=weights); the triggers keep it in sync.
+CREATE VIRTUAL TABLE weights_fts USING fts4(content="weights", note, tokenize=unicode61);
+
+-- +goose StatementBegin
+CREATE TRIGGER weights_fts_before_update BEFORE UPDATE ON weights BEGIN
+    DELETE FROM weights_fts WHERE docid = old.id;
+END;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TRIGGER weights_fts_before_delete BEFORE DELETE ON weights BEGIN
+    DELETE FROM weights_fts WHERE docid = old.id;
+END;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TRIGGER weights_fts_after_update AFTER UPDATE ON weights BEGIN
+    INSERT INTO weights_fts (docid, note) VALUES (new.id, new.note);
+END;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TRIGGER weights_fts_after_insert AFTER INSERT ON weights BEGIN
+    INSERT INTO weights_fts (docid, note) VALUES (new.id, new.note);
+END;
+-- +goose StatementEnd
+
+-- Index the notes of existing entries
+INSERT INTO weights_fts (weights_fts) VALUES ('rebuild');
+
+-- +goose Down
+DROP TRIGGER weights_fts_after_insert;
+DROP TRIGGER weights_fts_after_update;
+DROP TRIGGER weights_fts_before_delete;
+DROP TRIGGER weights_fts_before_update;
+DROP TABLE weights_fts;
@@ -0,0 +1,110 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/pressly/goose/v3"
+)
+
+// ConvertDatesToUTCVersion is the version of the Go migration converting dates to UTC
+const ConvertDatesToUTCVersion = 20261016130000
+
+// Date layouts of the weights and goals tables: rows are written as UTC with a zone
+// suffix; rows written before that carry no zone (full timestamps, or dates only from
+// before time-of-day support)
+const (
+	utcDateLayout       = "2006-01-02 15:04:05Z07:00"
+	legacyDateLayout    = "2006-01-02 15:04:05"
+	legacyDayOnlyLayout = "2006-01-02"
+)
+
+// dateColumns are the columns holding entry dates, which the migration rewrites
+var dateColumns = []struct{ table, column string }{
+	{table: "weights", column: "date"},
+	{table: "goals", column: "target_date"},
+}
+
+// ConvertDatesToUTC returns the migration rewriting dates stored without a zone as
+// UTC, so that they compare correctly as text with the dates written since. The
+// legacy dates are read in location, the configured time zone (TIMEZONE); rolling
+// back writes every date in location again, without a zone.
+//
+// It is a Go migration because SQLite can only convert from the machine's time zone.
+// Its source is named after this file, which is not embedded, so that db status can
+// show it like the SQL migrations.
+func ConvertDatesToUTC(location *time.Location) *goose.Migration {
+	migration := goose.NewGoMigration(ConvertDatesToUTCVersion,
+		&goose.GoFunc{RunTx: func(ctx context.Context, tx *sql.Tx) error {
+			return rewriteDates(ctx, tx, func(value string) (string, bool, error) {
+				if _, err := time.Parse(utcDateLayout, value); err == nil {
+					return "", false, nil
+				}
+				date, err := time.ParseInLocation(legacyDateLayout, value, location)
+				if err != nil {
+					date, err = time.ParseInLocation(legacyDayOnlyLayout, value, location)
+				}
+				if err != nil {
+					return "", false, err
+				}
+				return date.UTC().Format(utcDateLayout), true, nil
+			})
+		}},
+		&goose.GoFunc{RunTx: func(ctx context.Context, tx *sql.Tx) error {
+			return rewriteDates(ctx, tx, func(value string) (string, bool, error) {
+				date, err := time.Parse(utcDateLayout, value)
+				if err != nil {
+					// Already without a zone
+					return "", false, nil
+				}
+				return date.In(location).Format(legacyDateLayout), true, nil
+			})
+		}},
+	)
+	migration.Source = fmt.Sprintf("%d_convert_dates_to_utc.go", ConvertDatesToUTCVersion)
+	return migration
+}
+
+// rewriteDates replaces every date of dateColumns with the value returned by convert,
+// when it reports a change
+func rewriteDates(ctx context.Context, tx *sql.Tx, convert func(value string) (string, bool, error)) error {
+	for _, dates := range dateColumns {
+		rows, err := tx.QueryContext(ctx, fmt.Sprintf("SELECT id, %s FROM %s WHERE %s IS NOT NULL", dates.column, dates.table, dates.column))
+		if err != nil {
+			return fmt.Errorf("failed to read %s.%s: %w", dates.table, dates.column, err)
+		}
+
+		converted := make(map[int64]string)
+		for rows.Next() {
+			var id int64
+			var value string
+			if err := rows.Scan(&id, &value); err != nil {
+				rows.Close()
+				return fmt.Errorf("failed to read %s.%s: %w", dates.table, dates.column, err)
+			}
+			newValue, changed, err := convert(value)
+			if err != nil {
+				rows.Close()
+				return fmt.Errorf("invalid %s.%s '%s' in row %d: %w", dates.table, dates.column, value, id, err)
+			}
+			if changed {
+				converted[id] = newValue
+			}
+		}
+		err = rows.Err()
+		rows.Close()
+		if err != nil {
+			return fmt.Errorf("failed to read %s.%s: %w", dates.table, dates.column, err)
+		}
+
+		update := fmt.Sprintf("UPDATE %s SET %s = ? WHERE id = ?", dates.table, dates.column)
+		for id, value := range converted {
+			if _, err := tx.ExecContext(ctx, update, value, id); err != nil {
+				return fmt.Errorf("failed to update %s.%s of row %d: %w", dates.table, dates.column, id, err)
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,12 @@
+// Package migrations embeds the goose SQL migrations so the binary can create
+// and upgrade its database without the migration files on disk. Migrations that
+// need more than SQL are Go migrations, such as ConvertDatesToUTC, registered with
+// the provider in internal/db.
+package migrations
+
+import "embed"
+
+// FS holds every migration file of this directory
+//
+//go:embed *.sql
+var FS embed.FS
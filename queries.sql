@@ -1,42 +1,13 @@
 -- name: AddWeight :one
 INSERT INTO weights (
-    weight, date, unit, note
+    weight, date, unit, note, user_id
 ) VALUES (
-    ?, ?, ?, ?
+    ?, ?, ?, ?, ?
 )
 RETURNING *;
 
--- name: ListWeightsDateAsc :many
-SELECT * FROM weights
-WHERE
-    (@start_date IS NULL OR date >= @start_date)
-    AND (@end_date IS NULL OR date <= @end_date)
-ORDER BY date ASC
-LIMIT @row_limit;
-
--- name: ListWeightsDateDesc :many
-SELECT * FROM weights
-WHERE
-    (@start_date IS NULL OR date >= @start_date)
-    AND (@end_date IS NULL OR date <= @end_date)
-ORDER BY date DESC
-LIMIT @row_limit;
-
--- name: ListWeightsWeightAsc :many
-SELECT * FROM weights
-WHERE
-    (@start_date IS NULL OR date >= @start_date)
-    AND (@end_date IS NULL OR date <= @end_date)
-ORDER BY weight ASC
-LIMIT @row_limit;
-
--- name: ListWeightsWeightDesc :many
-SELECT * FROM weights
-WHERE
-    (@start_date IS NULL OR date >= @start_date)
-    AND (@end_date IS NULL OR date <= @end_date)
-ORDER BY weight DESC
-LIMIT @row_limit;
+-- Weight entries are listed with a query built at runtime (cmd/tracker/list_query.go),
+-- which supports any combination of filters and sort keys.
 
 -- name: GetWeight :one
 SELECT * FROM weights WHERE id = ?;
@@ -52,4 +23,34 @@ UPDATE weights SET
     note = ?,
     user_id = ?
 WHERE id = ?
-RETURNING *;
\ No newline at end of file
+RETURNING *;
+
+-- name: EnsureUser :exec
+INSERT INTO users (id) VALUES (?)
+ON CONFLICT (id) DO NOTHING;
+
+-- name: ListUsers :many
+SELECT * FROM users ORDER BY id;
+
+-- name: SetGoal :one
+INSERT INTO goals (
+    user_id, target_weight, unit, target_date, start_weight
+) VALUES (
+    ?, ?, ?, ?, ?
+)
+ON CONFLICT (user_id) DO UPDATE SET
+    target_weight = excluded.target_weight,
+    unit = excluded.unit,
+    target_date = excluded.target_date,
+    start_weight = excluded.start_weight,
+    created_at = CURRENT_TIMESTAMP
+RETURNING *;
+
+-- name: GetGoal :one
+SELECT * FROM goals WHERE user_id = ?;
+
+-- name: ListGoals :many
+SELECT * FROM goals ORDER BY user_id;
+
+-- name: DeleteGoal :execrows
+DELETE FROM goals WHERE user_id = ?;